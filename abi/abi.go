@@ -0,0 +1,449 @@
+package abi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrEntryNotFound is returned when an ABI has no entry of the requested name.
+var ErrEntryNotFound = errors.New("entry not found in the ABI")
+
+// EntryType is the type of an entry of a Cairo 1 ABI.
+type EntryType string
+
+const (
+	EntryTypeFunction    EntryType = "function"
+	EntryTypeConstructor EntryType = "constructor"
+	EntryTypeL1Handler   EntryType = "l1_handler"
+	EntryTypeStruct      EntryType = "struct"
+	EntryTypeEnum        EntryType = "enum"
+	EntryTypeEvent       EntryType = "event"
+	EntryTypeInterface   EntryType = "interface"
+	EntryTypeImpl        EntryType = "impl"
+)
+
+// StateMutability is the state mutability of a function.
+type StateMutability string
+
+const (
+	StateMutabilityExternal StateMutability = "external"
+	StateMutabilityView     StateMutability = "view"
+)
+
+// EventKind is the kind of an event, a struct or an enum of events.
+type EventKind string
+
+const (
+	EventKindStruct EventKind = "struct"
+	EventKindEnum   EventKind = "enum"
+)
+
+// EventMemberKind is the way the member of an event is serialized.
+type EventMemberKind string
+
+const (
+	// EventMemberKindKey is a member of a struct event serialized in the keys.
+	EventMemberKindKey EventMemberKind = "key"
+	// EventMemberKindData is a member of a struct event serialized in the data.
+	EventMemberKindData EventMemberKind = "data"
+	// EventMemberKindNested is a variant of an enum event whose name is added
+	// to the keys before the ones of the nested event.
+	EventMemberKindNested EventMemberKind = "nested"
+	// EventMemberKindFlat is a variant of an enum event serialized as the
+	// nested event alone.
+	EventMemberKindFlat EventMemberKind = "flat"
+)
+
+// ABI is the Cairo 1 ABI of a Sierra contract class, its entries being in
+// the order of the class.
+type ABI struct {
+	Entries []Entry
+}
+
+// Entry is an entry of an ABI, a *Function, *Struct, *Enum, *Event,
+// *Interface or *Impl.
+type Entry interface {
+	IsType() EntryType
+}
+
+// Param is a typed parameter: an input or an output of a function, a member
+// of a struct or a variant of an enum. The outputs of the functions have no name.
+type Param struct {
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+	// Tree the parsed Type, set by Parse
+	Tree *Type `json:"-"`
+}
+
+// Function is a function, a constructor or an L1 handler.
+type Function struct {
+	Type            EntryType       `json:"type"`
+	Name            string          `json:"name"`
+	Inputs          []Param         `json:"inputs"`
+	Outputs         []Param         `json:"outputs,omitempty"`
+	StateMutability StateMutability `json:"state_mutability,omitempty"`
+}
+
+// Struct is a struct type.
+type Struct struct {
+	Type    EntryType `json:"type"`
+	Name    string    `json:"name"`
+	Members []Param   `json:"members"`
+}
+
+// Enum is an enum type, the unit type () being the type of the variants
+// without data.
+type Enum struct {
+	Type     EntryType `json:"type"`
+	Name     string    `json:"name"`
+	Variants []Param   `json:"variants"`
+}
+
+// EventMember is a member of a struct event or a variant of an enum event.
+type EventMember struct {
+	Name string          `json:"name"`
+	Type string          `json:"type"`
+	Kind EventMemberKind `json:"kind"`
+	// Tree the parsed Type, set by Parse
+	Tree *Type `json:"-"`
+}
+
+// Event is an event, either a struct with Members or an enum with Variants.
+type Event struct {
+	Type     EntryType     `json:"type"`
+	Name     string        `json:"name"`
+	Kind     EventKind     `json:"kind"`
+	Members  []EventMember `json:"members,omitempty"`
+	Variants []EventMember `json:"variants,omitempty"`
+	// Inputs the data of the events of the compilers older than Cairo v2,
+	// which have no kind
+	Inputs []Param `json:"inputs,omitempty"`
+}
+
+// Interface is an interface and its functions.
+type Interface struct {
+	Type  EntryType  `json:"type"`
+	Name  string     `json:"name"`
+	Items []Function `json:"items"`
+}
+
+// Impl is an implementation of an interface by the contract.
+type Impl struct {
+	Type          EntryType `json:"type"`
+	Name          string    `json:"name"`
+	InterfaceName string    `json:"interface_name"`
+}
+
+// IsType returns the EntryType of the Function.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - EntryType: the EntryType
+func (f *Function) IsType() EntryType {
+	return f.Type
+}
+
+// IsType returns the EntryType of the Struct.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - EntryType: the EntryType
+func (s *Struct) IsType() EntryType {
+	return s.Type
+}
+
+// IsType returns the EntryType of the Enum.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - EntryType: the EntryType
+func (e *Enum) IsType() EntryType {
+	return e.Type
+}
+
+// IsType returns the EntryType of the Event.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - EntryType: the EntryType
+func (e *Event) IsType() EntryType {
+	return e.Type
+}
+
+// IsType returns the EntryType of the Interface.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - EntryType: the EntryType
+func (i *Interface) IsType() EntryType {
+	return i.Type
+}
+
+// IsType returns the EntryType of the Impl.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - EntryType: the EntryType
+func (i *Impl) IsType() EntryType {
+	return i.Type
+}
+
+// Parse parses a Cairo 1 ABI, as found in rpc.ContractClass.ABI, and the
+// type strings of its entries.
+//
+// Parameters:
+// - abi: The JSON of the ABI
+// Returns:
+// - *ABI: the parsed ABI
+// - error: an error if the ABI or one of its type strings is malformed
+func Parse(abi string) (*ABI, error) {
+	var parsed ABI
+	if err := json.Unmarshal([]byte(abi), &parsed); err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// UnmarshalJSON unmarshals the entries of an ABI and parses their type
+// strings. The ABI can also be a JSON string holding the entries, as in the
+// Sierra classes answered by the nodes.
+//
+// Parameters:
+// - data: The JSON data to be unmarshaled
+// Returns:
+// - error: an error if the ABI or one of its type strings is malformed
+func (a *ABI) UnmarshalJSON(data []byte) error {
+	var inner string
+	if err := json.Unmarshal(data, &inner); err == nil {
+		data = []byte(inner)
+	}
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return err
+	}
+
+	entries := make([]Entry, 0, len(raws))
+	for i, raw := range raws {
+		var header struct {
+			Type EntryType `json:"type"`
+		}
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return fmt.Errorf("entry %d: %w", i, err)
+		}
+		var entry Entry
+		switch header.Type {
+		case EntryTypeFunction, EntryTypeConstructor, EntryTypeL1Handler:
+			entry = &Function{}
+		case EntryTypeStruct:
+			entry = &Struct{}
+		case EntryTypeEnum:
+			entry = &Enum{}
+		case EntryTypeEvent:
+			entry = &Event{}
+		case EntryTypeInterface:
+			entry = &Interface{}
+		case EntryTypeImpl:
+			entry = &Impl{}
+		default:
+			return fmt.Errorf("entry %d: unknown ABI type %q", i, header.Type)
+		}
+		if err := json.Unmarshal(raw, entry); err != nil {
+			return fmt.Errorf("entry %d: %w", i, err)
+		}
+		if err := parseEntryTypes(entry); err != nil {
+			return fmt.Errorf("entry %d: %w", i, err)
+		}
+		entries = append(entries, entry)
+	}
+	a.Entries = entries
+	return nil
+}
+
+// MarshalJSON marshals the entries of the ABI as a JSON array.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: the JSON of the entries
+// - error: an error if any
+func (a ABI) MarshalJSON() ([]byte, error) {
+	if a.Entries == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(a.Entries)
+}
+
+// parseEntryTypes parses the type strings of an entry into their trees.
+func parseEntryTypes(entry Entry) error {
+	switch e := entry.(type) {
+	case *Function:
+		return parseFunctionTypes(e)
+	case *Struct:
+		return parseParamTypes(e.Name, e.Members)
+	case *Enum:
+		return parseParamTypes(e.Name, e.Variants)
+	case *Event:
+		switch e.Kind {
+		case EventKindStruct:
+			return parseEventMemberTypes(e.Name, e.Members)
+		case EventKindEnum:
+			return parseEventMemberTypes(e.Name, e.Variants)
+		case "":
+			return parseParamTypes(e.Name, e.Inputs)
+		}
+		return fmt.Errorf("%s: unknown event kind %q", e.Name, e.Kind)
+	case *Interface:
+		for i := range e.Items {
+			if err := parseFunctionTypes(&e.Items[i]); err != nil {
+				return fmt.Errorf("%s: %w", e.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// parseFunctionTypes parses the types of the inputs and outputs of a function.
+func parseFunctionTypes(f *Function) error {
+	// the constructors have no outputs, the other functions may have an empty list
+	if len(f.Outputs) == 0 {
+		f.Outputs = nil
+	}
+	if err := parseParamTypes(f.Name, f.Inputs); err != nil {
+		return err
+	}
+	return parseParamTypes(f.Name, f.Outputs)
+}
+
+// parseParamTypes parses the types of the parameters of an entry.
+func parseParamTypes(entry string, params []Param) error {
+	for i := range params {
+		tree, err := ParseType(params[i].Type)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", entry, params[i].Name, err)
+		}
+		params[i].Tree = tree
+	}
+	return nil
+}
+
+// parseEventMemberTypes parses the types of the members of an event.
+func parseEventMemberTypes(event string, members []EventMember) error {
+	for i := range members {
+		tree, err := ParseType(members[i].Type)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", event, members[i].Name, err)
+		}
+		members[i].Tree = tree
+	}
+	return nil
+}
+
+// Functions returns the functions of the ABI, the ones of its interfaces
+// included, along with its constructor and L1 handlers, in the order of the ABI.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []*Function: the functions
+func (a *ABI) Functions() []*Function {
+	var functions []*Function
+	for _, entry := range a.Entries {
+		switch e := entry.(type) {
+		case *Function:
+			functions = append(functions, e)
+		case *Interface:
+			for i := range e.Items {
+				functions = append(functions, &e.Items[i])
+			}
+		}
+	}
+	return functions
+}
+
+// Function returns the function of the given name, looked up in the
+// interfaces as well.
+//
+// Parameters:
+// - name: The name of the function
+// Returns:
+// - *Function: the function
+// - error: an error matching ErrEntryNotFound if the ABI has no such function
+func (a *ABI) Function(name string) (*Function, error) {
+	for _, function := range a.Functions() {
+		if function.Name == name {
+			return function, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: function %s", ErrEntryNotFound, name)
+}
+
+// Struct returns the struct of the given path.
+//
+// Parameters:
+// - name: The path of the struct, such as core::integer::u256
+// Returns:
+// - *Struct: the struct
+// - error: an error matching ErrEntryNotFound if the ABI has no such struct
+func (a *ABI) Struct(name string) (*Struct, error) {
+	for _, entry := range a.Entries {
+		if s, ok := entry.(*Struct); ok && s.Name == name {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: struct %s", ErrEntryNotFound, name)
+}
+
+// Enum returns the enum of the given path.
+//
+// Parameters:
+// - name: The path of the enum, such as core::bool
+// Returns:
+// - *Enum: the enum
+// - error: an error matching ErrEntryNotFound if the ABI has no such enum
+func (a *ABI) Enum(name string) (*Enum, error) {
+	for _, entry := range a.Entries {
+		if e, ok := entry.(*Enum); ok && e.Name == name {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: enum %s", ErrEntryNotFound, name)
+}
+
+// Event returns the event of the given path.
+//
+// Parameters:
+// - name: The path of the event
+// Returns:
+// - *Event: the event
+// - error: an error matching ErrEntryNotFound if the ABI has no such event
+func (a *ABI) Event(name string) (*Event, error) {
+	for _, entry := range a.Entries {
+		if e, ok := entry.(*Event); ok && e.Name == name {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: event %s", ErrEntryNotFound, name)
+}
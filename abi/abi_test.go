@@ -0,0 +1,156 @@
+package abi
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseType tests the parsing of type strings into their trees.
+func TestParseType(t *testing.T) {
+	type testSetType struct {
+		Type          string
+		ExpectedType  *Type
+		ExpectedError bool
+	}
+	felt252 := &Type{Kind: KindNamed, Name: "core::felt252"}
+	u256 := &Type{Kind: KindNamed, Name: "core::integer::u256"}
+	testSet := []testSetType{
+		{Type: "core::felt252", ExpectedType: felt252},
+		{
+			Type:         "core::array::Span::<core::integer::u256>",
+			ExpectedType: &Type{Kind: KindNamed, Name: "core::array::Span", Args: []*Type{u256}},
+		},
+		{
+			Type: "core::result::Result::<core::array::Array::<core::felt252>, core::integer::u256>",
+			ExpectedType: &Type{Kind: KindNamed, Name: "core::result::Result", Args: []*Type{
+				{Kind: KindNamed, Name: "core::array::Array", Args: []*Type{felt252}},
+				u256,
+			}},
+		},
+		{Type: "()", ExpectedType: &Type{Kind: KindTuple, Args: []*Type{}}},
+		{
+			Type:         "(core::felt252, (core::integer::u256, core::felt252))",
+			ExpectedType: &Type{Kind: KindTuple, Args: []*Type{felt252, {Kind: KindTuple, Args: []*Type{u256, felt252}}}},
+		},
+		{Type: "[core::felt252; 3]", ExpectedType: &Type{Kind: KindFixedArray, Args: []*Type{felt252}, Size: 3}},
+		{Type: "", ExpectedError: true},
+		{Type: "core::array::Array::<core::felt252", ExpectedError: true},
+		{Type: "core::array::Array::<>", ExpectedError: true},
+		{Type: "core::felt252>", ExpectedError: true},
+		{Type: "core::", ExpectedError: true},
+		{Type: "[core::felt252]", ExpectedError: true},
+		{Type: "(core::felt252 core::felt252)", ExpectedError: true},
+	}
+	for _, test := range testSet {
+		parsed, err := ParseType(test.Type)
+		if test.ExpectedError {
+			require.Error(t, err, test.Type)
+			continue
+		}
+		require.NoError(t, err, test.Type)
+		require.Equal(t, test.ExpectedType, parsed)
+		require.Equal(t, test.Type, parsed.String())
+	}
+	require.Equal(t, "Span", mustParseType(t, "core::array::Span::<core::felt252>").Base())
+}
+
+// mustParseType parses a type string, failing the test on error.
+func mustParseType(t *testing.T, s string) *Type {
+	parsed, err := ParseType(s)
+	require.NoError(t, err)
+	return parsed
+}
+
+// TestParse tests the parsing of the entries of a Cairo 1 ABI.
+func TestParse(t *testing.T) {
+	content, err := os.ReadFile("./tests/token.abi.json")
+	require.NoError(t, err)
+	abi, err := Parse(string(content))
+	require.NoError(t, err)
+	require.Len(t, abi.Entries, 13)
+
+	impl, ok := abi.Entries[0].(*Impl)
+	require.True(t, ok)
+	require.Equal(t, "token::token::IToken", impl.InterfaceName)
+
+	functions := abi.Functions()
+	names := make([]string, len(functions))
+	for i, function := range functions {
+		names[i] = function.Name
+	}
+	require.Equal(t, []string{"name", "balance_of", "transfer", "batch_approve", "constructor", "deposit"}, names)
+
+	approve, err := abi.Function("batch_approve")
+	require.NoError(t, err)
+	require.Equal(t, StateMutabilityExternal, approve.StateMutability)
+	require.Equal(t, "core::array::Span", approve.Inputs[0].Tree.Name)
+	require.Equal(t, "token::token::Allowance", approve.Inputs[0].Tree.Args[0].Name)
+	require.Equal(t, KindTuple, approve.Inputs[1].Tree.Kind)
+	require.Equal(t, KindFixedArray, approve.Inputs[2].Tree.Kind)
+	require.Equal(t, "Option", approve.Outputs[0].Tree.Base())
+
+	constructor, err := abi.Function("constructor")
+	require.NoError(t, err)
+	require.Equal(t, EntryTypeConstructor, constructor.IsType())
+	deposit, err := abi.Function("deposit")
+	require.NoError(t, err)
+	require.Equal(t, EntryTypeL1Handler, deposit.IsType())
+	_, err = abi.Function("mint")
+	require.ErrorIs(t, err, ErrEntryNotFound)
+
+	u256, err := abi.Struct("core::integer::u256")
+	require.NoError(t, err)
+	require.Equal(t, []string{"low", "high"}, []string{u256.Members[0].Name, u256.Members[1].Name})
+	boolean, err := abi.Enum("core::bool")
+	require.NoError(t, err)
+	require.Equal(t, KindTuple, boolean.Variants[1].Tree.Kind)
+
+	transfer, err := abi.Event("token::token::Transfer")
+	require.NoError(t, err)
+	require.Equal(t, EventKindStruct, transfer.Kind)
+	require.Equal(t, EventMemberKindKey, transfer.Members[0].Kind)
+	require.Equal(t, EventMemberKindData, transfer.Members[2].Kind)
+	event, err := abi.Event("token::token::Event")
+	require.NoError(t, err)
+	require.Equal(t, EventKindEnum, event.Kind)
+	require.Equal(t, EventMemberKindNested, event.Variants[0].Kind)
+	require.Equal(t, EventMemberKindFlat, event.Variants[1].Kind)
+
+	// the ABI marshals back to its entries
+	marshaled, err := json.Marshal(abi)
+	require.NoError(t, err)
+	reparsed, err := Parse(string(marshaled))
+	require.NoError(t, err)
+	require.Equal(t, abi, reparsed)
+
+	_, err = Parse(`[{"type": "function", "name": "f", "inputs": [{"name": "a", "type": "core::array::Array::<"}]}]`)
+	require.ErrorContains(t, err, "f.a")
+	_, err = Parse(`[{"type": "storage", "name": "s"}]`)
+	require.ErrorContains(t, err, "unknown ABI type")
+}
+
+// TestParseContractClass tests the parsing of the ABI of a Sierra class, held as a string.
+func TestParseContractClass(t *testing.T) {
+	content, err := os.ReadFile("./tests/hello_starknet_compiled.sierra.json")
+	require.NoError(t, err)
+	var class rpc.ContractClass
+	require.NoError(t, json.Unmarshal(content, &class))
+
+	abi, err := Parse(class.ABI)
+	require.NoError(t, err)
+	balance, err := abi.Function("get_balance")
+	require.NoError(t, err)
+	require.Equal(t, StateMutabilityView, balance.StateMutability)
+	require.Equal(t, "core::felt252", balance.Outputs[0].Tree.Name)
+
+	// the class embeds the ABI as a JSON string
+	var embedded struct {
+		ABI ABI `json:"abi"`
+	}
+	require.NoError(t, json.Unmarshal(content, &embedded))
+	require.Len(t, embedded.ABI.Entries, 3)
+}
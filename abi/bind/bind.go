@@ -0,0 +1,595 @@
+// Package bind generates the Go bindings of a contract from its ABI: typed
+// methods calling the view functions through a provider, typed methods
+// invoking the external functions through an account, the Go types of the
+// structs and enums of the ABI, and typed decoders of the events.
+package bind
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NethermindEth/starknet.go/abi"
+)
+
+// ErrUnsupportedType is matched with errors.Is by the errors of Bind for
+// the Cairo types without a Go binding.
+var ErrUnsupportedType = errors.New("unsupported type")
+
+// The Cairo types with a builtin Go binding.
+const (
+	typeBool      = "core::bool"
+	typeU128      = "core::integer::u128"
+	typeI128      = "core::integer::i128"
+	typeU256      = "core::integer::u256"
+	typeByteArray = "core::byte_array::ByteArray"
+	typeArray     = "core::array::Array"
+	typeSpan      = "core::array::Span"
+	typeOption    = "core::option::Option"
+	typeResult    = "core::result::Result"
+	typeNonZero   = "core::zeroable::NonZero"
+	typeBox       = "core::box::Box"
+)
+
+// feltTypes are the types bound to *felt.Felt.
+var feltTypes = map[string]bool{
+	"core::felt252": true,
+	"core::starknet::contract_address::ContractAddress": true,
+	"core::starknet::class_hash::ClassHash":             true,
+	"core::starknet::storage_access::StorageAddress":    true,
+	"core::starknet::eth_address::EthAddress":           true,
+	"core::bytes_31::bytes31":                           true,
+}
+
+// intTypes are the integer types bound to a Go integer.
+var intTypes = map[string]string{
+	"core::integer::u8":  "uint8",
+	"core::integer::u16": "uint16",
+	"core::integer::u32": "uint32",
+	"core::integer::u64": "uint64",
+	"core::integer::i8":  "int8",
+	"core::integer::i16": "int16",
+	"core::integer::i32": "int32",
+	"core::integer::i64": "int64",
+}
+
+// builtinTypes are the types of the ABI entries not generated, having a builtin binding.
+var builtinTypes = map[string]bool{
+	typeBool:      true,
+	typeU256:      true,
+	typeByteArray: true,
+	typeOption:    true,
+	typeResult:    true,
+}
+
+// Bind generates the Go source of the bindings of a contract.
+//
+// Parameters:
+// - contract: The ABI of the contract
+// - pkg: The name of the Go package
+// - typeName: The name of the Go type of the contract, such as Token
+// Returns:
+// - []byte: the formatted Go source
+// - error: an error if a type of the ABI has no Go binding or two entries have the same Go name
+func Bind(contract *abi.ABI, pkg, typeName string) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+	if !token.IsIdentifier(typeName) || !token.IsExported(typeName) {
+		return nil, fmt.Errorf("invalid type name %q", typeName)
+	}
+	b := binder{
+		abi:   contract,
+		names: map[string]string{},
+		used:  map[string]string{},
+		data:  tmplData{Package: pkg, Contract: typeName},
+	}
+	for _, name := range []string{typeName, "New" + typeName, "ConstructorCalldata", "ErrUnknownEvent", "DecodeEvent"} {
+		if err := b.use(name, "the bindings"); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.bindTypes(); err != nil {
+		return nil, err
+	}
+	if err := b.bindFunctions(); err != nil {
+		return nil, err
+	}
+	if err := b.bindEvents(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, b.data); err != nil {
+		return nil, err
+	}
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid generated source: %w", err)
+	}
+	return source, nil
+}
+
+// binder collects the bindings of the entries of an ABI.
+type binder struct {
+	abi *abi.ABI
+	// names the Go names of the structs, enums and struct events, keyed by path
+	names map[string]string
+	// used the entries of the generated package level names
+	used map[string]string
+	data tmplData
+}
+
+// use reserves a package level name for the entry.
+func (b *binder) use(name, entry string) error {
+	if other, ok := b.used[name]; ok {
+		return fmt.Errorf("%s and %s are both bound to %s", other, entry, name)
+	}
+	b.used[name] = entry
+	return nil
+}
+
+// bindTypes binds the structs and enums of the ABI.
+func (b *binder) bindTypes() error {
+	var structs []*abi.Struct
+	var enums []*abi.Enum
+	var paths []string
+	for _, entry := range b.abi.Entries {
+		switch e := entry.(type) {
+		case *abi.Struct:
+			if !b.isBuiltin(e.Name) {
+				structs = append(structs, e)
+				paths = append(paths, e.Name)
+			}
+		case *abi.Enum:
+			if !b.isBuiltin(e.Name) {
+				enums = append(enums, e)
+				paths = append(paths, e.Name)
+			}
+		}
+	}
+	if err := b.name(paths, ""); err != nil {
+		return err
+	}
+
+	for _, s := range structs {
+		fields, err := b.fields(s.Members)
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+		b.data.Structs = append(b.data.Structs, tmplStruct{Name: b.names[s.Name], Path: s.Name, Fields: fields})
+	}
+	for _, e := range enums {
+		enum := tmplEnum{Name: b.names[e.Name], Path: e.Name}
+		if err := b.use(enum.Name+"Variant", e.Name); err != nil {
+			return err
+		}
+		for i, variant := range e.Variants {
+			v := tmplVariant{Name: exported(variant.Name), Const: enum.Name + exported(variant.Name), Index: i}
+			if err := b.use(v.Const, e.Name+"::"+variant.Name); err != nil {
+				return err
+			}
+			if !isUnit(variant.Tree) {
+				typ, tag, err := b.goType(variant.Tree)
+				if err != nil {
+					return fmt.Errorf("%s: %s: %w", e.Name, variant.Name, err)
+				}
+				v.Field = &tmplField{Name: v.Name, Type: typ, Tag: tag}
+			}
+			enum.Variants = append(enum.Variants, v)
+		}
+		b.data.Enums = append(b.data.Enums, enum)
+	}
+	return nil
+}
+
+// isBuiltin reports whether the type of the path has a builtin binding.
+func (b *binder) isBuiltin(path string) bool {
+	t, err := abi.ParseType(path)
+	if err != nil {
+		return false
+	}
+	return builtinTypes[t.Name] || feltTypes[t.Name]
+}
+
+// name sets the Go names of the paths, made of their last segment and the
+// given suffix, prefixed by their module when they have the same last segment.
+func (b *binder) name(paths []string, suffix string) error {
+	bases := make(map[string]int, len(paths))
+	for _, path := range paths {
+		bases[typeName(path, false)]++
+	}
+	for _, path := range paths {
+		name := typeName(path, false)
+		if bases[name] > 1 {
+			name = typeName(path, true)
+		}
+		name += suffix
+		if err := b.use(name, path); err != nil {
+			return err
+		}
+		b.names[path] = name
+	}
+	return nil
+}
+
+// typeName returns the exported Go name of a type path, its last segment
+// followed by its generic arguments, prefixed by its module if asked.
+func typeName(path string, withModule bool) string {
+	t, err := abi.ParseType(path)
+	if err != nil {
+		return exported(path)
+	}
+	name := argName(t)
+	if withModule {
+		segments := strings.Split(t.Name, "::")
+		if len(segments) > 1 {
+			name = exported(segments[len(segments)-2]) + name
+		}
+	}
+	return name
+}
+
+// argName returns the part of a Go type name made of a type.
+func argName(t *abi.Type) string {
+	switch t.Kind {
+	case abi.KindTuple:
+		name := "Tuple"
+		for _, arg := range t.Args {
+			name += argName(arg)
+		}
+		return name
+	case abi.KindFixedArray:
+		return argName(t.Args[0]) + "Array" + strconv.FormatUint(t.Size, 10)
+	}
+	name := exported(t.Base())
+	for _, arg := range t.Args {
+		name += argName(arg)
+	}
+	return name
+}
+
+// fields binds the members of a struct or an event.
+func (b *binder) fields(members []abi.Param) ([]tmplField, error) {
+	fields := make([]tmplField, 0, len(members))
+	names := make(map[string]bool, len(members))
+	for _, member := range members {
+		typ, tag, err := b.goType(member.Tree)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", member.Name, err)
+		}
+		name := exported(member.Name)
+		if names[name] {
+			return nil, fmt.Errorf("%s: duplicate field %s", member.Name, name)
+		}
+		names[name] = true
+		fields = append(fields, tmplField{Name: name, Type: typ, Tag: tag})
+	}
+	return fields, nil
+}
+
+// goType returns the Go type bound to a Cairo type, and the cairo struct tag
+// of the fields of this type.
+func (b *binder) goType(t *abi.Type) (string, string, error) {
+	switch t.Kind {
+	case abi.KindTuple:
+		fields := make([]tmplField, len(t.Args))
+		for i, arg := range t.Args {
+			typ, tag, err := b.goType(arg)
+			if err != nil {
+				return "", "", err
+			}
+			fields[i] = tmplField{Name: fmt.Sprintf("F%d", i), Type: typ, Tag: tag}
+		}
+		return structType(fields), "", nil
+	case abi.KindFixedArray:
+		typ, tag, err := b.goType(t.Args[0])
+		if err != nil {
+			return "", "", err
+		}
+		return fmt.Sprintf("[%d]%s", t.Size, typ), tag, nil
+	}
+
+	if feltTypes[t.Name] {
+		return "*felt.Felt", "", nil
+	}
+	if typ, ok := intTypes[t.Name]; ok {
+		return typ, "", nil
+	}
+	switch t.Name {
+	case typeArray, typeSpan, typeOption, typeNonZero, typeBox:
+		if len(t.Args) == 0 {
+			return "", "", fmt.Errorf("%w: %s without its generic argument", ErrUnsupportedType, t)
+		}
+	}
+	switch t.Name {
+	case typeBool:
+		return "bool", "", nil
+	case typeU128:
+		return "*big.Int", "", nil
+	case typeI128:
+		return "*big.Int", "i128", nil
+	case typeU256:
+		return "*big.Int", "u256", nil
+	case typeByteArray:
+		return "string", "", nil
+	case typeArray, typeSpan:
+		typ, tag, err := b.goType(t.Args[0])
+		if err != nil {
+			return "", "", err
+		}
+		return "[]" + typ, tag, nil
+	case typeOption:
+		typ, tag, err := b.goType(t.Args[0])
+		if err != nil {
+			return "", "", err
+		}
+		return "*" + typ, tag, nil
+	case typeNonZero, typeBox:
+		return b.goType(t.Args[0])
+	}
+	if name, ok := b.names[t.String()]; ok {
+		return name, "", nil
+	}
+	return "", "", fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+}
+
+// bindFunctions binds the functions of the ABI.
+func (b *binder) bindFunctions() error {
+	methods := map[string]string{"Address": "the bindings", "Provider": "the bindings"}
+	useMethod := func(name, function string) error {
+		if other, ok := methods[name]; ok {
+			return fmt.Errorf("%s and %s are both bound to the method %s", other, function, name)
+		}
+		methods[name] = function
+		return nil
+	}
+
+	for _, function := range b.abi.Functions() {
+		f := tmplFunction{Contract: b.data.Contract, Name: exported(function.Name), Selector: function.Name}
+		params := map[string]bool{}
+		for _, input := range function.Inputs {
+			typ, tag, err := b.goType(input.Tree)
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", function.Name, input.Name, err)
+			}
+			param := unexported(input.Name)
+			if token.IsKeyword(param) || reservedParams[param] {
+				param += "Arg"
+			}
+			if params[param] {
+				return fmt.Errorf("%s: %s: duplicate parameter %s", function.Name, input.Name, param)
+			}
+			params[param] = true
+			f.Inputs = append(f.Inputs, tmplParam{
+				Param: param,
+				Field: tmplField{Name: exported(input.Name), Type: typ, Tag: tag},
+			})
+		}
+
+		switch function.Type {
+		case abi.EntryTypeConstructor:
+			b.data.Constructor = &f
+			continue
+		case abi.EntryTypeL1Handler:
+			// the L1 handlers are called by the messages of L1 only
+			continue
+		}
+		for i, output := range function.Outputs {
+			typ, tag, err := b.goType(output.Tree)
+			if err != nil {
+				return fmt.Errorf("%s: output %d: %w", function.Name, i, err)
+			}
+			f.Outputs = append(f.Outputs, tmplField{Name: fmt.Sprintf("Out%d", i), Type: typ, Tag: tag})
+		}
+		if err := useMethod(f.Name+"Call", function.Name); err != nil {
+			return err
+		}
+		if err := useMethod(f.Name, function.Name); err != nil {
+			return err
+		}
+		if function.StateMutability == abi.StateMutabilityView {
+			b.data.Views = append(b.data.Views, f)
+		} else {
+			b.data.Externals = append(b.data.Externals, f)
+		}
+	}
+	return nil
+}
+
+// reservedParams are the names used by the bodies of the generated methods,
+// renamed when they are the name of a parameter.
+var reservedParams = map[string]bool{
+	"c": true, "ctx": true, "blockID": true, "acc": true, "bounds": true,
+	"call": true, "calldata": true, "result": true, "output": true, "err": true,
+	"cairo": true, "felt": true, "fmt": true, "rpc": true, "utils": true,
+}
+
+// bindEvents binds the struct events of the ABI, with the keys selecting
+// them as variants of the enum events.
+func (b *binder) bindEvents() error {
+	var structs []*abi.Event
+	var paths []string
+	nested := map[string]bool{}
+	for _, entry := range b.abi.Entries {
+		e, ok := entry.(*abi.Event)
+		if !ok {
+			continue
+		}
+		if e.Kind == abi.EventKindEnum {
+			for _, variant := range e.Variants {
+				nested[variant.Type] = true
+			}
+			continue
+		}
+		structs = append(structs, e)
+		paths = append(paths, e.Name)
+	}
+	if err := b.name(paths, "Event"); err != nil {
+		return err
+	}
+
+	// the selectors of the keys of the struct events, found from the root enum events
+	keys := map[string][]string{}
+	for _, entry := range b.abi.Entries {
+		if e, ok := entry.(*abi.Event); ok && e.Kind == abi.EventKindEnum && !nested[e.Name] {
+			if err := b.eventKeys(e, nil, keys, 0); err != nil {
+				return err
+			}
+		}
+	}
+
+	groups := map[string][]tmplEvent{}
+	for _, e := range structs {
+		event := tmplEvent{Name: b.names[e.Name], Path: e.Name, Keys: keys[e.Name]}
+		if event.Keys == nil {
+			// the events of the compilers older than Cairo v2 and the events
+			// missing from the enums are selected by their name
+			event.Keys = []string{typeName(e.Name, false)}
+		}
+		if err := b.use(event.Name+"Keys", e.Name); err != nil {
+			return err
+		}
+		if err := b.use("Decode"+event.Name, e.Name); err != nil {
+			return err
+		}
+		members := e.Members
+		if e.Kind == "" {
+			for _, input := range e.Inputs {
+				members = append(members, abi.EventMember{Name: input.Name, Type: input.Type, Kind: abi.EventMemberKindData, Tree: input.Tree})
+			}
+		}
+		names := map[string]bool{}
+		for _, member := range members {
+			typ, tag, err := b.goType(member.Tree)
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", e.Name, member.Name, err)
+			}
+			field := tmplField{Name: exported(member.Name), Type: typ, Tag: tag}
+			if names[field.Name] {
+				return fmt.Errorf("%s: %s: duplicate field %s", e.Name, member.Name, field.Name)
+			}
+			names[field.Name] = true
+			switch member.Kind {
+			case abi.EventMemberKindKey:
+				event.KeyFields = append(event.KeyFields, field)
+			case abi.EventMemberKindData:
+				event.DataFields = append(event.DataFields, field)
+			default:
+				return fmt.Errorf("%s: %s: %w: %s member", e.Name, member.Name, ErrUnsupportedType, member.Kind)
+			}
+			event.Fields = append(event.Fields, field)
+		}
+		b.data.Events = append(b.data.Events, event)
+		groups[event.Keys[0]] = append(groups[event.Keys[0]], event)
+	}
+
+	for selector, events := range groups {
+		// the events with more keys are tried first, their keys starting with the keys of the others
+		sort.SliceStable(events, func(i, j int) bool {
+			return len(events[i].Keys) > len(events[j].Keys)
+		})
+		group := tmplEventGroup{Selector: selector}
+		for _, event := range events {
+			group.Events = append(group.Events, event.Name)
+		}
+		b.data.EventGroups = append(b.data.EventGroups, group)
+	}
+	sort.Slice(b.data.EventGroups, func(i, j int) bool {
+		return b.data.EventGroups[i].Selector < b.data.EventGroups[j].Selector
+	})
+	return nil
+}
+
+// maxEventDepth bounds the nesting of the enum events.
+const maxEventDepth = 16
+
+// eventKeys sets the keys of the struct events reached from the enum event,
+// whose variants are selected by the prefix keys.
+func (b *binder) eventKeys(e *abi.Event, prefix []string, keys map[string][]string, depth int) error {
+	if depth > maxEventDepth {
+		return fmt.Errorf("%s: events nested too deep", e.Name)
+	}
+	for _, variant := range e.Variants {
+		variantKeys := prefix
+		switch variant.Kind {
+		case abi.EventMemberKindNested:
+			variantKeys = append(append([]string{}, prefix...), variant.Name)
+		case abi.EventMemberKindFlat:
+		default:
+			return fmt.Errorf("%s: %s: %w: %s variant", e.Name, variant.Name, ErrUnsupportedType, variant.Kind)
+		}
+		inner, err := b.abi.Event(variant.Type)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", e.Name, variant.Name, err)
+		}
+		if inner.Kind == abi.EventKindEnum {
+			if err := b.eventKeys(inner, variantKeys, keys, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+		if variant.Kind == abi.EventMemberKindFlat {
+			// a flat struct event is selected by its name
+			variantKeys = append(append([]string{}, prefix...), typeName(inner.Name, false))
+		}
+		if _, ok := keys[inner.Name]; !ok {
+			keys[inner.Name] = variantKeys
+		}
+	}
+	return nil
+}
+
+// isUnit reports whether the type is the unit type ().
+func isUnit(t *abi.Type) bool {
+	return t.Kind == abi.KindTuple && len(t.Args) == 0
+}
+
+// exported returns the exported Go name of a Cairo name in snake case.
+func exported(name string) string {
+	var sb strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if word == "" {
+			continue
+		}
+		sb.WriteString(strings.ToUpper(word[:1]))
+		sb.WriteString(word[1:])
+	}
+	if sb.Len() == 0 {
+		return "X"
+	}
+	return sb.String()
+}
+
+// unexported returns the unexported Go name of a Cairo name in snake case.
+func unexported(name string) string {
+	name = exported(name)
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// structType returns the Go type of a struct of the fields.
+func structType(fields []tmplField) string {
+	if len(fields) == 0 {
+		return "struct{}"
+	}
+	var sb strings.Builder
+	sb.WriteString("struct {\n")
+	for _, field := range fields {
+		fmt.Fprintf(&sb, "%s %s %s\n", field.Name, field.Type, structTag(field.Tag))
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// structTag returns the struct tag of a field with the cairo tag.
+func structTag(tag string) string {
+	if tag == "" {
+		return ""
+	}
+	return fmt.Sprintf("`cairo:%q`", tag)
+}
@@ -0,0 +1,229 @@
+package bind_test
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/abi"
+	"github.com/NethermindEth/starknet.go/abi/bind"
+	"github.com/NethermindEth/starknet.go/abi/bind/tests/token"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/cairo"
+	"github.com/NethermindEth/starknet.go/mocks"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// update regenerates the golden bindings instead of comparing them.
+var update = flag.Bool("update", false, "update the golden bindings")
+
+const goldenPath = "tests/token/token.go"
+
+// TestBind tests the generated bindings against the golden bindings, which
+// are compiled with the module.
+func TestBind(t *testing.T) {
+	content, err := os.ReadFile("tests/token.abi.json")
+	require.NoError(t, err)
+	contract, err := abi.Parse(string(content))
+	require.NoError(t, err)
+
+	source, err := bind.Bind(contract, "token", "Token")
+	require.NoError(t, err)
+	if *update {
+		require.NoError(t, os.WriteFile(goldenPath, source, 0o644))
+	}
+	golden, err := os.ReadFile(goldenPath)
+	require.NoError(t, err)
+	require.Equal(t, string(golden), string(source))
+}
+
+// TestBindErrors tests the ABIs without Go bindings.
+func TestBindErrors(t *testing.T) {
+	type testSetType struct {
+		ABI           string
+		Package       string
+		ExpectedError error
+	}
+	testSet := []testSetType{
+		{
+			ABI:           `[{"type": "function", "name": "get", "inputs": [], "outputs": [{"type": "core::result::Result::<core::felt252, core::felt252>"}], "state_mutability": "view"}]`,
+			Package:       "token",
+			ExpectedError: bind.ErrUnsupportedType,
+		},
+		{
+			ABI:           `[{"type": "function", "name": "get", "inputs": [{"name": "x", "type": "core::array::Array"}], "outputs": [], "state_mutability": "view"}]`,
+			Package:       "token",
+			ExpectedError: bind.ErrUnsupportedType,
+		},
+		{
+			ABI:           `[{"type": "function", "name": "get", "inputs": [{"name": "x", "type": "token::Unknown"}], "outputs": [], "state_mutability": "external"}]`,
+			Package:       "token",
+			ExpectedError: bind.ErrUnsupportedType,
+		},
+		{
+			ABI: `[
+				{"type": "function", "name": "get", "inputs": [], "outputs": [], "state_mutability": "view"},
+				{"type": "function", "name": "get_call", "inputs": [], "outputs": [], "state_mutability": "view"}
+			]`,
+			Package: "token",
+		},
+		{
+			ABI: `[
+				{"type": "struct", "name": "a::Token", "members": []}
+			]`,
+			Package: "token",
+		},
+		{
+			ABI:     `[]`,
+			Package: "not a package",
+		},
+	}
+	for _, test := range testSet {
+		contract, err := abi.Parse(test.ABI)
+		require.NoError(t, err)
+		_, err = bind.Bind(contract, test.Package, "Token")
+		require.Error(t, err, test.ABI)
+		if test.ExpectedError != nil {
+			require.ErrorIs(t, err, test.ExpectedError)
+		}
+	}
+}
+
+// TestBindings tests the golden bindings of the token.
+func TestBindings(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+	mockRpcProvider := mocks.NewMockRpcProvider(mockCtrl)
+
+	address := utils.TestHexToFelt(t, "0x1234")
+	holder := utils.TestHexToFelt(t, "0x4a")
+	blockID := rpc.WithBlockTag("latest")
+	mockRpcProvider.EXPECT().Call(context.Background(), rpc.FunctionCall{
+		ContractAddress:    address,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("balance_of"),
+		Calldata:           []*felt.Felt{holder},
+	}, blockID).Return(utils.TestHexArrToFelt(t, []string{"0x5", "0x1"}), nil)
+
+	contract := token.NewToken(address, mockRpcProvider)
+	balance, err := contract.BalanceOf(context.Background(), blockID, holder)
+	require.NoError(t, err)
+	expectedBalance, _ := new(big.Int).SetString("0x100000000000000000000000000000005", 0)
+	require.Equal(t, expectedBalance, balance)
+
+	call, err := contract.SetStatusCall(token.Status{Variant: token.StatusActive, Active: big.NewInt(7)})
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexArrToFelt(t, []string{"0x0", "0x7", "0x0"}), call.Calldata)
+
+	var status token.Status
+	require.NoError(t, cairo.Unmarshal(call.Calldata, &status))
+	require.Equal(t, token.Status{Variant: token.StatusActive, Active: big.NewInt(7)}, status)
+	require.NoError(t, cairo.Unmarshal(utils.TestHexArrToFelt(t, []string{"0x1"}), &status))
+	require.Equal(t, token.Status{Variant: token.StatusPaused}, status)
+	require.ErrorIs(t, cairo.Unmarshal(utils.TestHexArrToFelt(t, []string{"0x2"}), &status), cairo.ErrInvalidData)
+
+	calldata, err := token.ConstructorCalldata("T", big.NewInt(1))
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexArrToFelt(t, []string{"0x0", "0x54", "0x1", "0x1", "0x0"}), calldata)
+
+	// the i128 are signed
+	minusFive := new(felt.Felt).Sub(&felt.Zero, new(felt.Felt).SetUint64(5))
+	mockRpcProvider.EXPECT().Call(context.Background(), rpc.FunctionCall{
+		ContractAddress:    address,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("balance_change"),
+		Calldata:           []*felt.Felt{holder},
+	}, blockID).Return([]*felt.Felt{minusFive}, nil)
+	change, err := contract.BalanceChange(context.Background(), blockID, holder)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(-5), change)
+	call, err = contract.AdjustCall(holder, big.NewInt(-5))
+	require.NoError(t, err)
+	require.Equal(t, []*felt.Felt{holder, minusFive}, call.Calldata)
+
+	// the invokes are v3 transactions signed by the account
+	ks, pub, _ := account.GetRandomKeys()
+	mockRpcProvider.EXPECT().ChainID(context.Background()).Return("SN_SEPOLIA", nil)
+	acc, err := account.NewAccount(mockRpcProvider, utils.TestHexToFelt(t, "0x5"), pub.String(), ks, 2)
+	require.NoError(t, err)
+	bounds := rpc.ResourceBoundsMapping{
+		L1Gas:     rpc.ResourceBounds{MaxAmount: "0x10", MaxPricePerUnit: "0x20"},
+		L2Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		L1DataGas: &rpc.ResourceBounds{MaxAmount: "0x30", MaxPricePerUnit: "0x40"},
+	}
+	mockRpcProvider.EXPECT().Nonce(context.Background(), blockID, acc.AccountAddress).Return(new(felt.Felt).SetUint64(3), nil)
+	mockRpcProvider.EXPECT().AddInvokeTransaction(context.Background(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, tx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+			invoke, ok := tx.(rpc.BroadcastInvokev3Txn)
+			require.True(t, ok, "%T", tx)
+			require.Equal(t, rpc.TransactionV3, invoke.Version)
+			require.Equal(t, bounds, invoke.ResourceBounds)
+			require.Equal(t, new(felt.Felt).SetUint64(3), invoke.Nonce)
+			require.Len(t, invoke.Signature, 2)
+			txHash, err := acc.TransactionHashInvoke(invoke.InvokeTxnV3)
+			require.NoError(t, err)
+			return &rpc.AddInvokeTransactionResponse{TransactionHash: txHash}, nil
+		})
+	response, err := contract.Adjust(context.Background(), acc, bounds, holder, big.NewInt(-5))
+	require.NoError(t, err)
+	require.NotNil(t, response.TransactionHash)
+}
+
+// TestDecodeEvent tests the decoding of the events of the token.
+func TestDecodeEvent(t *testing.T) {
+	type testSetType struct {
+		Event         rpc.Event
+		ExpectedEvent any
+		ExpectedError error
+	}
+	testSet := []testSetType{
+		{
+			Event: rpc.Event{
+				Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("Transfer"), utils.TestHexToFelt(t, "0x1"), utils.TestHexToFelt(t, "0x2")},
+				Data: utils.TestHexArrToFelt(t, []string{"0x3", "0x0"}),
+			},
+			ExpectedEvent: &token.TransferEvent{From: utils.TestHexToFelt(t, "0x1"), To: utils.TestHexToFelt(t, "0x2"), Value: big.NewInt(3)},
+		},
+		{
+			Event: rpc.Event{
+				Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("OwnershipTransferred")},
+				Data: utils.TestHexArrToFelt(t, []string{"0x4"}),
+			},
+			ExpectedEvent: &token.OwnershipTransferredEvent{NewOwner: utils.TestHexToFelt(t, "0x4")},
+		},
+		{
+			Event: rpc.Event{
+				Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("Transfer"), utils.TestHexToFelt(t, "0x1")},
+				Data: utils.TestHexArrToFelt(t, []string{"0x3", "0x0"}),
+			},
+			ExpectedError: cairo.ErrInvalidData,
+		},
+		{
+			Event: rpc.Event{
+				Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("Approval")},
+			},
+			ExpectedError: token.ErrUnknownEvent,
+		},
+		{
+			Event:         rpc.Event{},
+			ExpectedError: token.ErrUnknownEvent,
+		},
+	}
+	for _, test := range testSet {
+		event, err := token.DecodeEvent(test.Event)
+		if test.ExpectedError != nil {
+			require.True(t, errors.Is(err, test.ExpectedError), err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedEvent, event)
+	}
+
+	_, err := token.DecodeTransferEvent(rpc.Event{Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("OwnershipTransferred")}})
+	require.ErrorIs(t, err, token.ErrUnknownEvent)
+}
@@ -0,0 +1,419 @@
+package bind
+
+import (
+	"strings"
+	"text/template"
+)
+
+// tmplData is the data of the template of the bindings.
+type tmplData struct {
+	Package     string
+	Contract    string
+	Structs     []tmplStruct
+	Enums       []tmplEnum
+	Constructor *tmplFunction
+	Views       []tmplFunction
+	Externals   []tmplFunction
+	Events      []tmplEvent
+	EventGroups []tmplEventGroup
+}
+
+// tmplField is a field of a generated struct.
+type tmplField struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+// tmplStruct is the binding of a struct.
+type tmplStruct struct {
+	Name   string
+	Path   string
+	Fields []tmplField
+}
+
+// tmplEnum is the binding of an enum.
+type tmplEnum struct {
+	Name     string
+	Path     string
+	Variants []tmplVariant
+}
+
+// tmplVariant is a variant of an enum, its Field being nil when it has no data.
+type tmplVariant struct {
+	Name  string
+	Const string
+	Index int
+	Field *tmplField
+}
+
+// tmplParam is an input of a function.
+type tmplParam struct {
+	Param string
+	Field tmplField
+}
+
+// tmplFunction is the binding of a function.
+type tmplFunction struct {
+	Contract string
+	Name     string
+	Selector string
+	Inputs   []tmplParam
+	Outputs  []tmplField
+}
+
+// tmplEvent is the binding of a struct event, selected by the selectors of
+// the names in Keys.
+type tmplEvent struct {
+	Name       string
+	Path       string
+	Keys       []string
+	Fields     []tmplField
+	KeyFields  []tmplField
+	DataFields []tmplField
+}
+
+// tmplEventGroup is the events whose first key is the selector of the same name.
+type tmplEventGroup struct {
+	Selector string
+	Events   []string
+}
+
+var tmpl = template.Must(template.New("bindings").Funcs(template.FuncMap{
+	"tag":    structTag,
+	"struct": structType,
+	"inputs": func(inputs []tmplParam) []tmplField {
+		fields := make([]tmplField, len(inputs))
+		for i, input := range inputs {
+			fields[i] = input.Field
+		}
+		return fields
+	},
+	"params": func(inputs []tmplParam) string {
+		params := make([]string, len(inputs))
+		for i, input := range inputs {
+			params[i] = input.Param + " " + input.Field.Type
+		}
+		return strings.Join(params, ", ")
+	},
+	"args": func(inputs []tmplParam) string {
+		args := make([]string, len(inputs))
+		for i, input := range inputs {
+			args[i] = input.Param
+		}
+		return strings.Join(args, ", ")
+	},
+	"results": func(outputs []tmplField) string {
+		if len(outputs) == 0 {
+			return "error"
+		}
+		results := make([]string, len(outputs))
+		for i, output := range outputs {
+			results[i] = "out" + output.Name[len("Out"):] + " " + output.Type
+		}
+		return "(" + strings.Join(results, ", ") + ", err error)"
+	},
+	"zeros": func(outputs []tmplField) string {
+		var zeros strings.Builder
+		for _, output := range outputs {
+			zeros.WriteString("out" + output.Name[len("Out"):] + ", ")
+		}
+		return zeros.String()
+	},
+}).Parse(bindingsTemplate))
+
+const bindingsTemplate = `// Code generated by abigen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/cairo"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// Reference the imports not used by every contract.
+var (
+	_ = big.NewInt
+	_ = cairo.Marshal
+	_ = utils.GetSelectorFromNameFelt
+)
+
+// ErrUnknownEvent is matched with errors.Is by the errors of the event
+// decoders for the events of another type.
+var ErrUnknownEvent = errors.New("unknown event")
+{{range .Structs}}
+// {{.Name}} is the struct {{.Path}}.
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} {{tag .Tag}}
+{{- end}}
+}
+{{end}}
+{{- range $enum := .Enums}}
+// {{.Name}} is the enum {{.Path}}, Variant being the variant set and the
+// field of the same name its data.
+type {{.Name}} struct {
+	Variant {{.Name}}Variant
+{{- range .Variants}}{{if .Field}}
+	{{.Field.Name}} {{.Field.Type}} {{tag .Field.Tag}}
+{{- end}}{{end}}
+}
+
+// {{.Name}}Variant is a variant of the enum {{.Path}}.
+type {{.Name}}Variant uint64
+
+// The variants of the enum {{.Path}}.
+const (
+{{- range .Variants}}
+	{{.Const}} {{$enum.Name}}Variant = {{.Index}}
+{{- end}}
+)
+
+// MarshalCairo serializes the variant of the enum and its data.
+func (e {{.Name}}) MarshalCairo() ([]*felt.Felt, error) {
+	var value any
+	switch e.Variant {
+{{- range .Variants}}
+	case {{.Const}}:
+{{- if .Field}}
+		value = struct {
+			Value {{.Field.Type}} {{tag .Field.Tag}}
+		}{e.{{.Field.Name}}}
+{{- else}}
+		value = struct{}{}
+{{- end}}
+{{- end}}
+	default:
+		return nil, fmt.Errorf("%w: variant %d of {{.Path}}", cairo.ErrInvalidValue, e.Variant)
+	}
+	data, err := cairo.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*felt.Felt{new(felt.Felt).SetUint64(uint64(e.Variant))}, data...), nil
+}
+
+// UnmarshalCairo deserializes the variant of the enum and its data.
+func (e *{{.Name}}) UnmarshalCairo(data []*felt.Felt) (int, error) {
+	var index uint64
+	n, err := cairo.UnmarshalPrefix(data, &index)
+	if err != nil {
+		return 0, err
+	}
+	switch {{.Name}}Variant(index) {
+{{- range .Variants}}
+	case {{.Const}}:
+{{- if .Field}}
+		var value struct {
+			Value {{.Field.Type}} {{tag .Field.Tag}}
+		}
+		read, err := cairo.UnmarshalPrefix(data[n:], &value)
+		if err != nil {
+			return 0, fmt.Errorf("{{.Name}}: %w", err)
+		}
+		*e = {{$enum.Name}}{Variant: {{.Const}}, {{.Field.Name}}: value.Value}
+		return n + read, nil
+{{- else}}
+		*e = {{$enum.Name}}{Variant: {{.Const}}}
+		return n, nil
+{{- end}}
+{{- end}}
+	}
+	return 0, fmt.Errorf("%w: variant %d of {{.Path}}", cairo.ErrInvalidData, index)
+}
+{{end}}
+// {{.Contract}} is a binding of a deployed contract.
+type {{.Contract}} struct {
+	// Address the address of the contract
+	Address *felt.Felt
+	// Provider the provider calling the view functions
+	Provider rpc.RpcProvider
+}
+
+// New{{.Contract}} returns a binding of the contract deployed at the address.
+func New{{.Contract}}(address *felt.Felt, provider rpc.RpcProvider) *{{.Contract}} {
+	return &{{.Contract}}{Address: address, Provider: provider}
+}
+{{with .Constructor}}
+// ConstructorCalldata returns the calldata of the constructor, to deploy the contract.
+func ConstructorCalldata({{params .Inputs}}) ([]*felt.Felt, error) {
+	calldata, err := cairo.Marshal({{struct (inputs .Inputs)}}{ {{- args .Inputs -}} })
+	if err != nil {
+		return nil, fmt.Errorf("constructor: %w", err)
+	}
+	return calldata, nil
+}
+{{end}}
+{{- range $function := .Views}}{{template "call" $function}}
+// {{.Name}} calls the view function {{.Selector}}.
+func (c *{{$.Contract}}) {{.Name}}(ctx context.Context, blockID rpc.BlockID{{range .Inputs}}, {{.Param}} {{.Field.Type}}{{end}}) {{results .Outputs}} {
+	call, err := c.{{.Name}}Call({{args .Inputs}})
+	if err != nil {
+		return {{zeros .Outputs}}err
+	}
+	result, err := c.Provider.Call(ctx, call, blockID)
+	if err != nil {
+		return {{zeros .Outputs}}err
+	}
+	var output {{struct .Outputs}}
+	if err := cairo.Unmarshal(result, &output); err != nil {
+		return {{zeros .Outputs}}fmt.Errorf("{{.Selector}}: %w", err)
+	}
+	return {{range .Outputs}}output.{{.Name}}, {{end}}nil
+}
+{{end}}
+{{- range $function := .Externals}}{{template "call" $function}}
+// {{.Name}} invokes the external function {{.Selector}} from the account.
+func (c *{{$.Contract}}) {{.Name}}(ctx context.Context, acc *account.Account, bounds rpc.ResourceBoundsMapping{{range .Inputs}}, {{.Param}} {{.Field.Type}}{{end}}) (*rpc.AddInvokeTransactionResponse, error) {
+	call, err := c.{{.Name}}Call({{args .Inputs}})
+	if err != nil {
+		return nil, err
+	}
+	return invoke(ctx, acc, bounds, call)
+}
+{{end}}
+// invoke signs and sends a v3 invoke transaction of the calls from the
+// account, paying the fee in STRK within the resource bounds.
+func invoke(ctx context.Context, acc *account.Account, bounds rpc.ResourceBoundsMapping, calls ...rpc.FunctionCall) (*rpc.AddInvokeTransactionResponse, error) {
+	nonce, err := acc.Nonce(ctx, rpc.WithBlockTag("latest"), acc.AccountAddress)
+	if err != nil {
+		return nil, err
+	}
+	tx := rpc.BroadcastInvokev3Txn{
+		InvokeTxnV3: rpc.InvokeTxnV3{
+			Type:                  rpc.TransactionType_Invoke,
+			SenderAddress:         acc.AccountAddress,
+			Version:               rpc.TransactionV3,
+			Nonce:                 nonce,
+			ResourceBounds:        bounds,
+			Tip:                   "0x0",
+			PayMasterData:         []*felt.Felt{},
+			AccountDeploymentData: []*felt.Felt{},
+			NonceDataMode:         rpc.DAModeL1,
+			FeeMode:               rpc.DAModeL1,
+		},
+	}
+	if tx.Calldata, err = acc.FmtCalldata(calls); err != nil {
+		return nil, err
+	}
+	txHash, err := acc.TransactionHashInvoke(tx.InvokeTxnV3)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Signature, err = acc.Sign(ctx, txHash); err != nil {
+		return nil, err
+	}
+	return acc.AddInvokeTransaction(ctx, tx)
+}
+{{range .Events}}
+// {{.Name}} is the event {{.Path}}.
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} {{tag .Tag}}
+{{- end}}
+}
+
+// {{.Name}}Keys are the first keys of the event {{.Path}}, the selectors of its variants.
+var {{.Name}}Keys = []*felt.Felt{
+{{- range .Keys}}
+	utils.GetSelectorFromNameFelt("{{.}}"),
+{{- end}}
+}
+
+// Decode{{.Name}} decodes the event {{.Path}}.
+func Decode{{.Name}}(event rpc.Event) (*{{.Name}}, error) {
+	if !hasKeys(event.Keys, {{.Name}}Keys) {
+		return nil, fmt.Errorf("%w: not a {{.Path}}", ErrUnknownEvent)
+	}
+	var keys {{struct .KeyFields}}
+	if err := cairo.Unmarshal(event.Keys[len({{.Name}}Keys):], &keys); err != nil {
+		return nil, fmt.Errorf("{{.Path}} keys: %w", err)
+	}
+	var data {{struct .DataFields}}
+	if err := cairo.Unmarshal(event.Data, &data); err != nil {
+		return nil, fmt.Errorf("{{.Path}} data: %w", err)
+	}
+	return &{{.Name}}{
+{{- range .KeyFields}}
+		{{.Name}}: keys.{{.Name}},
+{{- end}}
+{{- range .DataFields}}
+		{{.Name}}: data.{{.Name}},
+{{- end}}
+	}, nil
+}
+{{end}}
+// eventDecoder is the decoder of the events starting with the keys.
+type eventDecoder struct {
+	keys   []*felt.Felt
+	decode func(rpc.Event) (any, error)
+}
+
+// eventDecoders are the decoders of the events, keyed by the selector of their first key.
+var eventDecoders = map[felt.Felt][]eventDecoder{
+{{- range .EventGroups}}
+	*utils.GetSelectorFromNameFelt("{{.Selector}}"): {
+{{- range .Events}}
+		{ {{- .}}Keys, decodeAs(Decode{{.}})},
+{{- end}}
+	},
+{{- end}}
+}
+
+// DecodeEvent decodes an event of the contract into a pointer to its Go type.
+func DecodeEvent(event rpc.Event) (any, error) {
+	if len(event.Keys) > 0 && event.Keys[0] != nil {
+		for _, decoder := range eventDecoders[*event.Keys[0]] {
+			if hasKeys(event.Keys, decoder.keys) {
+				return decoder.decode(event)
+			}
+		}
+	}
+	return nil, ErrUnknownEvent
+}
+
+// decodeAs returns the decoder of the events of a type as a decoder of any event.
+func decodeAs[T any](decode func(rpc.Event) (*T, error)) func(rpc.Event) (any, error) {
+	return func(event rpc.Event) (any, error) {
+		value, err := decode(event)
+		if err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+}
+
+// hasKeys reports whether the keys start with the prefix.
+func hasKeys(keys, prefix []*felt.Felt) bool {
+	if len(keys) < len(prefix) {
+		return false
+	}
+	for i, key := range prefix {
+		if keys[i] == nil || !keys[i].Equal(key) {
+			return false
+		}
+	}
+	return true
+}
+{{define "call"}}
+// {{.Name}}Call returns the call of the function {{.Selector}}.
+func (c *{{.Contract}}) {{.Name}}Call({{params .Inputs}}) (rpc.FunctionCall, error) {
+	calldata, err := cairo.Marshal({{struct (inputs .Inputs)}}{ {{- args .Inputs -}} })
+	if err != nil {
+		return rpc.FunctionCall{}, fmt.Errorf("{{.Selector}}: %w", err)
+	}
+	return rpc.FunctionCall{
+		ContractAddress:    c.Address,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("{{.Selector}}"),
+		Calldata:           calldata,
+	}, nil
+}
+{{end}}`
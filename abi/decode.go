@@ -0,0 +1,335 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// maxEmptyElems bounds the array elements without felts, such as the unit
+// elements of [(); N], that a decoding may hold.
+const maxEmptyElems = 1 << 16
+
+// decoder reads the values serialized in felts.
+type decoder struct {
+	abi  *ABI
+	data []*felt.Felt
+	pos  int
+	// empty the array elements read without a felt so far
+	empty int
+}
+
+// DecodeResult deserializes the result of a call of a function, as returned
+// by Provider.Call, into Go values, one per output of the function. The
+// values are decoded as follows:
+//   - felt252, addresses, class hashes and bytes31 to *felt.Felt
+//   - the integers, u256 included, to *big.Int
+//   - bool to bool and ByteArray to string
+//   - Array, Span, tuples and fixed-size arrays to []any
+//   - structs to map[string]any keyed by member name
+//   - enums to Variant, Option to nil for None or the value for Some
+//
+// Parameters:
+// - function: The name of the function
+// - result: The felts returned by the call
+// Returns:
+// - []any: the values of the outputs
+// - error: an error matching ErrInvalidData, naming the path of the invalid output
+func (a *ABI) DecodeResult(function string, result []*felt.Felt) ([]any, error) {
+	f, err := a.Function(function)
+	if err != nil {
+		return nil, err
+	}
+	d := decoder{abi: a, data: result}
+	values := make([]any, len(f.Outputs))
+	for i, output := range f.Outputs {
+		values[i], err = d.decode(output.Tree, fmt.Sprintf("output %d", i))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", function, err)
+		}
+	}
+	if err := d.end(); err != nil {
+		return nil, fmt.Errorf("%s: %w", function, err)
+	}
+	return values, nil
+}
+
+// DecodeCalldata deserializes the calldata of a function into its
+// arguments, keyed by input name, as DecodeResult does for the outputs.
+//
+// Parameters:
+// - function: The name of the function, constructor for the constructor
+// - calldata: The calldata of the function
+// Returns:
+// - map[string]any: the arguments keyed by input name
+// - error: an error matching ErrInvalidData, naming the path of the invalid argument
+func (a *ABI) DecodeCalldata(function string, calldata []*felt.Felt) (map[string]any, error) {
+	f, err := a.Function(function)
+	if err != nil {
+		return nil, err
+	}
+	d := decoder{abi: a, data: calldata}
+	args := make(map[string]any, len(f.Inputs))
+	for _, input := range f.Inputs {
+		args[input.Name], err = d.decode(input.Tree, input.Name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", function, err)
+		}
+	}
+	if err := d.end(); err != nil {
+		return nil, fmt.Errorf("%s: %w", function, err)
+	}
+	return args, nil
+}
+
+// Decode deserializes a value of a Cairo type, as DecodeResult does for the
+// outputs of a function. All the felts must be read.
+//
+// Parameters:
+// - typ: The Cairo type string, such as core::array::Span::<core::integer::u256>
+// - data: The serialized value
+// Returns:
+// - any: the value
+// - error: an error matching ErrInvalidData, naming the path of the invalid value
+func (a *ABI) Decode(typ string, data []*felt.Felt) (any, error) {
+	tree, err := ParseType(typ)
+	if err != nil {
+		return nil, err
+	}
+	d := decoder{abi: a, data: data}
+	value, err := d.decode(tree, "value")
+	if err != nil {
+		return nil, err
+	}
+	if err := d.end(); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// decode reads a value of the type.
+func (d *decoder) decode(t *Type, path string) (any, error) {
+	if missingArgs(t) {
+		return nil, invalidData(path, "%s without its generic arguments", t)
+	}
+	switch t.Kind {
+	case KindTuple:
+		if len(t.Args) == 0 {
+			return nil, nil
+		}
+		elems := make([]any, len(t.Args))
+		for i, arg := range t.Args {
+			elem, err := d.decode(arg, fmt.Sprintf("%s.%d", path, i))
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = elem
+		}
+		return elems, nil
+	case KindFixedArray:
+		return d.decodeElems(t.Args[0], t.Size, path)
+	}
+
+	if bits, ok := feltTypes[t.Name]; ok {
+		f, err := d.next(path)
+		if err != nil {
+			return nil, err
+		}
+		if f.BigInt(new(big.Int)).BitLen() > bits {
+			return nil, invalidData(path, "%s overflows %s", f, t.Name)
+		}
+		return f, nil
+	}
+	if bits, ok := intTypes[t.Name]; ok {
+		f, err := d.next(path)
+		if err != nil {
+			return nil, err
+		}
+		n := f.BigInt(new(big.Int))
+		if bits < 0 && n.Cmp(new(big.Int).Rsh(fieldPrime, 1)) > 0 {
+			n.Sub(n, fieldPrime)
+		}
+		if !fitsInt(n, bits) {
+			return nil, invalidData(path, "%s overflows %s", f, t.Name)
+		}
+		return n, nil
+	}
+
+	switch t.Name {
+	case typeBool:
+		f, err := d.next(path)
+		if err != nil {
+			return nil, err
+		}
+		if f.Cmp(new(felt.Felt).SetUint64(1)) > 0 {
+			return nil, invalidData(path, "%s isn't a bool", f)
+		}
+		return !f.IsZero(), nil
+	case typeU256:
+		low, err := d.next(path + ".low")
+		if err != nil {
+			return nil, err
+		}
+		high, err := d.next(path + ".high")
+		if err != nil {
+			return nil, err
+		}
+		if low.BigInt(new(big.Int)).BitLen() > 128 || high.BigInt(new(big.Int)).BitLen() > 128 {
+			return nil, invalidData(path, "the parts %s and %s overflow u128", low, high)
+		}
+		n := high.BigInt(new(big.Int))
+		return n.Lsh(n, 128).Or(n, low.BigInt(new(big.Int))), nil
+	case typeByteArray:
+		count, err := d.length(path)
+		if err != nil {
+			return nil, err
+		}
+		if count > uint64(len(d.data)-d.pos) {
+			return nil, invalidData(path, "%d words for %d felts", count, len(d.data)-d.pos)
+		}
+		start := d.pos - 1
+		d.pos += int(count)
+		if _, err := d.next(path + ".pending_word"); err != nil {
+			return nil, err
+		}
+		if _, err := d.next(path + ".pending_word_len"); err != nil {
+			return nil, err
+		}
+		s, err := utils.ByteArrFeltToStringExact(d.data[start:d.pos])
+		if err != nil {
+			return nil, invalidData(path, "%v", err)
+		}
+		return s, nil
+	case typeArray, typeSpan:
+		length, err := d.length(path)
+		if err != nil {
+			return nil, err
+		}
+		// the length is bounded by the felts left, the elements of the unit type included
+		if length > uint64(len(d.data)-d.pos) {
+			return nil, invalidData(path, "%d elements for %d felts", length, len(d.data)-d.pos)
+		}
+		return d.decodeElems(t.Args[0], length, path)
+	case typeNonZero, typeBox:
+		return d.decode(t.Args[0], path)
+	case typeOption:
+		index, err := d.length(path)
+		if err != nil {
+			return nil, err
+		}
+		switch index {
+		case 0:
+			return d.decode(t.Args[0], path+".Some")
+		case 1:
+			return nil, nil
+		}
+		return nil, invalidData(path, "variant %d of %s", index, t.Name)
+	case typeResult:
+		index, err := d.length(path)
+		if err != nil {
+			return nil, err
+		}
+		switch index {
+		case 0:
+			value, err := d.decode(t.Args[0], path+".Ok")
+			return Variant{Name: "Ok", Value: value}, err
+		case 1:
+			value, err := d.decode(t.Args[1], path+".Err")
+			return Variant{Name: "Err", Value: value}, err
+		}
+		return nil, invalidData(path, "variant %d of %s", index, t.Name)
+	}
+
+	name := t.String()
+	if s, err := d.abi.Struct(name); err == nil {
+		fields := make(map[string]any, len(s.Members))
+		for _, member := range s.Members {
+			if fields[member.Name], err = d.decode(member.Tree, path+"."+member.Name); err != nil {
+				return nil, err
+			}
+		}
+		return fields, nil
+	}
+	if e, err := d.abi.Enum(name); err == nil {
+		index, err := d.length(path)
+		if err != nil {
+			return nil, err
+		}
+		if index >= uint64(len(e.Variants)) {
+			return nil, invalidData(path, "variant %d of %s", index, name)
+		}
+		variant := e.Variants[index]
+		value, err := d.decode(variant.Tree, path+"."+variant.Name)
+		if err != nil {
+			return nil, err
+		}
+		return Variant{Name: variant.Name, Value: value}, nil
+	}
+	return nil, fmt.Errorf("%s: %w %s", path, ErrUnknownType, name)
+}
+
+// decodeElems reads the elements of an array.
+func (d *decoder) decodeElems(t *Type, length uint64, path string) ([]any, error) {
+	// each element takes a felt at least, except for the types without data
+	// such as the unit type, whose elements are bounded by maxEmptyElems
+	left := uint64(len(d.data) - d.pos)
+	if length > left+uint64(maxEmptyElems-d.empty) {
+		return nil, invalidData(path, "%d elements for %d felts", length, left)
+	}
+	elems := make([]any, length)
+	for i := range elems {
+		start := d.pos
+		elem, err := d.decode(t, fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+		if d.pos == start {
+			if d.empty++; d.empty > maxEmptyElems {
+				return nil, invalidData(path, "more than %d elements without felts", maxEmptyElems)
+			}
+		}
+		elems[i] = elem
+	}
+	return elems, nil
+}
+
+// next reads a felt.
+func (d *decoder) next(path string) (*felt.Felt, error) {
+	if d.pos == len(d.data) {
+		return nil, invalidData(path, "missing felt at offset %d", d.pos)
+	}
+	f := d.data[d.pos]
+	if f == nil {
+		return nil, invalidData(path, "nil felt at offset %d", d.pos)
+	}
+	d.pos++
+	return f, nil
+}
+
+// length reads a felt holding a length or a variant index.
+func (d *decoder) length(path string) (uint64, error) {
+	f, err := d.next(path)
+	if err != nil {
+		return 0, err
+	}
+	n := f.BigInt(new(big.Int))
+	if !n.IsUint64() {
+		return 0, invalidData(path, "%s isn't a length", f)
+	}
+	return n.Uint64(), nil
+}
+
+// end returns an error if some felts were not read.
+func (d *decoder) end() error {
+	if d.pos != len(d.data) {
+		return fmt.Errorf("%w: %d felts left over", ErrInvalidData, len(d.data)-d.pos)
+	}
+	return nil
+}
+
+// invalidData returns an error matching ErrInvalidData at the path.
+func invalidData(path, format string, args ...any) error {
+	return fmt.Errorf("%s: %w: %s", path, ErrInvalidData, fmt.Sprintf(format, args...))
+}
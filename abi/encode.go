@@ -0,0 +1,428 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	// ErrInvalidValue is matched with errors.Is by the errors of the encoding
+	// when a Go value doesn't fit its Cairo type.
+	ErrInvalidValue = errors.New("invalid value")
+	// ErrInvalidData is matched with errors.Is by the errors of the decoding
+	// when the felts don't hold a value of the Cairo type.
+	ErrInvalidData = errors.New("invalid data")
+	// ErrUnknownType is matched with errors.Is by the errors of the encoding
+	// and decoding of a type that is neither a core type nor an entry of the ABI.
+	ErrUnknownType = errors.New("unknown type")
+)
+
+// Variant is a variant of a Cairo enum and its value, nil for the variants
+// of the unit type.
+type Variant struct {
+	Name  string
+	Value any
+}
+
+// The core types serialized without an ABI entry.
+const (
+	typeFelt252         = "core::felt252"
+	typeContractAddress = "core::starknet::contract_address::ContractAddress"
+	typeClassHash       = "core::starknet::class_hash::ClassHash"
+	typeStorageAddress  = "core::starknet::storage_access::StorageAddress"
+	typeEthAddress      = "core::starknet::eth_address::EthAddress"
+	typeBytes31         = "core::bytes_31::bytes31"
+	typeBool            = "core::bool"
+	typeU256            = "core::integer::u256"
+	typeByteArray       = "core::byte_array::ByteArray"
+	typeArray           = "core::array::Array"
+	typeSpan            = "core::array::Span"
+	typeOption          = "core::option::Option"
+	typeResult          = "core::result::Result"
+	typeNonZero         = "core::zeroable::NonZero"
+	typeBox             = "core::box::Box"
+)
+
+// feltTypes are the types serialized as a single felt, and the number of bits of their values.
+var feltTypes = map[string]int{
+	typeFelt252:         252,
+	typeContractAddress: 251,
+	typeClassHash:       251,
+	typeStorageAddress:  251,
+	typeEthAddress:      160,
+	typeBytes31:         248,
+}
+
+// intTypes are the integer types serialized as a single felt, and the number
+// of bits of their values, negative for the signed ones.
+var intTypes = map[string]int{
+	"core::integer::u8":   8,
+	"core::integer::u16":  16,
+	"core::integer::u32":  32,
+	"core::integer::u64":  64,
+	"core::integer::u128": 128,
+	"core::integer::i8":   -8,
+	"core::integer::i16":  -16,
+	"core::integer::i32":  -32,
+	"core::integer::i64":  -64,
+	"core::integer::i128": -128,
+}
+
+// genericTypes are the generic core types and their number of generic arguments.
+var genericTypes = map[string]int{
+	typeArray:   1,
+	typeSpan:    1,
+	typeOption:  1,
+	typeResult:  2,
+	typeNonZero: 1,
+	typeBox:     1,
+}
+
+// missingArgs reports whether the type has fewer generic arguments, or
+// elements for a fixed-size array, than its kind needs.
+func missingArgs(t *Type) bool {
+	if t.Kind == KindFixedArray {
+		return len(t.Args) == 0
+	}
+	return t.Kind == KindNamed && len(t.Args) < genericTypes[t.Name]
+}
+
+// fieldPrime is the prime of the felts.
+var fieldPrime, _ = new(big.Int).SetString("800000000000011000000000000000000000000000000000000000000000001", 16)
+
+// EncodeCalldata serializes the arguments of a function into its calldata,
+// following the Serde layout of their Cairo types. The arguments are
+// converted as follows:
+//   - felt252, addresses, class hashes, bytes31 and the integers from
+//     *felt.Felt, *big.Int, the Go integers, or strings of numbers in
+//     decimal or 0x-prefixed hexadecimal
+//   - u256 split into its low and high parts
+//   - bool from bool
+//   - ByteArray from string or []byte
+//   - Array and Span from slices and arrays, tuples and fixed-size arrays
+//     from slices and arrays of their size
+//   - structs from map[string]any keyed by member name
+//   - enums from Variant, Option from nil for None or the value for Some
+//
+// Parameters:
+// - function: The name of the function, constructor for the constructor
+// - args: The arguments of the function, in order
+// Returns:
+// - []*felt.Felt: the calldata
+// - error: an error matching ErrInvalidValue, naming the path of the invalid argument
+func (a *ABI) EncodeCalldata(function string, args ...any) ([]*felt.Felt, error) {
+	f, err := a.Function(function)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != len(f.Inputs) {
+		return nil, fmt.Errorf("%s: %w: %d arguments for %d inputs", function, ErrInvalidValue, len(args), len(f.Inputs))
+	}
+	calldata := []*felt.Felt{}
+	for i, input := range f.Inputs {
+		calldata, err = a.encode(calldata, input.Tree, args[i], input.Name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", function, err)
+		}
+	}
+	return calldata, nil
+}
+
+// FunctionCall builds the call of a function of a contract, its calldata
+// being encoded by EncodeCalldata.
+//
+// Parameters:
+// - contractAddress: The address of the contract
+// - function: The name of the function
+// - args: The arguments of the function, in order
+// Returns:
+// - rpc.FunctionCall: the call
+// - error: an error matching ErrInvalidValue, naming the path of the invalid argument
+func (a *ABI) FunctionCall(contractAddress *felt.Felt, function string, args ...any) (rpc.FunctionCall, error) {
+	calldata, err := a.EncodeCalldata(function, args...)
+	if err != nil {
+		return rpc.FunctionCall{}, err
+	}
+	return rpc.FunctionCall{
+		ContractAddress:    contractAddress,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(function),
+		Calldata:           calldata,
+	}, nil
+}
+
+// Encode serializes a Go value of a Cairo type, as EncodeCalldata does for
+// the arguments of a function.
+//
+// Parameters:
+// - typ: The Cairo type string, such as core::array::Span::<core::integer::u256>
+// - value: The value to serialize
+// Returns:
+// - []*felt.Felt: the serialized value
+// - error: an error matching ErrInvalidValue, naming the path of the invalid value
+func (a *ABI) Encode(typ string, value any) ([]*felt.Felt, error) {
+	tree, err := ParseType(typ)
+	if err != nil {
+		return nil, err
+	}
+	return a.encode([]*felt.Felt{}, tree, value, "value")
+}
+
+// encode appends the serialization of the value to out.
+func (a *ABI) encode(out []*felt.Felt, t *Type, value any, path string) ([]*felt.Felt, error) {
+	if missingArgs(t) {
+		return nil, invalidValue(path, "%s without its generic arguments", t)
+	}
+	switch t.Kind {
+	case KindTuple:
+		if len(t.Args) == 0 {
+			return out, nil
+		}
+		elems, err := sequence(value, path, len(t.Args))
+		if err != nil {
+			return nil, err
+		}
+		for i, elem := range elems {
+			if out, err = a.encode(out, t.Args[i], elem, fmt.Sprintf("%s.%d", path, i)); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case KindFixedArray:
+		elems, err := sequence(value, path, int(t.Size))
+		if err != nil {
+			return nil, err
+		}
+		for i, elem := range elems {
+			if out, err = a.encode(out, t.Args[0], elem, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+
+	if bits, ok := feltTypes[t.Name]; ok {
+		n, err := toBigInt(value, path)
+		if err != nil {
+			return nil, err
+		}
+		if n.Sign() < 0 || n.BitLen() > bits || n.Cmp(fieldPrime) >= 0 {
+			return nil, invalidValue(path, "%s overflows %s", n, t.Name)
+		}
+		return append(out, utils.BigIntToFelt(n)), nil
+	}
+	if bits, ok := intTypes[t.Name]; ok {
+		n, err := toBigInt(value, path)
+		if err != nil {
+			return nil, err
+		}
+		if !fitsInt(n, bits) {
+			return nil, invalidValue(path, "%s overflows %s", n, t.Name)
+		}
+		if n.Sign() < 0 {
+			n = new(big.Int).Add(n, fieldPrime)
+		}
+		return append(out, utils.BigIntToFelt(n)), nil
+	}
+
+	switch t.Name {
+	case typeBool:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, invalidValue(path, "%T for %s", value, t.Name)
+		}
+		if b {
+			return append(out, new(felt.Felt).SetUint64(1)), nil
+		}
+		return append(out, new(felt.Felt)), nil
+	case typeU256:
+		if _, ok := value.(map[string]any); ok {
+			break
+		}
+		n, err := toBigInt(value, path)
+		if err != nil {
+			return nil, err
+		}
+		if n.Sign() < 0 || n.BitLen() > 256 {
+			return nil, invalidValue(path, "%s overflows %s", n, t.Name)
+		}
+		low := new(big.Int).And(n, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)))
+		high := new(big.Int).Rsh(n, 128)
+		return append(out, utils.BigIntToFelt(low), utils.BigIntToFelt(high)), nil
+	case typeByteArray:
+		var s string
+		switch v := value.(type) {
+		case string:
+			s = v
+		case []byte:
+			s = string(v)
+		default:
+			return nil, invalidValue(path, "%T for %s", value, t.Name)
+		}
+		words, err := utils.StringToByteArrFelt(s)
+		if err != nil {
+			return nil, invalidValue(path, "%v", err)
+		}
+		return append(out, words...), nil
+	case typeArray, typeSpan:
+		elems, err := sequence(value, path, -1)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, new(felt.Felt).SetUint64(uint64(len(elems))))
+		for i, elem := range elems {
+			if out, err = a.encode(out, t.Args[0], elem, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case typeNonZero, typeBox:
+		return a.encode(out, t.Args[0], value, path)
+	case typeOption:
+		if _, ok := value.(Variant); ok {
+			break
+		}
+		if isNil(value) {
+			return append(out, new(felt.Felt).SetUint64(1)), nil
+		}
+		return a.encode(append(out, new(felt.Felt)), t.Args[0], value, path+".Some")
+	case typeResult:
+		v, ok := value.(Variant)
+		if !ok {
+			return nil, invalidValue(path, "%T for %s, expected a Variant", value, t.Name)
+		}
+		switch v.Name {
+		case "Ok":
+			return a.encode(append(out, new(felt.Felt)), t.Args[0], v.Value, path+".Ok")
+		case "Err":
+			return a.encode(append(out, new(felt.Felt).SetUint64(1)), t.Args[1], v.Value, path+".Err")
+		}
+		return nil, invalidValue(path, "unknown variant %q of %s", v.Name, t.Name)
+	}
+
+	name := t.String()
+	if s, err := a.Struct(name); err == nil {
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return nil, invalidValue(path, "%T for struct %s, expected a map[string]any", value, name)
+		}
+		if len(fields) > len(s.Members) {
+			for field := range fields {
+				if !hasMember(s.Members, field) {
+					return nil, invalidValue(path, "unknown member %q of %s", field, name)
+				}
+			}
+		}
+		for _, member := range s.Members {
+			field, ok := fields[member.Name]
+			if !ok {
+				return nil, invalidValue(path, "missing member %q of %s", member.Name, name)
+			}
+			if out, err = a.encode(out, member.Tree, field, path+"."+member.Name); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	if e, err := a.Enum(name); err == nil {
+		v, ok := value.(Variant)
+		if !ok {
+			return nil, invalidValue(path, "%T for enum %s, expected a Variant", value, name)
+		}
+		for i, variant := range e.Variants {
+			if variant.Name == v.Name {
+				return a.encode(append(out, new(felt.Felt).SetUint64(uint64(i))), variant.Tree, v.Value, path+"."+v.Name)
+			}
+		}
+		return nil, invalidValue(path, "unknown variant %q of %s", v.Name, name)
+	}
+	return nil, fmt.Errorf("%s: %w %s", path, ErrUnknownType, name)
+}
+
+// invalidValue returns an error matching ErrInvalidValue at the path.
+func invalidValue(path, format string, args ...any) error {
+	return fmt.Errorf("%s: %w: %s", path, ErrInvalidValue, fmt.Sprintf(format, args...))
+}
+
+// toBigInt converts a Go number to a big integer.
+func toBigInt(value any, path string) (*big.Int, error) {
+	switch v := value.(type) {
+	case *felt.Felt:
+		if v != nil {
+			return v.BigInt(new(big.Int)), nil
+		}
+	case felt.Felt:
+		return v.BigInt(new(big.Int)), nil
+	case *big.Int:
+		if v != nil {
+			return new(big.Int).Set(v), nil
+		}
+	case big.Int:
+		return new(big.Int).Set(&v), nil
+	case string:
+		n, ok := new(big.Int).SetString(v, 0)
+		if !ok {
+			return nil, invalidValue(path, "%q isn't a number", v)
+		}
+		return n, nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	}
+	return nil, invalidValue(path, "%T isn't a number", value)
+}
+
+// fitsInt reports whether the integer fits in the bits of an integer type,
+// negative for the signed ones.
+func fitsInt(n *big.Int, bits int) bool {
+	if bits > 0 {
+		return n.Sign() >= 0 && n.BitLen() <= bits
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(-bits-1))
+	return n.Cmp(new(big.Int).Neg(limit)) >= 0 && n.Cmp(limit) < 0
+}
+
+// sequence returns the elements of a slice or an array, of the given length
+// unless it is negative.
+func sequence(value any, path string, length int) ([]any, error) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, invalidValue(path, "%T isn't a slice", value)
+	}
+	if length >= 0 && rv.Len() != length {
+		return nil, invalidValue(path, "%d elements, expected %d", rv.Len(), length)
+	}
+	elems := make([]any, rv.Len())
+	for i := range elems {
+		elems[i] = rv.Index(i).Interface()
+	}
+	return elems, nil
+}
+
+// isNil reports whether the value is nil or a nil pointer.
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
+// hasMember reports whether the members have the given name.
+func hasMember(members []Param, name string) bool {
+	for _, member := range members {
+		if member.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,248 @@
+package abi
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenABI parses the ABI of the test token.
+func tokenABI(t *testing.T) *ABI {
+	content, err := os.ReadFile("./tests/token.abi.json")
+	require.NoError(t, err)
+	abi, err := Parse(string(content))
+	require.NoError(t, err)
+	return abi
+}
+
+// TestEncodeCalldata tests the serialization of the arguments of functions and their deserialization.
+func TestEncodeCalldata(t *testing.T) {
+	type testSetType struct {
+		Function         string
+		Args             []any
+		ExpectedCalldata []string
+		ExpectedArgs     map[string]any
+	}
+	u128Plus5, _ := new(big.Int).SetString("0x100000000000000000000000000000005", 0)
+	spender := utils.TestHexToFelt(t, "0x4a")
+	testSet := []testSetType{
+		{
+			Function:         "transfer",
+			Args:             []any{"0x123", u128Plus5},
+			ExpectedCalldata: []string{"0x123", "0x5", "0x1"},
+			ExpectedArgs:     map[string]any{"recipient": utils.TestHexToFelt(t, "0x123"), "amount": u128Plus5},
+		},
+		{
+			Function:         "constructor",
+			Args:             []any{"hello", 10},
+			ExpectedCalldata: []string{"0x0", "0x68656c6c6f", "0x5", "0xa", "0x0"},
+			ExpectedArgs:     map[string]any{"name": "hello", "supply": big.NewInt(10)},
+		},
+		{
+			Function: "batch_approve",
+			Args: []any{
+				[]map[string]any{{"spender": spender, "amount": uint64(7)}, {"spender": "74", "amount": map[string]any{"low": 1, "high": 2}}},
+				[]any{new(felt.Felt).SetUint64(9), true},
+				[4]uint8{1, 2, 3, 255},
+			},
+			ExpectedCalldata: []string{"0x2", "0x4a", "0x7", "0x0", "0x4a", "0x1", "0x2", "0x9", "0x1", "0x1", "0x2", "0x3", "0xff"},
+			ExpectedArgs: map[string]any{
+				"allowances": []any{
+					map[string]any{"spender": spender, "amount": big.NewInt(7)},
+					map[string]any{"spender": spender, "amount": new(big.Int).Add(new(big.Int).Lsh(big.NewInt(2), 128), big.NewInt(1))},
+				},
+				"pair":  []any{utils.TestHexToFelt(t, "0x9"), true},
+				"fixed": []any{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(255)},
+			},
+		},
+	}
+	abi := tokenABI(t)
+	for _, test := range testSet {
+		calldata, err := abi.EncodeCalldata(test.Function, test.Args...)
+		require.NoError(t, err)
+		require.Equal(t, utils.TestHexArrToFelt(t, test.ExpectedCalldata), calldata)
+
+		args, err := abi.DecodeCalldata(test.Function, calldata)
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedArgs, args)
+	}
+
+	call, err := abi.FunctionCall(spender, "transfer", "0x123", 5)
+	require.NoError(t, err)
+	require.Equal(t, utils.GetSelectorFromNameFelt("transfer"), call.EntryPointSelector)
+	require.Equal(t, utils.TestHexArrToFelt(t, []string{"0x123", "0x5", "0x0"}), call.Calldata)
+}
+
+// TestEncodeErrors tests that the errors name the path of the invalid values.
+func TestEncodeErrors(t *testing.T) {
+	type testSetType struct {
+		Function      string
+		Args          []any
+		ExpectedError string
+	}
+	testSet := []testSetType{
+		{Function: "transfer", Args: []any{"0x123"}, ExpectedError: "transfer: invalid value: 1 arguments for 2 inputs"},
+		{Function: "transfer", Args: []any{"0x123", -1}, ExpectedError: "transfer: amount: invalid value: -1 overflows core::integer::u256"},
+		{Function: "transfer", Args: []any{"recipient", 1}, ExpectedError: `transfer: recipient: invalid value: "recipient" isn't a number`},
+		{
+			Function:      "batch_approve",
+			Args:          []any{[]any{map[string]any{"spender": 1, "amount": map[string]any{"low": 1, "high": new(big.Int).Lsh(big.NewInt(1), 128)}}}, []any{1, true}, []int{0, 0, 0, 0}},
+			ExpectedError: "batch_approve: allowances[0].amount.high: invalid value: 340282366920938463463374607431768211456 overflows core::integer::u128",
+		},
+		{
+			Function:      "batch_approve",
+			Args:          []any{[]any{map[string]any{"spender": 1}}, []any{1, true}, []int{0, 0, 0, 0}},
+			ExpectedError: `batch_approve: allowances[0]: invalid value: missing member "amount" of token::token::Allowance`,
+		},
+		{
+			Function:      "batch_approve",
+			Args:          []any{[]any{}, []any{1, 1}, []int{0, 0, 0, 0}},
+			ExpectedError: "batch_approve: pair.1: invalid value: int for core::bool",
+		},
+		{
+			Function:      "batch_approve",
+			Args:          []any{[]any{}, []any{1, true}, []int{0, 0, 256, 0}},
+			ExpectedError: "batch_approve: fixed[2]: invalid value: 256 overflows core::integer::u8",
+		},
+		{
+			Function:      "batch_approve",
+			Args:          []any{[]any{}, []any{1, true}, []int{0}},
+			ExpectedError: "batch_approve: fixed: invalid value: 1 elements, expected 4",
+		},
+	}
+	abi := tokenABI(t)
+	for _, test := range testSet {
+		_, err := abi.EncodeCalldata(test.Function, test.Args...)
+		require.ErrorIs(t, err, ErrInvalidValue)
+		require.EqualError(t, err, test.ExpectedError)
+	}
+	_, err := abi.EncodeCalldata("mint")
+	require.ErrorIs(t, err, ErrEntryNotFound)
+	_, err = abi.Encode("token::token::Unknown", 1)
+	require.ErrorIs(t, err, ErrUnknownType)
+}
+
+// TestDecodeResult tests the deserialization of the results of calls.
+func TestDecodeResult(t *testing.T) {
+	type testSetType struct {
+		Function       string
+		Result         []string
+		ExpectedValues []any
+		ExpectedError  string
+	}
+	testSet := []testSetType{
+		{Function: "balance_of", Result: []string{"0x5", "0x1"}, ExpectedValues: []any{new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(5))}},
+		{Function: "transfer", Result: []string{"0x1"}, ExpectedValues: []any{true}},
+		{
+			Function:       "name",
+			Result:         []string{"0x1", "0x4c6f6e6720737472696e672c206d6f7265207468616e203331206368617261", "0x63746572732e", "0x6"},
+			ExpectedValues: []any{"Long string, more than 31 characters."},
+		},
+		{
+			Function:       "batch_approve",
+			Result:         []string{"0x0", "0x4a", "0x7", "0x0"},
+			ExpectedValues: []any{map[string]any{"spender": utils.TestHexToFelt(t, "0x4a"), "amount": big.NewInt(7)}},
+		},
+		{Function: "batch_approve", Result: []string{"0x1"}, ExpectedValues: []any{nil}},
+		{Function: "batch_approve", Result: []string{"0x0", "0x4a", "0x7"}, ExpectedError: "batch_approve: output 0.Some.amount.high: invalid data: missing felt at offset 3"},
+		{Function: "batch_approve", Result: []string{"0x2"}, ExpectedError: "batch_approve: output 0: invalid data: variant 2 of core::option::Option"},
+		{Function: "transfer", Result: []string{"0x2"}, ExpectedError: "transfer: output 0: invalid data: 0x2 isn't a bool"},
+		{Function: "transfer", Result: []string{"0x1", "0x1"}, ExpectedError: "transfer: invalid data: 1 felts left over"},
+		{Function: "name", Result: []string{"0x5", "0x1", "0x1"}, ExpectedError: "name: output 0: invalid data: 5 words for 2 felts"},
+	}
+	abi := tokenABI(t)
+	for _, test := range testSet {
+		values, err := abi.DecodeResult(test.Function, utils.TestHexArrToFelt(t, test.Result))
+		if test.ExpectedError != "" {
+			require.ErrorIs(t, err, ErrInvalidData)
+			require.EqualError(t, err, test.ExpectedError)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedValues, values)
+	}
+}
+
+// TestEncodeTypes tests the round trip of the values of types outside of functions.
+func TestEncodeTypes(t *testing.T) {
+	type testSetType struct {
+		Type          string
+		Value         any
+		ExpectedData  []string
+		ExpectedValue any
+	}
+	minusOne := new(big.Int).Sub(fieldPrime, big.NewInt(1))
+	testSet := []testSetType{
+		{Type: "core::integer::i8", Value: -1, ExpectedData: []string{utils.BigToHex(minusOne)}, ExpectedValue: big.NewInt(-1)},
+		{Type: "core::integer::i128", Value: 5, ExpectedData: []string{"0x5"}, ExpectedValue: big.NewInt(5)},
+		{Type: "()", Value: nil, ExpectedData: []string{}, ExpectedValue: nil},
+		{
+			Type:          "core::result::Result::<core::felt252, core::byte_array::ByteArray>",
+			Value:         Variant{Name: "Err", Value: []byte("no")},
+			ExpectedData:  []string{"0x1", "0x0", "0x6e6f", "0x2"},
+			ExpectedValue: Variant{Name: "Err", Value: "no"},
+		},
+		{
+			Type:          "core::option::Option::<token::token::Allowance>",
+			Value:         Variant{Name: "None"},
+			ExpectedData:  []string{"0x1"},
+			ExpectedValue: nil,
+		},
+		{
+			Type:          "core::bool",
+			Value:         false,
+			ExpectedData:  []string{"0x0"},
+			ExpectedValue: false,
+		},
+		{
+			Type:          "core::array::Span::<(core::zeroable::NonZero::<core::felt252>, core::integer::u32)>",
+			Value:         [][]any{{1, 2}},
+			ExpectedData:  []string{"0x1", "0x1", "0x2"},
+			ExpectedValue: []any{[]any{utils.TestHexToFelt(t, "0x1"), big.NewInt(2)}},
+		},
+	}
+	abi := tokenABI(t)
+	for _, test := range testSet {
+		data, err := abi.Encode(test.Type, test.Value)
+		require.NoError(t, err)
+		require.Equal(t, utils.TestHexArrToFelt(t, test.ExpectedData), data)
+
+		value, err := abi.Decode(test.Type, data)
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedValue, value)
+	}
+
+	_, err := abi.Encode("core::integer::i8", 128)
+	require.ErrorIs(t, err, ErrInvalidValue)
+	_, err = abi.Decode("core::integer::i8", utils.TestHexArrToFelt(t, []string{"0x80"}))
+	require.ErrorIs(t, err, ErrInvalidData)
+
+	// the generic types without their arguments
+	for _, typ := range []string{"core::array::Array", "core::array::Span", "core::option::Option", "core::zeroable::NonZero", "core::box::Box"} {
+		_, err = abi.Encode(typ, []any{})
+		require.ErrorIs(t, err, ErrInvalidValue, typ)
+		_, err = abi.Decode(typ, utils.TestHexArrToFelt(t, []string{"0x0"}))
+		require.ErrorIs(t, err, ErrInvalidData, typ)
+	}
+	_, err = abi.Decode("core::result::Result::<core::felt252>", utils.TestHexArrToFelt(t, []string{"0x1", "0x0"}))
+	require.ErrorIs(t, err, ErrInvalidData)
+
+	// a length larger than the data, the unit type included
+	_, err = abi.Decode("core::array::Array::<()>", utils.TestHexArrToFelt(t, []string{"0xffffffffffffffff"}))
+	require.EqualError(t, err, "value: invalid data: 18446744073709551615 elements for 0 felts")
+	_, err = abi.Decode("core::array::Span::<core::felt252>", utils.TestHexArrToFelt(t, []string{"0x3", "0x1"}))
+	require.ErrorIs(t, err, ErrInvalidData)
+
+	// the fixed-size arrays of unit elements are bounded, nested ones included
+	decoded, err := abi.Decode("[(); 3]", []*felt.Felt{})
+	require.NoError(t, err)
+	require.Equal(t, []any{nil, nil, nil}, decoded)
+	_, err = abi.Decode("[(); 18446744073709551615]", []*felt.Felt{})
+	require.ErrorIs(t, err, ErrInvalidData)
+	_, err = abi.Decode("[[(); 65536]; 65536]", []*felt.Felt{})
+	require.EqualError(t, err, "value: invalid data: more than 65536 elements without felts")
+}
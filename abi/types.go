@@ -0,0 +1,245 @@
+package abi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TypeKind is the kind of a Cairo type.
+type TypeKind int
+
+const (
+	// KindNamed is a type referred to by its path, such as core::felt252 or
+	// core::array::Array::<core::felt252>.
+	KindNamed TypeKind = iota
+	// KindTuple is a tuple, such as (core::felt252, core::bool), the unit
+	// type () being the empty tuple.
+	KindTuple
+	// KindFixedArray is a fixed-size array, such as [core::felt252; 3].
+	KindFixedArray
+)
+
+// Type is a node of the tree of a Cairo type string.
+type Type struct {
+	Kind TypeKind
+	// Name the path of a named type, without its generic arguments
+	Name string
+	// Args the generic arguments of a named type, the elements of a tuple,
+	// or the element of a fixed-size array
+	Args []*Type
+	// Size the length of a fixed-size array
+	Size uint64
+}
+
+// Base returns the last segment of the path of a named type, such as Span
+// for core::array::Span::<core::felt252>.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the last segment of the path, empty for a tuple or a fixed-size array
+func (t *Type) Base() string {
+	if t.Kind != KindNamed {
+		return ""
+	}
+	return t.Name[strings.LastIndex(t.Name, "::")+len("::"):]
+}
+
+// String returns the type string of the type, as the compiler writes it in the ABI.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the type string
+func (t *Type) String() string {
+	var sb strings.Builder
+	t.write(&sb)
+	return sb.String()
+}
+
+// write writes the type string of the type.
+func (t *Type) write(sb *strings.Builder) {
+	switch t.Kind {
+	case KindTuple:
+		sb.WriteByte('(')
+		for i, arg := range t.Args {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			arg.write(sb)
+		}
+		sb.WriteByte(')')
+	case KindFixedArray:
+		sb.WriteByte('[')
+		t.Args[0].write(sb)
+		sb.WriteString("; ")
+		sb.WriteString(strconv.FormatUint(t.Size, 10))
+		sb.WriteByte(']')
+	default:
+		sb.WriteString(t.Name)
+		if len(t.Args) == 0 {
+			return
+		}
+		sb.WriteString("::<")
+		for i, arg := range t.Args {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			arg.write(sb)
+		}
+		sb.WriteByte('>')
+	}
+}
+
+// ParseType parses a Cairo type string, such as
+// core::array::Span::<core::integer::u256>, (core::felt252, core::bool) or
+// [core::felt252; 3], into its tree.
+//
+// Parameters:
+// - s: The type string
+// Returns:
+// - *Type: the tree of the type
+// - error: an error if the type string is malformed
+func ParseType(s string) (*Type, error) {
+	p := typeParser{s: s}
+	t, err := p.parseType()
+	if err != nil {
+		return nil, fmt.Errorf("invalid type %q: %w", s, err)
+	}
+	p.skipSpaces()
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("invalid type %q: unexpected %q at offset %d", s, p.s[p.pos:], p.pos)
+	}
+	return t, nil
+}
+
+// typeParser is a recursive descent parser of type strings.
+type typeParser struct {
+	s   string
+	pos int
+}
+
+// parseType parses the type at the current position.
+func (p *typeParser) parseType() (*Type, error) {
+	p.skipSpaces()
+	if p.pos == len(p.s) {
+		return nil, fmt.Errorf("missing type at offset %d", p.pos)
+	}
+	switch p.s[p.pos] {
+	case '(':
+		p.pos++
+		elems, err := p.parseList(')')
+		if err != nil {
+			return nil, err
+		}
+		return &Type{Kind: KindTuple, Args: elems}, nil
+	case '[':
+		p.pos++
+		elem, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(";"); err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		start := p.pos
+		for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		size, err := strconv.ParseUint(p.s[start:p.pos], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid array size at offset %d", start)
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return &Type{Kind: KindFixedArray, Args: []*Type{elem}, Size: size}, nil
+	}
+
+	start := p.pos
+	for {
+		if !p.parseIdent() {
+			return nil, fmt.Errorf("expected an identifier at offset %d", p.pos)
+		}
+		if !strings.HasPrefix(p.s[p.pos:], "::") {
+			return &Type{Kind: KindNamed, Name: p.s[start:p.pos]}, nil
+		}
+		if strings.HasPrefix(p.s[p.pos:], "::<") {
+			name := p.s[start:p.pos]
+			p.pos += len("::<")
+			args, err := p.parseList('>')
+			if err != nil {
+				return nil, err
+			}
+			if len(args) == 0 {
+				return nil, fmt.Errorf("empty generic arguments of %s", name)
+			}
+			return &Type{Kind: KindNamed, Name: name, Args: args}, nil
+		}
+		p.pos += len("::")
+	}
+}
+
+// parseList parses the types separated by commas up to the closing
+// character, allowing a trailing comma.
+func (p *typeParser) parseList(closing byte) ([]*Type, error) {
+	types := []*Type{}
+	for {
+		p.skipSpaces()
+		if p.pos < len(p.s) && p.s[p.pos] == closing {
+			p.pos++
+			return types, nil
+		}
+		t, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+		p.skipSpaces()
+		if p.pos < len(p.s) && p.s[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		if p.pos < len(p.s) && p.s[p.pos] == closing {
+			p.pos++
+			return types, nil
+		}
+		return nil, fmt.Errorf("expected ',' or '%c' at offset %d", closing, p.pos)
+	}
+}
+
+// parseIdent parses an identifier, reporting whether there was one.
+func (p *typeParser) parseIdent() bool {
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			break
+		}
+		p.pos++
+	}
+	return p.pos > start
+}
+
+// expect consumes the token, after spaces.
+func (p *typeParser) expect(token string) error {
+	p.skipSpaces()
+	if !strings.HasPrefix(p.s[p.pos:], token) {
+		return fmt.Errorf("expected %q at offset %d", token, p.pos)
+	}
+	p.pos += len(token)
+	return nil
+}
+
+// skipSpaces skips the spaces at the current position.
+func (p *typeParser) skipSpaces() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
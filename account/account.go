@@ -5,7 +5,6 @@ import (
 	"errors"
 	"time"
 
-	"github.com/NethermindEth/juno/core/crypto"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/contracts"
 	"github.com/NethermindEth/starknet.go/hash"
@@ -174,72 +173,16 @@ func (account *Account) SignDeclareTransaction(ctx context.Context, tx *rpc.Decl
 // - *felt.Felt: the calculated transaction hash
 // - error: an error if any
 func (account *Account) TransactionHashDeployAccount(tx rpc.DeployAccountType, contractAddress *felt.Felt) (*felt.Felt, error) {
-
-	// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#deploy_account_transaction
 	switch txn := tx.(type) {
 	case rpc.DeployAccountTxn:
-		calldata := []*felt.Felt{txn.ClassHash, txn.ContractAddressSalt}
-		calldata = append(calldata, txn.ConstructorCalldata...)
-		calldataHash, err := hash.ComputeHashOnElementsFelt(calldata)
-		if err != nil {
-			return nil, err
-		}
-
-		versionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-
-		// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#deploy_account_hash_calculation
-		return hash.CalculateTransactionHashCommon(
-			PREFIX_DEPLOY_ACCOUNT,
-			versionFelt,
-			contractAddress,
-			&felt.Zero,
-			calldataHash,
-			txn.MaxFee,
-			account.ChainId,
-			[]*felt.Felt{txn.Nonce},
-		)
 	case rpc.DeployAccountTxnV3:
 		if txn.Version == "" || txn.ResourceBounds == (rpc.ResourceBoundsMapping{}) || txn.Nonce == nil || txn.PayMasterData == nil {
 			return nil, ErrNotAllParametersSet
 		}
-		calldata := []*felt.Felt{txn.ClassHash, txn.ContractAddressSalt}
-		calldata = append(calldata, txn.ConstructorCalldata...) //nolint:all
-
-		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-		DAUint64, err := dataAvailabilityMode(txn.FeeMode, txn.NonceDataMode)
-		if err != nil {
-			return nil, err
-		}
-		tipUint64, err := txn.Tip.ToUint64()
-		if err != nil {
-			return nil, err
-		}
-		tipAndResourceHash, err := tipAndResourcesHash(tipUint64, txn.ResourceBounds)
-		if err != nil {
-			return nil, err
-		}
-		// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#deploy_account_hash_calculation
-		return crypto.PoseidonArray(
-			PREFIX_DEPLOY_ACCOUNT,
-			txnVersionFelt,
-			contractAddress,
-			tipAndResourceHash,
-			crypto.PoseidonArray(txn.PayMasterData...),
-			account.ChainId,
-			txn.Nonce,
-			new(felt.Felt).SetUint64(DAUint64),
-			crypto.PoseidonArray(txn.ConstructorCalldata...),
-			txn.ClassHash,
-			txn.ContractAddressSalt,
-		), nil
+	default:
+		return nil, ErrTxnTypeUnSupported
 	}
-	return nil, ErrTxnTypeUnSupported
+	return hash.TransactionHashDeployAccount(tx, contractAddress, account.ChainId)
 }
 
 // TransactionHashInvoke calculates the transaction hash for the given invoke transaction.
@@ -257,120 +200,24 @@ func (account *Account) TransactionHashDeployAccount(tx rpc.DeployAccountType, c
 
 // If the transaction type is unsupported, the function returns an error.
 func (account *Account) TransactionHashInvoke(tx rpc.InvokeTxnType) (*felt.Felt, error) {
-
-	// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#v0_hash_calculation
 	switch txn := tx.(type) {
 	case rpc.InvokeTxnV0:
 		if txn.Version == "" || len(txn.Calldata) == 0 || txn.MaxFee == nil || txn.EntryPointSelector == nil {
 			return nil, ErrNotAllParametersSet
 		}
-
-		calldataHash, err := hash.ComputeHashOnElementsFelt(txn.Calldata)
-		if err != nil {
-			return nil, err
-		}
-
-		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-		return hash.CalculateTransactionHashCommon(
-			PREFIX_TRANSACTION,
-			txnVersionFelt,
-			txn.ContractAddress,
-			txn.EntryPointSelector,
-			calldataHash,
-			txn.MaxFee,
-			account.ChainId,
-			[]*felt.Felt{},
-		)
-
 	case rpc.InvokeTxnV1:
 		if txn.Version == "" || len(txn.Calldata) == 0 || txn.Nonce == nil || txn.MaxFee == nil || txn.SenderAddress == nil {
 			return nil, ErrNotAllParametersSet
 		}
-
-		calldataHash, err := hash.ComputeHashOnElementsFelt(txn.Calldata)
-		if err != nil {
-			return nil, err
-		}
-		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-		return hash.CalculateTransactionHashCommon(
-			PREFIX_TRANSACTION,
-			txnVersionFelt,
-			txn.SenderAddress,
-			&felt.Zero,
-			calldataHash,
-			txn.MaxFee,
-			account.ChainId,
-			[]*felt.Felt{txn.Nonce},
-		)
 	case rpc.InvokeTxnV3:
 		// https://github.com/starknet-io/SNIPs/blob/main/SNIPS/snip-8.md#protocol-changes
 		if txn.Version == "" || txn.ResourceBounds == (rpc.ResourceBoundsMapping{}) || len(txn.Calldata) == 0 || txn.Nonce == nil || txn.SenderAddress == nil || txn.PayMasterData == nil || txn.AccountDeploymentData == nil {
 			return nil, ErrNotAllParametersSet
 		}
-
-		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-		DAUint64, err := dataAvailabilityMode(txn.FeeMode, txn.NonceDataMode)
-		if err != nil {
-			return nil, err
-		}
-		tipUint64, err := txn.Tip.ToUint64()
-		if err != nil {
-			return nil, err
-		}
-		tipAndResourceHash, err := tipAndResourcesHash(tipUint64, txn.ResourceBounds)
-		if err != nil {
-			return nil, err
-		}
-		return crypto.PoseidonArray(
-			PREFIX_TRANSACTION,
-			txnVersionFelt,
-			txn.SenderAddress,
-			tipAndResourceHash,
-			crypto.PoseidonArray(txn.PayMasterData...),
-			account.ChainId,
-			txn.Nonce,
-			new(felt.Felt).SetUint64(DAUint64),
-			crypto.PoseidonArray(txn.AccountDeploymentData...),
-			crypto.PoseidonArray(txn.Calldata...),
-		), nil
-	}
-	return nil, ErrTxnTypeUnSupported
-}
-
-func tipAndResourcesHash(tip uint64, resourceBounds rpc.ResourceBoundsMapping) (*felt.Felt, error) {
-	l1Bytes, err := resourceBounds.L1Gas.Bytes(rpc.ResourceL1Gas)
-	if err != nil {
-		return nil, err
-	}
-	l2Bytes, err := resourceBounds.L2Gas.Bytes(rpc.ResourceL2Gas)
-	if err != nil {
-		return nil, err
-	}
-	l1Bounds := new(felt.Felt).SetBytes(l1Bytes)
-	l2Bounds := new(felt.Felt).SetBytes(l2Bytes)
-	return crypto.PoseidonArray(new(felt.Felt).SetUint64(tip), l1Bounds, l2Bounds), nil
-}
-
-func dataAvailabilityMode(feeDAMode, nonceDAMode rpc.DataAvailabilityMode) (uint64, error) {
-	const dataAvailabilityModeBits = 32
-	fee64, err := feeDAMode.UInt64()
-	if err != nil {
-		return 0, err
-	}
-	nonce64, err := nonceDAMode.UInt64()
-	if err != nil {
-		return 0, err
+	default:
+		return nil, ErrTxnTypeUnSupported
 	}
-	return fee64 + nonce64<<dataAvailabilityModeBits, nil
+	return hash.TransactionHashInvoke(tx, account.ChainId)
 }
 
 // TransactionHashDeclare calculates the transaction hash for declaring a transaction type.
@@ -388,7 +235,6 @@ func dataAvailabilityMode(feeDAMode, nonceDAMode rpc.DataAvailabilityMode) (uint
 //
 // If the `tx` parameter is not one of the supported types, the function returns an error `ErrTxnTypeUnSupported`.
 func (account *Account) TransactionHashDeclare(tx rpc.DeclareTxnType) (*felt.Felt, error) {
-
 	switch txn := tx.(type) {
 	case rpc.DeclareTxnV0:
 		// Due to inconsistencies in version 0 hash calculation we don't calculate the hash
@@ -397,90 +243,20 @@ func (account *Account) TransactionHashDeclare(tx rpc.DeclareTxnType) (*felt.Fel
 		if txn.SenderAddress == nil || txn.Version == "" || txn.ClassHash == nil || txn.MaxFee == nil || txn.Nonce == nil {
 			return nil, ErrNotAllParametersSet
 		}
-
-		calldataHash, err := hash.ComputeHashOnElementsFelt([]*felt.Felt{txn.ClassHash})
-		if err != nil {
-			return nil, err
-		}
-
-		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-		return hash.CalculateTransactionHashCommon(
-			PREFIX_DECLARE,
-			txnVersionFelt,
-			txn.SenderAddress,
-			&felt.Zero,
-			calldataHash,
-			txn.MaxFee,
-			account.ChainId,
-			[]*felt.Felt{txn.Nonce},
-		)
 	case rpc.DeclareTxnV2:
 		if txn.CompiledClassHash == nil || txn.SenderAddress == nil || txn.Version == "" || txn.ClassHash == nil || txn.MaxFee == nil || txn.Nonce == nil {
 			return nil, ErrNotAllParametersSet
 		}
-
-		calldataHash, err := hash.ComputeHashOnElementsFelt([]*felt.Felt{txn.ClassHash})
-		if err != nil {
-			return nil, err
-		}
-
-		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-		return hash.CalculateTransactionHashCommon(
-			PREFIX_DECLARE,
-			txnVersionFelt,
-			txn.SenderAddress,
-			&felt.Zero,
-			calldataHash,
-			txn.MaxFee,
-			account.ChainId,
-			[]*felt.Felt{txn.Nonce, txn.CompiledClassHash},
-		)
 	case rpc.DeclareTxnV3:
 		// https://github.com/starknet-io/SNIPs/blob/main/SNIPS/snip-8.md#protocol-changes
 		if txn.Version == "" || txn.ResourceBounds == (rpc.ResourceBoundsMapping{}) || txn.Nonce == nil || txn.SenderAddress == nil || txn.PayMasterData == nil || txn.AccountDeploymentData == nil ||
 			txn.ClassHash == nil || txn.CompiledClassHash == nil {
 			return nil, ErrNotAllParametersSet
 		}
-
-		txnVersionFelt, err := new(felt.Felt).SetString(string(txn.Version))
-		if err != nil {
-			return nil, err
-		}
-		DAUint64, err := dataAvailabilityMode(txn.FeeMode, txn.NonceDataMode)
-		if err != nil {
-			return nil, err
-		}
-		tipUint64, err := txn.Tip.ToUint64()
-		if err != nil {
-			return nil, err
-		}
-
-		tipAndResourceHash, err := tipAndResourcesHash(tipUint64, txn.ResourceBounds)
-		if err != nil {
-			return nil, err
-		}
-		return crypto.PoseidonArray(
-			PREFIX_DECLARE,
-			txnVersionFelt,
-			txn.SenderAddress,
-			tipAndResourceHash,
-			crypto.PoseidonArray(txn.PayMasterData...),
-			account.ChainId,
-			txn.Nonce,
-			new(felt.Felt).SetUint64(DAUint64),
-			crypto.PoseidonArray(txn.AccountDeploymentData...),
-			txn.ClassHash,
-			txn.CompiledClassHash,
-		), nil
+	default:
+		return nil, ErrTxnTypeUnSupported
 	}
-
-	return nil, ErrTxnTypeUnSupported
+	return hash.TransactionHashDeclare(tx, account.ChainId)
 }
 
 // PrecomputeAccountAddress calculates the precomputed address for an account.
@@ -521,12 +297,10 @@ func (account *Account) WaitForTransactionReceipt(ctx context.Context, transacti
 		case <-t.C:
 			receiptWithBlockInfo, err := account.TransactionReceipt(ctx, transactionHash)
 			if err != nil {
-				rpcErr := err.(*rpc.RPCError)
-				if rpcErr.Code == rpc.ErrHashNotFound.Code && rpcErr.Message == rpc.ErrHashNotFound.Message {
+				if errors.Is(err, rpc.ErrHashNotFound) {
 					continue
-				} else {
-					return nil, err
 				}
+				return nil, err
 			}
 			return receiptWithBlockInfo, nil
 		}
@@ -631,6 +405,42 @@ func (account *Account) BlockWithReceipts(ctx context.Context, blockID rpc.Block
 	return account.provider.BlockWithReceipts(ctx, blockID)
 }
 
+// TypedBlockWithTxHashes retrieves a block with transaction hashes as a typed result.
+//
+// Parameters:
+// - ctx: the context.Context object for the request.
+// - blockID: the rpc.BlockID object specifying the block to retrieve.
+// Returns:
+// - *rpc.BlockTxHashesResult: the retrieved block, pending or not
+// - error: an error if there was any issue retrieving the block
+func (account *Account) TypedBlockWithTxHashes(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockTxHashesResult, error) {
+	return account.provider.TypedBlockWithTxHashes(ctx, blockID)
+}
+
+// TypedBlockWithTxs retrieves the specified block along with its transactions as a typed result.
+//
+// Parameters:
+// - ctx: the context.Context object for the request.
+// - blockID: the rpc.BlockID object specifying the block to retrieve.
+// Returns:
+// - *rpc.BlockResult: the retrieved block, pending or not
+// - error: an error if there was any issue retrieving the block
+func (account *Account) TypedBlockWithTxs(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockResult, error) {
+	return account.provider.TypedBlockWithTxs(ctx, blockID)
+}
+
+// TypedBlockWithReceipts retrieves the specified block along with its transactions and receipts as a typed result.
+//
+// Parameters:
+// - ctx: the context.Context object for the request.
+// - blockID: the rpc.BlockID object specifying the block to retrieve.
+// Returns:
+// - *rpc.BlockWithReceiptsResult: the retrieved block, pending or not
+// - error: an error if there was any issue retrieving the block
+func (account *Account) TypedBlockWithReceipts(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockWithReceiptsResult, error) {
+	return account.provider.TypedBlockWithReceipts(ctx, blockID)
+}
+
 // Call is a function that performs a function call on an Account.
 //
 // Parameters:
@@ -695,6 +505,18 @@ func (account *Account) ClassHashAt(ctx context.Context, blockID rpc.BlockID, co
 	return account.provider.ClassHashAt(ctx, blockID, contractAddress)
 }
 
+// CompiledCasm returns the CASM compiled from the Sierra class with the given hash.
+//
+// Parameters:
+// - ctx: The context to use for the function call.
+// - classHash: The hash of the Sierra class.
+// Returns:
+// - *contracts.CasmClass: the compiled class
+// - error: an error if any occurred.
+func (account *Account) CompiledCasm(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error) {
+	return account.provider.CompiledCasm(ctx, classHash)
+}
+
 // EstimateFee estimates the fee for a set of requests in the given block ID.
 //
 // Parameters:
@@ -773,6 +595,18 @@ func (account *Account) StorageAt(ctx context.Context, contractAddress *felt.Fel
 	return account.provider.StorageAt(ctx, contractAddress, key, blockID)
 }
 
+// StorageProof returns the proofs of classes, contracts and storage slots in the state tries of a block.
+//
+// Parameters:
+// - ctx: The context.Context object for the function
+// - input: The block and the items to prove
+// Returns:
+// - *rpc.StorageProof: the proofs and the global roots of the block
+// - error: An error if the retrieval fails.
+func (account *Account) StorageProof(ctx context.Context, input rpc.StorageProofInput) (*rpc.StorageProof, error) {
+	return account.provider.StorageProof(ctx, input)
+}
+
 // StateUpdate updates the state of the Account.
 //
 // Parameters:
@@ -878,6 +712,18 @@ func (account *Account) GetTransactionStatus(ctx context.Context, Txnhash *felt.
 	return account.provider.GetTransactionStatus(ctx, Txnhash)
 }
 
+// MessagesStatus returns the status of the messages sent by an L1 transaction.
+//
+// Parameters:
+// - ctx: The context.Context
+// - transactionHash: The hash of the L1 transaction.
+// Returns:
+// - []rpc.MessageStatusResp: the status of each message
+// - error: an error if any
+func (account *Account) MessagesStatus(ctx context.Context, transactionHash rpc.NumAsHex) ([]rpc.MessageStatusResp, error) {
+	return account.provider.MessagesStatus(ctx, transactionHash)
+}
+
 // FmtCalldata generates the formatted calldata for the given function calls and Cairo version.
 //
 // Parameters:
@@ -0,0 +1,198 @@
+package cairo
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+type allowance struct {
+	Spender *felt.Felt
+	Amount  *big.Int `cairo:"u256"`
+}
+
+// point serializes itself as a single felt, x * 2^32 + y.
+type point struct {
+	X, Y uint32
+}
+
+// MarshalCairo packs the point in a felt.
+func (p point) MarshalCairo() ([]*felt.Felt, error) {
+	return []*felt.Felt{new(felt.Felt).SetUint64(uint64(p.X)<<32 | uint64(p.Y))}, nil
+}
+
+// UnmarshalCairo unpacks the point from a felt.
+func (p *point) UnmarshalCairo(data []*felt.Felt) (int, error) {
+	n := data[0].BigInt(new(big.Int)).Uint64()
+	p.X, p.Y = uint32(n>>32), uint32(n)
+	return 1, nil
+}
+
+type token struct {
+	Name     string
+	Symbol   string `cairo:"felt"`
+	Decimals uint8
+	Supply   *big.Int `cairo:"u256"`
+	Holders  []allowance
+	Pair     [2]uint32
+	Owner    *allowance
+	Delegate *allowance
+	Delta    int64
+	Change   *big.Int   `cairo:"i128"`
+	Balances []*big.Int `cairo:"u256"`
+	Data     []byte     `cairo:"bytearray"`
+	Origin   point
+	Target   *point
+	Ignored  string `cairo:"-"`
+	private  bool
+}
+
+// TestMarshal tests the round trip of the values through their felts.
+func TestMarshal(t *testing.T) {
+	type testSetType struct {
+		Value        any
+		ExpectedData []string
+	}
+	u128Plus5, _ := new(big.Int).SetString("0x100000000000000000000000000000005", 0)
+	minusTwo := utils.BigToHex(new(big.Int).Sub(fieldPrime, big.NewInt(2)))
+	testSet := []testSetType{
+		{Value: new(felt.Felt).SetUint64(7), ExpectedData: []string{"0x7"}},
+		{Value: true, ExpectedData: []string{"0x1"}},
+		{Value: int8(-2), ExpectedData: []string{minusTwo}},
+		{Value: "hello", ExpectedData: []string{"0x0", "0x68656c6c6f", "0x5"}},
+		{Value: []uint16{1, 2}, ExpectedData: []string{"0x2", "0x1", "0x2"}},
+		{Value: [3]bool{true, false, true}, ExpectedData: []string{"0x1", "0x0", "0x1"}},
+		{Value: allowance{Spender: utils.TestHexToFelt(t, "0x4a"), Amount: u128Plus5}, ExpectedData: []string{"0x4a", "0x5", "0x1"}},
+		{
+			Value: token{
+				Name:     "Ether",
+				Symbol:   "ETH",
+				Decimals: 18,
+				Supply:   big.NewInt(1000),
+				Holders:  []allowance{{Spender: utils.TestHexToFelt(t, "0x1"), Amount: big.NewInt(2)}},
+				Pair:     [2]uint32{3, 4},
+				Owner:    &allowance{Spender: utils.TestHexToFelt(t, "0x5"), Amount: u128Plus5},
+				Delta:    -2,
+				Change:   big.NewInt(-2),
+				Balances: []*big.Int{big.NewInt(6)},
+				Data:     []byte{0, 1},
+				Origin:   point{X: 1, Y: 2},
+				Target:   &point{Y: 3},
+			},
+			ExpectedData: []string{
+				"0x0", "0x4574686572", "0x5", // name
+				"0x455448", "0x12", // symbol, decimals
+				"0x3e8", "0x0", // supply
+				"0x1", "0x1", "0x2", "0x0", // holders
+				"0x3", "0x4", // pair
+				"0x0", "0x5", "0x5", "0x1", // owner
+				"0x1",               // delegate
+				minusTwo,            // delta
+				minusTwo,            // change
+				"0x1", "0x6", "0x0", // balances
+				"0x0", "0x1", "0x2", // data
+				"0x100000002", // origin
+				"0x0", "0x3",  // target
+			},
+		},
+	}
+	for _, test := range testSet {
+		data, err := Marshal(test.Value)
+		require.NoError(t, err)
+		require.Equal(t, utils.TestHexArrToFelt(t, test.ExpectedData), data)
+
+		decoded := newOf(test.Value)
+		require.NoError(t, Unmarshal(data, decoded))
+		require.Equal(t, test.Value, deref(decoded))
+
+		// a pointer is serialized as the value it points to, not as an Option
+		data, err = Marshal(decoded)
+		require.NoError(t, err)
+		require.Equal(t, utils.TestHexArrToFelt(t, test.ExpectedData), data)
+	}
+}
+
+// TestMarshalErrors tests that the errors name the path of the invalid values.
+func TestMarshalErrors(t *testing.T) {
+	_, err := Marshal(token{Name: "x", Symbol: "a symbol longer than thirty-one bytes", Supply: big.NewInt(1), Origin: point{}})
+	require.ErrorIs(t, err, ErrInvalidValue)
+	require.ErrorContains(t, err, "value.Symbol")
+
+	_, err = Marshal(allowance{Spender: new(felt.Felt), Amount: new(big.Int).Lsh(big.NewInt(1), 256)})
+	require.ErrorIs(t, err, ErrInvalidValue)
+	require.EqualError(t, err, "cairo: value.Amount: invalid value: 115792089237316195423570985008687907853269984665640564039457584007913129639936 overflows a u256")
+
+	_, err = Marshal(map[string]int{})
+	require.ErrorIs(t, err, ErrUnsupportedType)
+	_, err = Marshal(struct {
+		A int `cairo:"u128"`
+	}{})
+	require.ErrorIs(t, err, ErrUnsupportedType)
+
+	var a allowance
+	require.ErrorIs(t, Unmarshal(utils.TestHexArrToFelt(t, []string{"0x1", "0x2"}), &a), ErrInvalidData)
+	require.EqualError(t, Unmarshal(utils.TestHexArrToFelt(t, []string{"0x1", "0x2"}), &a), "cairo: value.Amount.high: invalid data: missing felt at offset 2")
+	require.ErrorIs(t, Unmarshal(utils.TestHexArrToFelt(t, []string{"0x1", "0x2", "0x3", "0x4"}), &a), ErrInvalidData)
+	var holders []allowance
+	require.ErrorIs(t, Unmarshal(utils.TestHexArrToFelt(t, []string{"0xffffffff"}), &holders), ErrInvalidData)
+	_, err = Marshal(struct {
+		A *big.Int `cairo:"i128"`
+	}{A: new(big.Int).Lsh(big.NewInt(1), 127)})
+	require.ErrorIs(t, err, ErrInvalidValue)
+	var signed struct {
+		A *big.Int `cairo:"i128"`
+	}
+	require.EqualError(t, Unmarshal(utils.TestHexArrToFelt(t, []string{"0x80000000000000000000000000000000"}), &signed), "cairo: value.A: invalid data: 0x80000000000000000000000000000000 overflows an i128")
+	var small uint8
+	require.EqualError(t, Unmarshal(utils.TestHexArrToFelt(t, []string{"0x100"}), &small), "cairo: value: invalid data: 256 overflows uint8")
+	require.ErrorIs(t, Unmarshal(nil, a), ErrUnsupportedType)
+}
+
+// newOf returns a pointer to a new zero value of the type of v.
+func newOf(v any) any {
+	switch v.(type) {
+	case *felt.Felt:
+		return new(felt.Felt)
+	case bool:
+		return new(bool)
+	case int8:
+		return new(int8)
+	case string:
+		return new(string)
+	case []uint16:
+		return new([]uint16)
+	case [3]bool:
+		return new([3]bool)
+	case allowance:
+		return new(allowance)
+	case token:
+		return new(token)
+	}
+	return nil
+}
+
+// deref returns the value pointed to by the pointers of newOf, keeping the felts as pointers.
+func deref(v any) any {
+	switch p := v.(type) {
+	case *felt.Felt:
+		return p
+	case *bool:
+		return *p
+	case *int8:
+		return *p
+	case *string:
+		return *p
+	case *[]uint16:
+		return *p
+	case *[3]bool:
+		return *p
+	case *allowance:
+		return *p
+	case *token:
+		return *p
+	}
+	return nil
+}
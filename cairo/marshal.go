@@ -0,0 +1,272 @@
+package cairo
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	// ErrUnsupportedType is matched with errors.Is by the errors of Marshal
+	// and Unmarshal for the Go types without a Cairo serialization.
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrInvalidValue is matched with errors.Is by the errors of Marshal when
+	// a value doesn't fit its Cairo type.
+	ErrInvalidValue = errors.New("invalid value")
+	// ErrInvalidData is matched with errors.Is by the errors of Unmarshal
+	// when the felts don't hold a value of the Go type.
+	ErrInvalidData = errors.New("invalid data")
+)
+
+// CairoMarshaler is implemented by the types serializing themselves into felts.
+type CairoMarshaler interface {
+	MarshalCairo() ([]*felt.Felt, error)
+}
+
+// CairoUnmarshaler is implemented by the types deserializing themselves
+// from felts. UnmarshalCairo is given the felts left to read and returns
+// the number of felts it read.
+type CairoUnmarshaler interface {
+	UnmarshalCairo(data []*felt.Felt) (int, error)
+}
+
+// The values of the cairo struct tag.
+const (
+	// tagFelt serializes a string as a short string in a single felt.
+	tagFelt = "felt"
+	// tagU256 serializes an integer as a u256, in its low and high parts.
+	tagU256 = "u256"
+	// tagByteArray serializes a string or a []byte as a ByteArray.
+	tagByteArray = "bytearray"
+	// tagI128 serializes a *big.Int as an i128, the negative values being read back as negative.
+	tagI128 = "i128"
+)
+
+// maxShortStringLength is the number of bytes of a short string.
+const maxShortStringLength = 31
+
+var (
+	feltType             = reflect.TypeOf(felt.Felt{})
+	bigIntType           = reflect.TypeOf(big.Int{})
+	cairoMarshalerType   = reflect.TypeOf((*CairoMarshaler)(nil)).Elem()
+	cairoUnmarshalerType = reflect.TypeOf((*CairoUnmarshaler)(nil)).Elem()
+
+	fieldPrime, _ = new(big.Int).SetString("800000000000011000000000000000000000000000000000000000000000001", 16)
+	u256Max       = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	i128Max       = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	i128Min       = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+)
+
+// Marshal serializes a Go value into felts, following the Serde layout of
+// Cairo, the way encoding/json serializes into JSON:
+//   - *felt.Felt, felt.Felt, bool and the Go integers are a single felt,
+//     the negative integers being serialized as their field element
+//   - *big.Int is a single felt, a u256 with the cairo:"u256" tag, or a
+//     signed i128 with the cairo:"i128" tag
+//   - string and []byte with the cairo:"bytearray" tag are a ByteArray, a
+//     string with the cairo:"felt" tag is a short string
+//   - the structs are their exported fields in order, the fields with the
+//     cairo:"-" tag being skipped
+//   - the slices are an Array, prefixed by their length, and the arrays are
+//     a tuple of their elements
+//   - the other pointers nested in the value are an Option, nil being None,
+//     while a pointer given to Marshal is the value it points to, the way
+//     Unmarshal reads it
+//   - the types implementing CairoMarshaler serialize themselves
+//
+// The tag of a field applies to the elements of its slices, arrays and pointers.
+//
+// Parameters:
+// - v: The value to serialize
+// Returns:
+// - []*felt.Felt: the felts of the value
+// - error: an error naming the path of the value that can't be serialized
+func Marshal(v any) ([]*felt.Felt, error) {
+	if v == nil {
+		return nil, fmt.Errorf("cairo: %w: nil", ErrUnsupportedType)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("cairo: %w: nil %T", ErrInvalidValue, v)
+		}
+		rv = rv.Elem()
+	}
+	out, err := marshal([]*felt.Felt{}, rv, "", "value")
+	if err != nil {
+		return nil, fmt.Errorf("cairo: %w", err)
+	}
+	return out, nil
+}
+
+// marshal appends the serialization of the value to out.
+func marshal(out []*felt.Felt, v reflect.Value, tag, path string) ([]*felt.Felt, error) {
+	// the pointers are options, the value they point to is serialized after the tag
+	if v.Kind() != reflect.Pointer {
+		var marshaler CairoMarshaler
+		if v.Type().Implements(cairoMarshalerType) {
+			marshaler = v.Interface().(CairoMarshaler)
+		} else if v.CanAddr() && v.Addr().Type().Implements(cairoMarshalerType) {
+			marshaler = v.Addr().Interface().(CairoMarshaler)
+		}
+		if marshaler != nil {
+			felts, err := marshaler.MarshalCairo()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			return append(out, felts...), nil
+		}
+	}
+
+	switch v.Type() {
+	case feltType:
+		f := v.Interface().(felt.Felt)
+		if tag == tagU256 {
+			return marshalU256(out, f.BigInt(new(big.Int)), path)
+		}
+		return append(out, &f), nil
+	case bigIntType:
+		b := v.Interface().(big.Int)
+		n := new(big.Int).Set(&b)
+		if tag == tagU256 {
+			return marshalU256(out, n, path)
+		}
+		if tag == tagI128 && (n.Cmp(i128Min) < 0 || n.Cmp(i128Max) > 0) {
+			return nil, fmt.Errorf("%s: %w: %s overflows an i128", path, ErrInvalidValue, n)
+		}
+		if n.Sign() < 0 {
+			n.Add(n, fieldPrime)
+		}
+		if n.Sign() < 0 || n.Cmp(fieldPrime) >= 0 {
+			return nil, fmt.Errorf("%s: %w: %s overflows a felt", path, ErrInvalidValue, &b)
+		}
+		return append(out, utils.BigIntToFelt(n)), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		elem := v.Type().Elem()
+		if elem == feltType || elem == bigIntType {
+			if v.IsNil() {
+				return nil, fmt.Errorf("%s: %w: nil %s", path, ErrInvalidValue, v.Type())
+			}
+			return marshal(out, v.Elem(), tag, path)
+		}
+		if v.IsNil() {
+			return append(out, new(felt.Felt).SetUint64(1)), nil
+		}
+		return marshal(append(out, new(felt.Felt)), v.Elem(), tag, path)
+	case reflect.Bool:
+		if v.Bool() {
+			return append(out, new(felt.Felt).SetUint64(1)), nil
+		}
+		return append(out, new(felt.Felt)), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := big.NewInt(v.Int())
+		if tag == tagU256 {
+			return marshalU256(out, n, path)
+		}
+		if n.Sign() < 0 {
+			n.Add(n, fieldPrime)
+		}
+		return append(out, utils.BigIntToFelt(n)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := new(big.Int).SetUint64(v.Uint())
+		if tag == tagU256 {
+			return marshalU256(out, n, path)
+		}
+		return append(out, utils.BigIntToFelt(n)), nil
+	case reflect.String:
+		switch tag {
+		case tagFelt:
+			if len(v.String()) > maxShortStringLength {
+				return nil, fmt.Errorf("%s: %w: %q is longer than a short string", path, ErrInvalidValue, v.String())
+			}
+			return append(out, new(felt.Felt).SetBytes([]byte(v.String()))), nil
+		case tagByteArray, "":
+			words, err := utils.StringToByteArrFelt(v.String())
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w: %v", path, ErrInvalidValue, err)
+			}
+			return append(out, words...), nil
+		}
+	case reflect.Slice:
+		if tag == tagByteArray && v.Type().Elem().Kind() == reflect.Uint8 {
+			words, err := utils.StringToByteArrFelt(string(v.Bytes()))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w: %v", path, ErrInvalidValue, err)
+			}
+			return append(out, words...), nil
+		}
+		out = append(out, new(felt.Felt).SetUint64(uint64(v.Len())))
+		return marshalElems(out, v, tag, path)
+	case reflect.Array:
+		return marshalElems(out, v, tag, path)
+	case reflect.Struct:
+		fields, err := structFields(v.Type())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, field := range fields {
+			var err error
+			if out, err = marshal(out, v.Field(field.index), field.tag, path+"."+field.name); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	if tag != "" {
+		return nil, fmt.Errorf("%s: %w: %s with the %q tag", path, ErrUnsupportedType, v.Type(), tag)
+	}
+	return nil, fmt.Errorf("%s: %w: %s", path, ErrUnsupportedType, v.Type())
+}
+
+// marshalElems appends the serialization of the elements of a slice or an array to out.
+func marshalElems(out []*felt.Felt, v reflect.Value, tag, path string) ([]*felt.Felt, error) {
+	for i := 0; i < v.Len(); i++ {
+		var err error
+		if out, err = marshal(out, v.Index(i), tag, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// marshalU256 appends the low and high parts of a u256 to out.
+func marshalU256(out []*felt.Felt, n *big.Int, path string) ([]*felt.Felt, error) {
+	if n.Sign() < 0 || n.Cmp(u256Max) > 0 {
+		return nil, fmt.Errorf("%s: %w: %s overflows a u256", path, ErrInvalidValue, n)
+	}
+	low, high := utils.SplitFactStr(utils.BigToHex(n))
+	return append(out, utils.BigIntToFelt(utils.HexToBN(low)), utils.BigIntToFelt(utils.HexToBN(high))), nil
+}
+
+// field is a serialized field of a struct.
+type field struct {
+	index int
+	name  string
+	tag   string
+}
+
+// structFields returns the serialized fields of a struct type, in order.
+func structFields(t reflect.Type) ([]field, error) {
+	fields := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("cairo")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		switch tag {
+		case "", tagFelt, tagU256, tagByteArray, tagI128:
+		default:
+			return nil, fmt.Errorf("%s: %w: unknown cairo tag %q", f.Name, ErrUnsupportedType, tag)
+		}
+		fields = append(fields, field{index: i, name: f.Name, tag: tag})
+	}
+	return fields, nil
+}
@@ -0,0 +1,325 @@
+package cairo
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// Unmarshal deserializes felts, such as the result of Provider.Call, into
+// the value pointed to by v, following the layout described by Marshal.
+// The integers whose felt is above half the field are read as negative
+// into the signed types and the *big.Int with the cairo:"i128" tag. All the felts must be read.
+//
+// Parameters:
+// - data: The felts to deserialize
+// - v: A non-nil pointer to the value to fill
+// Returns:
+// - error: an error naming the path of the value that can't be deserialized
+func Unmarshal(data []*felt.Felt, v any) error {
+	n, err := UnmarshalPrefix(data, v)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("cairo: %w: %d felts left over", ErrInvalidData, len(data)-n)
+	}
+	return nil
+}
+
+// UnmarshalPrefix deserializes the value at the start of the felts into the
+// value pointed to by v, as Unmarshal does, and returns the number of felts
+// read. It lets the types implementing CairoUnmarshaler read their content.
+//
+// Parameters:
+// - data: The felts to deserialize, starting with the value
+// - v: A non-nil pointer to the value to fill
+// Returns:
+// - int: the number of felts read
+// - error: an error naming the path of the value that can't be deserialized
+func UnmarshalPrefix(data []*felt.Felt, v any) (int, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return 0, fmt.Errorf("cairo: %w: Unmarshal needs a non-nil pointer, got %T", ErrUnsupportedType, v)
+	}
+	d := decoder{data: data}
+	if err := d.unmarshal(rv.Elem(), "", "value"); err != nil {
+		return 0, fmt.Errorf("cairo: %w", err)
+	}
+	return d.pos, nil
+}
+
+// decoder reads the values serialized in felts.
+type decoder struct {
+	data []*felt.Felt
+	pos  int
+}
+
+// unmarshal fills the settable value.
+func (d *decoder) unmarshal(v reflect.Value, tag, path string) error {
+	if v.Kind() != reflect.Pointer && v.CanAddr() && v.Addr().Type().Implements(cairoUnmarshalerType) {
+		n, err := v.Addr().Interface().(CairoUnmarshaler).UnmarshalCairo(d.data[d.pos:])
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if n < 0 || n > len(d.data)-d.pos {
+			return fmt.Errorf("%s: %w: %d felts read out of %d", path, ErrInvalidData, n, len(d.data)-d.pos)
+		}
+		d.pos += n
+		return nil
+	}
+
+	switch v.Type() {
+	case feltType:
+		if tag == tagU256 {
+			n, err := d.u256(path)
+			if err != nil {
+				return err
+			}
+			if n.Cmp(fieldPrime) >= 0 {
+				return fmt.Errorf("%s: %w: %s overflows a felt", path, ErrInvalidData, n)
+			}
+			v.Set(reflect.ValueOf(*utils.BigIntToFelt(n)))
+			return nil
+		}
+		f, err := d.next(path)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(*f))
+		return nil
+	case bigIntType:
+		var n *big.Int
+		if tag == tagU256 {
+			var err error
+			if n, err = d.u256(path); err != nil {
+				return err
+			}
+		} else {
+			f, err := d.next(path)
+			if err != nil {
+				return err
+			}
+			n = f.BigInt(new(big.Int))
+			if tag == tagI128 {
+				if n.Cmp(new(big.Int).Rsh(fieldPrime, 1)) > 0 {
+					n.Sub(n, fieldPrime)
+				}
+				if n.Cmp(i128Min) < 0 || n.Cmp(i128Max) > 0 {
+					return fmt.Errorf("%s: %w: %s overflows an i128", path, ErrInvalidData, f)
+				}
+			}
+		}
+		v.Addr().Interface().(*big.Int).Set(n)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		elem := v.Type().Elem()
+		if elem != feltType && elem != bigIntType {
+			index, err := d.length(path)
+			if err != nil {
+				return err
+			}
+			switch index {
+			case 0:
+			case 1:
+				v.Set(reflect.Zero(v.Type()))
+				return nil
+			default:
+				return fmt.Errorf("%s: %w: variant %d of an Option", path, ErrInvalidData, index)
+			}
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(elem))
+		}
+		return d.unmarshal(v.Elem(), tag, path)
+	case reflect.Bool:
+		f, err := d.next(path)
+		if err != nil {
+			return err
+		}
+		if f.Cmp(new(felt.Felt).SetUint64(1)) > 0 {
+			return fmt.Errorf("%s: %w: %s isn't a bool", path, ErrInvalidData, f)
+		}
+		v.SetBool(!f.IsZero())
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := d.integer(tag, path)
+		if err != nil {
+			return err
+		}
+		if n.Cmp(new(big.Int).Rsh(fieldPrime, 1)) > 0 {
+			n.Sub(n, fieldPrime)
+		}
+		if !n.IsInt64() || v.OverflowInt(n.Int64()) {
+			return fmt.Errorf("%s: %w: %s overflows %s", path, ErrInvalidData, n, v.Type())
+		}
+		v.SetInt(n.Int64())
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := d.integer(tag, path)
+		if err != nil {
+			return err
+		}
+		if !n.IsUint64() || v.OverflowUint(n.Uint64()) {
+			return fmt.Errorf("%s: %w: %s overflows %s", path, ErrInvalidData, n, v.Type())
+		}
+		v.SetUint(n.Uint64())
+		return nil
+	case reflect.String:
+		switch tag {
+		case tagFelt:
+			f, err := d.next(path)
+			if err != nil {
+				return err
+			}
+			b := f.Bytes()
+			s := b[:]
+			for len(s) > 0 && s[0] == 0 {
+				s = s[1:]
+			}
+			if len(s) > maxShortStringLength {
+				return fmt.Errorf("%s: %w: %s isn't a short string", path, ErrInvalidData, f)
+			}
+			v.SetString(string(s))
+			return nil
+		case tagByteArray, "":
+			s, err := d.byteArray(path)
+			if err != nil {
+				return err
+			}
+			v.SetString(s)
+			return nil
+		}
+	case reflect.Slice:
+		if tag == tagByteArray && v.Type().Elem().Kind() == reflect.Uint8 {
+			s, err := d.byteArray(path)
+			if err != nil {
+				return err
+			}
+			v.SetBytes([]byte(s))
+			return nil
+		}
+		length, err := d.length(path)
+		if err != nil {
+			return err
+		}
+		// each element takes a felt at least
+		if length > uint64(len(d.data)-d.pos) {
+			return fmt.Errorf("%s: %w: %d elements for %d felts", path, ErrInvalidData, length, len(d.data)-d.pos)
+		}
+		v.Set(reflect.MakeSlice(v.Type(), int(length), int(length)))
+		return d.unmarshalElems(v, tag, path)
+	case reflect.Array:
+		return d.unmarshalElems(v, tag, path)
+	case reflect.Struct:
+		fields, err := structFields(v.Type())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for _, field := range fields {
+			if err := d.unmarshal(v.Field(field.index), field.tag, path+"."+field.name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if tag != "" {
+		return fmt.Errorf("%s: %w: %s with the %q tag", path, ErrUnsupportedType, v.Type(), tag)
+	}
+	return fmt.Errorf("%s: %w: %s", path, ErrUnsupportedType, v.Type())
+}
+
+// unmarshalElems fills the elements of a slice or an array.
+func (d *decoder) unmarshalElems(v reflect.Value, tag, path string) error {
+	for i := 0; i < v.Len(); i++ {
+		if err := d.unmarshal(v.Index(i), tag, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// integer reads an integer, a u256 with the u256 tag.
+func (d *decoder) integer(tag, path string) (*big.Int, error) {
+	if tag == tagU256 {
+		return d.u256(path)
+	}
+	f, err := d.next(path)
+	if err != nil {
+		return nil, err
+	}
+	return f.BigInt(new(big.Int)), nil
+}
+
+// u256 reads the low and high parts of a u256.
+func (d *decoder) u256(path string) (*big.Int, error) {
+	low, err := d.next(path + ".low")
+	if err != nil {
+		return nil, err
+	}
+	high, err := d.next(path + ".high")
+	if err != nil {
+		return nil, err
+	}
+	if low.BigInt(new(big.Int)).BitLen() > 128 || high.BigInt(new(big.Int)).BitLen() > 128 {
+		return nil, fmt.Errorf("%s: %w: the parts %s and %s overflow u128", path, ErrInvalidData, low, high)
+	}
+	n := high.BigInt(new(big.Int))
+	return n.Lsh(n, 128).Or(n, low.BigInt(new(big.Int))), nil
+}
+
+// byteArray reads a ByteArray.
+func (d *decoder) byteArray(path string) (string, error) {
+	start := d.pos
+	count, err := d.length(path)
+	if err != nil {
+		return "", err
+	}
+	if count > uint64(len(d.data)-d.pos) {
+		return "", fmt.Errorf("%s: %w: %d words for %d felts", path, ErrInvalidData, count, len(d.data)-d.pos)
+	}
+	d.pos += int(count)
+	if _, err := d.next(path + ".pending_word"); err != nil {
+		return "", err
+	}
+	if _, err := d.next(path + ".pending_word_len"); err != nil {
+		return "", err
+	}
+	s, err := utils.ByteArrFeltToStringExact(d.data[start:d.pos])
+	if err != nil {
+		return "", fmt.Errorf("%s: %w: %v", path, ErrInvalidData, err)
+	}
+	return s, nil
+}
+
+// next reads a felt.
+func (d *decoder) next(path string) (*felt.Felt, error) {
+	if d.pos == len(d.data) {
+		return nil, fmt.Errorf("%s: %w: missing felt at offset %d", path, ErrInvalidData, d.pos)
+	}
+	f := d.data[d.pos]
+	if f == nil {
+		return nil, fmt.Errorf("%s: %w: nil felt at offset %d", path, ErrInvalidData, d.pos)
+	}
+	d.pos++
+	return f, nil
+}
+
+// length reads a felt holding a length or a variant index.
+func (d *decoder) length(path string) (uint64, error) {
+	f, err := d.next(path)
+	if err != nil {
+		return 0, err
+	}
+	n := f.BigInt(new(big.Int))
+	if !n.IsUint64() {
+		return 0, fmt.Errorf("%s: %w: %s isn't a length", path, ErrInvalidData, f)
+	}
+	return n.Uint64(), nil
+}
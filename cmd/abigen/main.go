@@ -0,0 +1,114 @@
+// Command abigen generates the Go bindings of a contract from its class:
+// typed methods calling its view functions and invoking its external
+// functions, the Go types of its structs and enums, and typed decoders of
+// its events.
+//
+// Usage:
+//
+//	abigen -class token.contract_class.json -pkg token -type Token -out token/token.go
+//
+// The class is either a Sierra contract class, whose abi is a JSON string,
+// a Scarb artifact, whose abi is a JSON array, or the ABI alone.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/NethermindEth/starknet.go/abi"
+	"github.com/NethermindEth/starknet.go/abi/bind"
+)
+
+// main entry point of the program.
+//
+// It reads the class given by the flags, generates its bindings and writes
+// them to the output file, or to the standard output.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func main() {
+	classPath := flag.String("class", "", "path of the Sierra contract class, Scarb artifact or ABI")
+	pkg := flag.String("pkg", "", "name of the Go package of the bindings")
+	typeName := flag.String("type", "", "name of the Go type of the contract, defaults to the package name in title case")
+	out := flag.String("out", "", "path of the Go file to write, defaults to the standard output")
+	flag.Parse()
+
+	if *classPath == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *typeName == "" {
+		*typeName = strings.ToUpper((*pkg)[:1]) + (*pkg)[1:]
+	}
+	if err := run(*classPath, *pkg, *typeName, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "abigen:", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the bindings of the class.
+//
+// Parameters:
+// - classPath: The path of the class
+// - pkg: The name of the Go package
+// - typeName: The name of the Go type of the contract
+// - out: The path of the Go file, empty for the standard output
+// Returns:
+// - error: an error if the class can't be read or bound
+func run(classPath, pkg, typeName, out string) error {
+	content, err := os.ReadFile(classPath)
+	if err != nil {
+		return err
+	}
+	contract, err := readABI(content)
+	if err != nil {
+		return fmt.Errorf("%s: %w", classPath, err)
+	}
+	source, err := bind.Bind(contract, pkg, typeName)
+	if err != nil {
+		return fmt.Errorf("%s: %w", classPath, err)
+	}
+	if out == "" {
+		_, err := os.Stdout.Write(source)
+		return err
+	}
+	return os.WriteFile(out, source, 0o644)
+}
+
+// readABI reads the ABI of a class, a Scarb artifact or an ABI alone.
+//
+// Parameters:
+// - content: The JSON content of the file
+// Returns:
+// - *abi.ABI: the ABI
+// - error: an error if the content holds no ABI
+func readABI(content []byte) (*abi.ABI, error) {
+	content = bytes.TrimSpace(content)
+	if len(content) > 0 && content[0] == '[' {
+		var contract abi.ABI
+		if err := json.Unmarshal(content, &contract); err != nil {
+			return nil, err
+		}
+		return &contract, nil
+	}
+	var class struct {
+		ABI *abi.ABI `json:"abi"`
+	}
+	if err := json.Unmarshal(content, &class); err != nil {
+		return nil, err
+	}
+	if class.ABI == nil {
+		return nil, errors.New("no abi")
+	}
+	return class.ABI, nil
+}
@@ -16,7 +16,10 @@ type CasmClass struct {
 	Version          string                     `json:"compiler_version"`
 	ByteCode         []*felt.Felt               `json:"bytecode"`
 	EntryPointByType CasmClassEntryPointsByType `json:"entry_points_by_type"`
-	// Hints            any                        `json:"hints"`
+	// Hints the hints of the bytecode, as pairs of a pc and its hints
+	Hints json.RawMessage `json:"hints,omitempty"`
+	// BytecodeSegmentLengths the lengths of the bytecode segments, a nested list of integers
+	BytecodeSegmentLengths json.RawMessage `json:"bytecode_segment_lengths,omitempty"`
 }
 
 type CasmClassEntryPointsByType struct {
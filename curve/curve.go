@@ -611,6 +611,19 @@ func (sc StarkCurve) PedersenHash(elems []*big.Int) (hash *big.Int, err error) {
 	return ptx, nil
 }
 
+// Pedersen computes the Pedersen hash of two felts.
+// NOTE: This function just wraps the Juno implementation
+// (ref: https://github.com/NethermindEth/juno/blob/main/core/crypto/pedersen_hash.go#L24)
+//
+// Parameters:
+// - a: The first felt
+// - b: The second felt
+// Returns:
+// - *felt.Felt: pointer to a felt.Felt
+func (sc StarkCurve) Pedersen(a, b *felt.Felt) *felt.Felt {
+	return junoCrypto.Pedersen(a, b)
+}
+
 // PoseidonArray is a function that takes a variadic number of felt.Felt pointers as parameters and
 // NOTE: This function just wraps the Juno implementation
 // (ref: https://github.com/NethermindEth/juno/blob/main/core/crypto/poseidon_hash.go#L74)
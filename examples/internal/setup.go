@@ -22,8 +22,8 @@ func init() {
 // Default "panic" but printing all RPCError fields (code, message, and data)
 func PanicRPC(err error) {
 
-	RPCErr, ok := err.(*rpc.RPCError)
-	if !ok {
+	var RPCErr *rpc.RPCError
+	if !errors.As(err, &RPCErr) {
 		panic("failed to cast to RPCError. This error is not a RPCError")
 	}
 	err = errors.Join(
@@ -0,0 +1,46 @@
+package gateway
+
+import "fmt"
+
+// Error is an error answered by the feeder gateway.
+type Error struct {
+	// StatusCode the HTTP status of the answer
+	StatusCode int `json:"-"`
+	// Code the Starknet error code, such as "StarknetErrorCode.BLOCK_NOT_FOUND"
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+var (
+	ErrBlockNotFound = &Error{
+		Code:    "StarknetErrorCode.BLOCK_NOT_FOUND",
+		Message: "Block not found",
+	}
+	ErrUndeclaredClass = &Error{
+		Code:    "StarknetErrorCode.UNDECLARED_CLASS",
+		Message: "Class is not declared",
+	}
+	ErrOutOfRangeBlockHash = &Error{
+		Code:    "StarkErrorCode.OUT_OF_RANGE_BLOCK_HASH",
+		Message: "Block hash is out of range",
+	}
+	ErrMalformedRequest = &Error{
+		Code:    "StarkErrorCode.MALFORMED_REQUEST",
+		Message: "Malformed request",
+	}
+)
+
+// Error returns the code and the message of the error.
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("feeder gateway answered with HTTP status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Is reports whether target is an *Error with the same code, so that
+// errors.Is(err, ErrBlockNotFound) matches the errors of the gateway.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code != "" && t.Code == e.Code
+}
@@ -0,0 +1,282 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+const (
+	// MainnetURL is the URL of the feeder gateway of Starknet mainnet.
+	MainnetURL = "https://alpha-mainnet.starknet.io"
+	// SepoliaURL is the URL of the feeder gateway of Starknet Sepolia.
+	SepoliaURL = "https://alpha-sepolia.starknet.io"
+)
+
+// Gateway is a client of the feeder gateway of a Starknet sequencer.
+type Gateway struct {
+	baseURL string
+	client  *http.Client
+	headers http.Header
+}
+
+// Option configures a Gateway.
+type Option func(*Gateway)
+
+// WithHTTPClient sets the HTTP client of the Gateway, http.DefaultClient by default.
+//
+// Parameters:
+// - client: The HTTP client
+// Returns:
+// - Option: the option to pass to NewGateway
+func WithHTTPClient(client *http.Client) Option {
+	return func(g *Gateway) {
+		g.client = client
+	}
+}
+
+// WithHeader adds a header, such as an API key, to every request of the Gateway.
+//
+// Parameters:
+// - key: The name of the header
+// - value: The value of the header
+// Returns:
+// - Option: the option to pass to NewGateway
+func WithHeader(key, value string) Option {
+	return func(g *Gateway) {
+		g.headers.Add(key, value)
+	}
+}
+
+// NewGateway creates a client of the feeder gateway at baseURL, such as
+// MainnetURL. The /feeder_gateway path is appended to it.
+//
+// Parameters:
+// - baseURL: The URL of the sequencer
+// - options: The options of the client
+// Returns:
+// - *Gateway: the client
+func NewGateway(baseURL string, options ...Option) *Gateway {
+	g := &Gateway{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  http.DefaultClient,
+		headers: http.Header{},
+	}
+	for _, option := range options {
+		option(g)
+	}
+	return g
+}
+
+// Block returns a block with its transactions and receipts, from get_block.
+//
+// Parameters:
+// - ctx: The context of the request
+// - blockID: The block, by number, hash or tag
+// Returns:
+// - *Block: the block
+// - error: an *Error if the gateway rejected the request, or any other error
+func (g *Gateway) Block(ctx context.Context, blockID rpc.BlockID) (*Block, error) {
+	query, err := blockQuery(blockID)
+	if err != nil {
+		return nil, err
+	}
+	var block Block
+	if err := g.get(ctx, "get_block", query, &block); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// StateUpdate returns the state update of a block, from get_state_update.
+//
+// Parameters:
+// - ctx: The context of the request
+// - blockID: The block, by number, hash or tag
+// Returns:
+// - *StateUpdate: the state update
+// - error: an *Error if the gateway rejected the request, or any other error
+func (g *Gateway) StateUpdate(ctx context.Context, blockID rpc.BlockID) (*StateUpdate, error) {
+	query, err := blockQuery(blockID)
+	if err != nil {
+		return nil, err
+	}
+	var update StateUpdate
+	if err := g.get(ctx, "get_state_update", query, &update); err != nil {
+		return nil, err
+	}
+	return &update, nil
+}
+
+// StateUpdateWithBlock returns the state update of a block along with the
+// block, in a single get_state_update request.
+//
+// Parameters:
+// - ctx: The context of the request
+// - blockID: The block, by number, hash or tag
+// Returns:
+// - *StateUpdateWithBlock: the state update and the block
+// - error: an *Error if the gateway rejected the request, or any other error
+func (g *Gateway) StateUpdateWithBlock(ctx context.Context, blockID rpc.BlockID) (*StateUpdateWithBlock, error) {
+	query, err := blockQuery(blockID)
+	if err != nil {
+		return nil, err
+	}
+	query.Set("includeBlock", "true")
+	var update StateUpdateWithBlock
+	if err := g.get(ctx, "get_state_update", query, &update); err != nil {
+		return nil, err
+	}
+	return &update, nil
+}
+
+// Signature returns the signature of a block by the sequencer, from get_signature.
+//
+// Parameters:
+// - ctx: The context of the request
+// - blockID: The block, by number or hash
+// Returns:
+// - *BlockSignature: the signature
+// - error: an *Error if the gateway rejected the request, or any other error
+func (g *Gateway) Signature(ctx context.Context, blockID rpc.BlockID) (*BlockSignature, error) {
+	query, err := blockQuery(blockID)
+	if err != nil {
+		return nil, err
+	}
+	var signature BlockSignature
+	if err := g.get(ctx, "get_signature", query, &signature); err != nil {
+		return nil, err
+	}
+	return &signature, nil
+}
+
+// ClassByHash returns a declared class, from get_class_by_hash.
+//
+// Parameters:
+// - ctx: The context of the request
+// - blockID: The block the class must be declared at
+// - classHash: The hash of the class
+// Returns:
+// - rpc.ClassOutput: a *rpc.ContractClass or, for the Cairo 0 classes, a *rpc.DeprecatedContractClass
+// - error: an *Error if the gateway rejected the request, or any other error
+func (g *Gateway) ClassByHash(ctx context.Context, blockID rpc.BlockID, classHash *felt.Felt) (rpc.ClassOutput, error) {
+	query, err := blockQuery(blockID)
+	if err != nil {
+		return nil, err
+	}
+	query.Set("classHash", classHash.String())
+	var raw map[string]json.RawMessage
+	if err := g.get(ctx, "get_class_by_hash", query, &raw); err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := raw["sierra_program"]; ok {
+		var class rpc.ContractClass
+		if err := json.Unmarshal(content, &class); err != nil {
+			return nil, err
+		}
+		return &class, nil
+	}
+	var class rpc.DeprecatedContractClass
+	if err := json.Unmarshal(content, &class); err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+// CompiledClassByClassHash returns the CASM of a Sierra class, from
+// get_compiled_class_by_class_hash.
+//
+// Parameters:
+// - ctx: The context of the request
+// - blockID: The block the class must be declared at
+// - classHash: The hash of the Sierra class
+// Returns:
+// - *contracts.CasmClass: the compiled class
+// - error: an *Error if the gateway rejected the request, or any other error
+func (g *Gateway) CompiledClassByClassHash(ctx context.Context, blockID rpc.BlockID, classHash *felt.Felt) (*contracts.CasmClass, error) {
+	query, err := blockQuery(blockID)
+	if err != nil {
+		return nil, err
+	}
+	query.Set("classHash", classHash.String())
+	var class contracts.CasmClass
+	if err := g.get(ctx, "get_compiled_class_by_class_hash", query, &class); err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+// blockQuery returns the query parameters selecting a block.
+//
+// Parameters:
+// - blockID: The block, by number, hash or tag
+// Returns:
+// - url.Values: the query parameters
+// - error: rpc.ErrInvalidBlockID if the block id is empty or has an unknown tag
+func blockQuery(blockID rpc.BlockID) (url.Values, error) {
+	query := url.Values{}
+	switch {
+	case blockID.Tag == "latest" || blockID.Tag == "pending":
+		query.Set("blockNumber", blockID.Tag)
+	case blockID.Tag != "":
+		return nil, rpc.ErrInvalidBlockID
+	case blockID.Number != nil:
+		query.Set("blockNumber", strconv.FormatUint(*blockID.Number, 10))
+	case blockID.Hash != nil:
+		query.Set("blockHash", blockID.Hash.String())
+	default:
+		return nil, rpc.ErrInvalidBlockID
+	}
+	return query, nil
+}
+
+// get sends a GET request to an endpoint of the feeder gateway and decodes the answer into result.
+//
+// Parameters:
+// - ctx: The context of the request
+// - endpoint: The endpoint, such as "get_block"
+// - query: The query parameters
+// - result: The value the answer is decoded into
+// Returns:
+// - error: an *Error if the gateway rejected the request, or any other error
+func (g *Gateway) get(ctx context.Context, endpoint string, query url.Values, result interface{}) error {
+	target := fmt.Sprintf("%s/feeder_gateway/%s?%s", g.baseURL, endpoint, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	for key, values := range g.headers {
+		req.Header[key] = values
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		gatewayErr := &Error{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(body, gatewayErr); err != nil || gatewayErr.Code == "" {
+			gatewayErr.Message = strings.TrimSpace(string(body))
+		}
+		return gatewayErr
+	}
+	return json.Unmarshal(body, result)
+}
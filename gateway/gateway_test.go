@@ -0,0 +1,145 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestGateway returns a Gateway to a server answering the endpoints with
+// the given files, along with the queries it received.
+func newTestGateway(t *testing.T, files map[string]string) (*Gateway, *[]url.Values) {
+	t.Helper()
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		if r.URL.Query().Get("blockNumber") == "404" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code": "StarknetErrorCode.BLOCK_NOT_FOUND", "message": "Block number 404 was not found."}`))
+			return
+		}
+		file, ok := files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("internal server error"))
+			return
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	t.Cleanup(server.Close)
+	return NewGateway(server.URL+"/", WithHTTPClient(server.Client())), &queries
+}
+
+// TestBlock tests that get_block is queried by number, hash and tag and that the block is decoded.
+func TestBlock(t *testing.T) {
+	gw, queries := newTestGateway(t, map[string]string{"/feeder_gateway/get_block": "tests/block.json"})
+	number := uint64(64164)
+	hash := utils.TestHexToFelt(t, "0x2a70fb03fe363a2d6be843343a1d81ce6abeda1e9bd5cc6ad8fa9f45e30fdeb")
+
+	type testSetType struct {
+		BlockID       rpc.BlockID
+		ExpectedQuery url.Values
+	}
+	testSet := []testSetType{
+		{BlockID: rpc.WithBlockNumber(number), ExpectedQuery: url.Values{"blockNumber": {"64164"}}},
+		{BlockID: rpc.WithBlockHash(hash), ExpectedQuery: url.Values{"blockHash": {hash.String()}}},
+		{BlockID: rpc.WithBlockTag("latest"), ExpectedQuery: url.Values{"blockNumber": {"latest"}}},
+	}
+	for _, test := range testSet {
+		block, err := gw.Block(context.Background(), test.BlockID)
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedQuery, (*queries)[len(*queries)-1])
+
+		require.Equal(t, hash, block.BlockHash)
+		require.Equal(t, number, block.BlockNumber)
+		require.Equal(t, BlockStatusAcceptedOnL1, block.Status)
+		require.Equal(t, rpc.L1DAModeBlob, block.L1DAMode)
+		require.Equal(t, "0.13.2", block.StarknetVersion)
+		require.Len(t, block.Transactions, 1)
+		require.Equal(t, TransactionTypeInvoke, block.Transactions[0].Type)
+		require.Equal(t, rpc.U64("0x186a0"), block.Transactions[0].ResourceBounds["L1_GAS"].MaxAmount)
+		require.Len(t, block.Receipts, 1)
+		require.Equal(t, rpc.TxnExecutionStatusSUCCEEDED, block.Receipts[0].ExecutionStatus)
+		require.Len(t, block.Receipts[0].Events, 1)
+		require.Equal(t, uint64(128), block.Receipts[0].ExecutionResources.DataAvailability.L1DataGas)
+	}
+
+	_, err := gw.Block(context.Background(), rpc.BlockID{})
+	require.ErrorIs(t, err, rpc.ErrInvalidBlockID)
+}
+
+// TestStateUpdate tests that a state update is decoded and converted to the JSON-RPC form.
+func TestStateUpdate(t *testing.T) {
+	gw, queries := newTestGateway(t, map[string]string{"/feeder_gateway/get_state_update": "tests/state_update.json"})
+
+	update, err := gw.StateUpdate(context.Background(), rpc.WithBlockNumber(64164))
+	require.NoError(t, err)
+	require.Empty(t, (*queries)[0].Get("includeBlock"))
+	require.Equal(t, utils.TestHexToFelt(t, "0x2a70fb03fe363a2d6be843343a1d81ce6abeda1e9bd5cc6ad8fa9f45e30fdeb"), update.BlockHash)
+
+	diff, err := update.StateDiff.ToRPC()
+	require.NoError(t, err)
+	require.Len(t, diff.StorageDiffs, 2)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1"), diff.StorageDiffs[0].Address)
+	require.Equal(t, utils.TestHexToFelt(t, "0xfd06"), diff.StorageDiffs[0].StorageEntries[0].Key)
+	require.Len(t, diff.Nonces, 1)
+	require.Equal(t, utils.TestHexToFelt(t, "0x6"), diff.Nonces[0].Nonce)
+	require.Equal(t, []rpc.ReplacedClassesItem{{ContractClass: utils.TestHexToFelt(t, "0x2"), ClassHash: utils.TestHexToFelt(t, "0x3")}}, diff.ReplacedClasses)
+
+	_, err = gw.StateUpdateWithBlock(context.Background(), rpc.WithBlockTag("pending"))
+	require.NoError(t, err)
+	require.Equal(t, "true", (*queries)[1].Get("includeBlock"))
+	require.Equal(t, "pending", (*queries)[1].Get("blockNumber"))
+}
+
+// TestClassByHash tests that the Sierra classes are told apart from the Cairo 0 ones.
+func TestClassByHash(t *testing.T) {
+	gw, queries := newTestGateway(t, map[string]string{
+		"/feeder_gateway/get_class_by_hash":                "../contracts/tests/hello_starknet_compiled.sierra.json",
+		"/feeder_gateway/get_compiled_class_by_class_hash": "../contracts/tests/hello_starknet_compiled.casm.json",
+	})
+	classHash := utils.TestHexToFelt(t, "0x224518978adb773cfd4862a894e9d333192fbd24bc83841dc7d4167c09b89c5")
+
+	class, err := gw.ClassByHash(context.Background(), rpc.WithBlockTag("latest"), classHash)
+	require.NoError(t, err)
+	sierra, ok := class.(*rpc.ContractClass)
+	require.True(t, ok)
+	require.NotEmpty(t, sierra.SierraProgram)
+	require.Equal(t, classHash.String(), (*queries)[0].Get("classHash"))
+
+	casm, err := gw.CompiledClassByClassHash(context.Background(), rpc.WithBlockTag("latest"), classHash)
+	require.NoError(t, err)
+	require.NotEmpty(t, casm.ByteCode)
+}
+
+// TestGatewayError tests that the errors of the gateway match the sentinels.
+func TestGatewayError(t *testing.T) {
+	gw, _ := newTestGateway(t, map[string]string{})
+
+	_, err := gw.Block(context.Background(), rpc.WithBlockNumber(404))
+	require.ErrorIs(t, err, ErrBlockNotFound)
+	require.False(t, errors.Is(err, ErrUndeclaredClass))
+	var gatewayErr *Error
+	require.True(t, errors.As(err, &gatewayErr))
+	require.Equal(t, http.StatusBadRequest, gatewayErr.StatusCode)
+	require.Equal(t, "Block number 404 was not found.", gatewayErr.Message)
+
+	_, err = gw.Signature(context.Background(), rpc.WithBlockNumber(1))
+	require.True(t, errors.As(err, &gatewayErr))
+	require.Equal(t, http.StatusInternalServerError, gatewayErr.StatusCode)
+	require.Equal(t, "internal server error", gatewayErr.Message)
+	require.False(t, errors.Is(err, ErrBlockNotFound))
+}
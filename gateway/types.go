@@ -0,0 +1,218 @@
+package gateway
+
+import (
+	"sort"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// BlockStatus is the status of a block in the feeder gateway.
+type BlockStatus string
+
+const (
+	BlockStatusPending      BlockStatus = "PENDING"
+	BlockStatusAcceptedOnL2 BlockStatus = "ACCEPTED_ON_L2"
+	BlockStatusAcceptedOnL1 BlockStatus = "ACCEPTED_ON_L1"
+	BlockStatusReverted     BlockStatus = "REVERTED"
+	BlockStatusAborted      BlockStatus = "ABORTED"
+)
+
+// Block is a block answered by get_block.
+type Block struct {
+	// BlockHash the hash of the block, nil for the pending block
+	BlockHash       *felt.Felt `json:"block_hash,omitempty"`
+	ParentBlockHash *felt.Felt `json:"parent_block_hash"`
+	// BlockNumber the number of the block, zero for the pending block
+	BlockNumber uint64 `json:"block_number,omitempty"`
+	// StateRoot the global state root after the block
+	StateRoot *felt.Felt `json:"state_root,omitempty"`
+	// TransactionCommitment the root of the transactions trie, since Starknet v0.11
+	TransactionCommitment *felt.Felt `json:"transaction_commitment,omitempty"`
+	// EventCommitment the root of the events trie, since Starknet v0.11
+	EventCommitment *felt.Felt `json:"event_commitment,omitempty"`
+	// ReceiptCommitment the root of the receipts trie, since Starknet v0.13.2
+	ReceiptCommitment *felt.Felt `json:"receipt_commitment,omitempty"`
+	// StateDiffCommitment the hash of the state diff, since Starknet v0.13.2
+	StateDiffCommitment *felt.Felt `json:"state_diff_commitment,omitempty"`
+	// StateDiffLength the number of entries of the state diff, since Starknet v0.13.2
+	StateDiffLength uint64            `json:"state_diff_length,omitempty"`
+	Status          BlockStatus       `json:"status"`
+	L1DAMode        rpc.L1DAMode      `json:"l1_da_mode"`
+	L1GasPrice      rpc.ResourcePrice `json:"l1_gas_price"`
+	L1DataGasPrice  rpc.ResourcePrice `json:"l1_data_gas_price"`
+	// L2GasPrice the price of l2 gas, since Starknet v0.13.4
+	L2GasPrice       *rpc.ResourcePrice   `json:"l2_gas_price,omitempty"`
+	Transactions     []Transaction        `json:"transactions"`
+	Timestamp        uint64               `json:"timestamp"`
+	SequencerAddress *felt.Felt           `json:"sequencer_address,omitempty"`
+	Receipts         []TransactionReceipt `json:"transaction_receipts"`
+	StarknetVersion  string               `json:"starknet_version,omitempty"`
+}
+
+// TransactionType is the type of a transaction in the feeder gateway.
+type TransactionType string
+
+const (
+	TransactionTypeDeclare       TransactionType = "DECLARE"
+	TransactionTypeDeploy        TransactionType = "DEPLOY"
+	TransactionTypeDeployAccount TransactionType = "DEPLOY_ACCOUNT"
+	TransactionTypeInvoke        TransactionType = "INVOKE_FUNCTION"
+	TransactionTypeL1Handler     TransactionType = "L1_HANDLER"
+)
+
+// Transaction is a transaction of a Block. The fields a type or a version
+// doesn't have are left empty.
+type Transaction struct {
+	Hash    *felt.Felt      `json:"transaction_hash"`
+	Type    TransactionType `json:"type"`
+	Version *felt.Felt      `json:"version,omitempty"`
+	Nonce   *felt.Felt      `json:"nonce,omitempty"`
+	MaxFee  *felt.Felt      `json:"max_fee,omitempty"`
+	// ContractAddress the called contract of the v0 invoke and L1 handler
+	// transactions, the deployed contract of the deploy and deploy account ones
+	ContractAddress     *felt.Felt   `json:"contract_address,omitempty"`
+	SenderAddress       *felt.Felt   `json:"sender_address,omitempty"`
+	EntryPointSelector  *felt.Felt   `json:"entry_point_selector,omitempty"`
+	Calldata            []*felt.Felt `json:"calldata,omitempty"`
+	Signature           []*felt.Felt `json:"signature,omitempty"`
+	ClassHash           *felt.Felt   `json:"class_hash,omitempty"`
+	CompiledClassHash   *felt.Felt   `json:"compiled_class_hash,omitempty"`
+	ContractAddressSalt *felt.Felt   `json:"contract_address_salt,omitempty"`
+	ConstructorCalldata []*felt.Felt `json:"constructor_calldata,omitempty"`
+	// ResourceBounds the bounds of the v3 transactions, keyed by resource
+	// such as "L1_GAS", "L2_GAS" and "L1_DATA_GAS"
+	ResourceBounds        map[string]rpc.ResourceBounds `json:"resource_bounds,omitempty"`
+	Tip                   rpc.U64                       `json:"tip,omitempty"`
+	PaymasterData         []*felt.Felt                  `json:"paymaster_data,omitempty"`
+	AccountDeploymentData []*felt.Felt                  `json:"account_deployment_data,omitempty"`
+	NonceDAMode           rpc.DataAvailabilityMode      `json:"nonce_data_availability_mode,omitempty"`
+	FeeDAMode             rpc.DataAvailabilityMode      `json:"fee_data_availability_mode,omitempty"`
+}
+
+// TransactionReceipt is the receipt of a transaction of a Block.
+type TransactionReceipt struct {
+	TransactionHash  *felt.Felt             `json:"transaction_hash"`
+	TransactionIndex uint64                 `json:"transaction_index"`
+	ExecutionStatus  rpc.TxnExecutionStatus `json:"execution_status,omitempty"`
+	// RevertError the revert reason of a reverted transaction
+	RevertError           string             `json:"revert_error,omitempty"`
+	ActualFee             *felt.Felt         `json:"actual_fee"`
+	Events                []rpc.Event        `json:"events"`
+	L2ToL1Messages        []rpc.MsgToL1      `json:"l2_to_l1_messages"`
+	L1ToL2ConsumedMessage *L1ToL2Message     `json:"l1_to_l2_consumed_message,omitempty"`
+	ExecutionResources    ExecutionResources `json:"execution_resources"`
+}
+
+// L1ToL2Message is the message consumed by an L1 handler transaction.
+type L1ToL2Message struct {
+	FromAddress string       `json:"from_address"`
+	ToAddress   *felt.Felt   `json:"to_address"`
+	Selector    *felt.Felt   `json:"selector"`
+	Payload     []*felt.Felt `json:"payload"`
+	Nonce       *felt.Felt   `json:"nonce,omitempty"`
+}
+
+// ExecutionResources are the resources used by a transaction.
+type ExecutionResources struct {
+	Steps                  uint64            `json:"n_steps"`
+	BuiltinInstanceCounter map[string]uint64 `json:"builtin_instance_counter"`
+	MemoryHoles            uint64            `json:"n_memory_holes"`
+	// DataAvailability the gas used for the data availability, since Starknet v0.13.1
+	DataAvailability *GasVector `json:"data_availability,omitempty"`
+	// TotalGasConsumed the gas used by the transaction, since Starknet v0.13.2
+	TotalGasConsumed *GasVector `json:"total_gas_consumed,omitempty"`
+}
+
+// GasVector is an amount of each kind of gas.
+type GasVector struct {
+	L1Gas     uint64 `json:"l1_gas"`
+	L1DataGas uint64 `json:"l1_data_gas"`
+	L2Gas     uint64 `json:"l2_gas,omitempty"`
+}
+
+// StateUpdate is a state update answered by get_state_update.
+type StateUpdate struct {
+	// BlockHash the hash of the block, nil for the pending block
+	BlockHash *felt.Felt `json:"block_hash,omitempty"`
+	NewRoot   *felt.Felt `json:"new_root,omitempty"`
+	OldRoot   *felt.Felt `json:"old_root"`
+	StateDiff StateDiff  `json:"state_diff"`
+}
+
+// StateDiff is the change of the state in a block.
+type StateDiff struct {
+	// StorageDiffs the changed storage slots, keyed by contract address
+	StorageDiffs map[string][]rpc.StorageEntry `json:"storage_diffs"`
+	// Nonces the new nonces, keyed by contract address
+	Nonces            map[string]*felt.Felt      `json:"nonces"`
+	DeployedContracts []rpc.DeployedContractItem `json:"deployed_contracts"`
+	// OldDeclaredContracts the hashes of the declared Cairo 0 classes
+	OldDeclaredContracts []*felt.Felt              `json:"old_declared_contracts"`
+	DeclaredClasses      []rpc.DeclaredClassesItem `json:"declared_classes"`
+	ReplacedClasses      []ReplacedClass           `json:"replaced_classes"`
+}
+
+// ReplacedClass is a contract whose class was replaced.
+type ReplacedClass struct {
+	Address   *felt.Felt `json:"address"`
+	ClassHash *felt.Felt `json:"class_hash"`
+}
+
+// StateUpdateWithBlock is a state update along with its block.
+type StateUpdateWithBlock struct {
+	Block       Block       `json:"block"`
+	StateUpdate StateUpdate `json:"state_update"`
+}
+
+// BlockSignature is the signature of a block by the sequencer.
+type BlockSignature struct {
+	BlockHash *felt.Felt `json:"block_hash"`
+	// Signature the r and s values of the signature
+	Signature []*felt.Felt `json:"signature"`
+}
+
+// ToRPC converts the state diff to the JSON-RPC form, the contracts being
+// sorted by address.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - rpc.StateDiff: the state diff
+// - error: an error if an address isn't a felt
+func (d StateDiff) ToRPC() (rpc.StateDiff, error) {
+	diff := rpc.StateDiff{
+		StorageDiffs:              []rpc.ContractStorageDiffItem{},
+		DeprecatedDeclaredClasses: d.OldDeclaredContracts,
+		DeclaredClasses:           d.DeclaredClasses,
+		DeployedContracts:         d.DeployedContracts,
+		ReplacedClasses:           make([]rpc.ReplacedClassesItem, len(d.ReplacedClasses)),
+		Nonces:                    []rpc.ContractNonce{},
+	}
+	for address, entries := range d.StorageDiffs {
+		contract, err := new(felt.Felt).SetString(address)
+		if err != nil {
+			return rpc.StateDiff{}, err
+		}
+		diff.StorageDiffs = append(diff.StorageDiffs, rpc.ContractStorageDiffItem{Address: contract, StorageEntries: entries})
+	}
+	sort.Slice(diff.StorageDiffs, func(i, j int) bool {
+		return diff.StorageDiffs[i].Address.Cmp(diff.StorageDiffs[j].Address) < 0
+	})
+	for address, nonce := range d.Nonces {
+		contract, err := new(felt.Felt).SetString(address)
+		if err != nil {
+			return rpc.StateDiff{}, err
+		}
+		diff.Nonces = append(diff.Nonces, rpc.ContractNonce{ContractAddress: contract, Nonce: nonce})
+	}
+	sort.Slice(diff.Nonces, func(i, j int) bool {
+		return diff.Nonces[i].ContractAddress.Cmp(diff.Nonces[j].ContractAddress) < 0
+	})
+	for i, replaced := range d.ReplacedClasses {
+		diff.ReplacedClasses[i] = rpc.ReplacedClassesItem{ContractClass: replaced.Address, ClassHash: replaced.ClassHash}
+	}
+	return diff, nil
+}
@@ -0,0 +1,494 @@
+package hash
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+var (
+	// ErrUnsupportedBlockVersion is returned for the blocks older than Starknet
+	// v0.7.0, whose hash depends on the chain id.
+	ErrUnsupportedBlockVersion = errors.New("unsupported Starknet version")
+	// ErrBlockHashMismatch is matched with errors.Is by the error of
+	// VerifyBlock when the block hash doesn't match its content.
+	ErrBlockHashMismatch = errors.New("the block hash doesn't match the block")
+	// ErrStateUpdateMismatch is matched with errors.Is by the error of
+	// VerifyBlock when the state update isn't the one of the block.
+	ErrStateUpdateMismatch = errors.New("the state update doesn't match the block")
+)
+
+var (
+	// the first version hashing the blocks with Poseidon
+	poseidonBlockVersion = [4]uint64{0, 13, 2}
+	// the first version hashing the gas prices together, with the l2 gas price
+	l2GasBlockVersion = [4]uint64{0, 13, 4}
+	// the first version committing to the signatures of every transaction
+	allSignaturesBlockVersion = [4]uint64{0, 11, 1}
+	// the first version hashing the blocks without the chain id
+	minBlockVersion = [4]uint64{0, 7, 0}
+
+	blockHashPrefix0 = new(felt.Felt).SetBytes([]byte("STARKNET_BLOCK_HASH0"))
+	blockHashPrefix1 = new(felt.Felt).SetBytes([]byte("STARKNET_BLOCK_HASH1"))
+	gasPricesPrefix  = new(felt.Felt).SetBytes([]byte("STARKNET_GAS_PRICES0"))
+	stateDiffPrefix  = new(felt.Felt).SetBytes([]byte("STARKNET_STATE_DIFF0"))
+)
+
+// BlockHash computes the hash of a block from its header, its transactions
+// with their receipts and its state diff. The blocks of Starknet v0.13.2 and
+// later are hashed with Poseidon and commit to their state diff and
+// receipts, the older ones are hashed with Pedersen. The blocks older than
+// Starknet v0.7.0 are not supported.
+//
+// The receipt commitment relies on the gas consumed by the transactions,
+// which the nodes only return since the v0.8 of the JSON-RPC spec.
+//
+// Parameters:
+// - header: The header of the block, its own hash isn't used
+// - transactions: The transactions of the block with their receipts
+// - stateDiff: The state diff of the block, only required since Starknet v0.13.2
+// Returns:
+// - *felt.Felt: the hash of the block
+// - error: an error if the version of the block isn't supported or the state diff is missing
+func BlockHash(header rpc.BlockHeader, transactions []rpc.TransactionWithReceipt, stateDiff *rpc.StateDiff) (*felt.Felt, error) {
+	version, err := parseStarknetVersion(header.StarknetVersion)
+	if err != nil {
+		return nil, err
+	}
+	if compareVersions(version, minBlockVersion) < 0 {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedBlockVersion, header.StarknetVersion)
+	}
+	txCommitment, err := TransactionCommitment(transactions, header.StarknetVersion)
+	if err != nil {
+		return nil, err
+	}
+	eventCommitment, err := EventCommitment(transactions, header.StarknetVersion)
+	if err != nil {
+		return nil, err
+	}
+	eventCount := uint64(0)
+	for _, txn := range transactions {
+		eventCount += uint64(len(txn.Receipt.Events))
+	}
+
+	if compareVersions(version, poseidonBlockVersion) < 0 {
+		return crypto.PedersenArray(
+			new(felt.Felt).SetUint64(header.BlockNumber),
+			header.NewRoot,
+			header.SequencerAddress,
+			new(felt.Felt).SetUint64(header.Timestamp),
+			new(felt.Felt).SetUint64(uint64(len(transactions))),
+			txCommitment,
+			new(felt.Felt).SetUint64(eventCount),
+			eventCommitment,
+			&felt.Zero,
+			&felt.Zero,
+			header.ParentHash,
+		), nil
+	}
+
+	if stateDiff == nil {
+		return nil, fmt.Errorf("the state diff is required to hash a block of Starknet %s", header.StarknetVersion)
+	}
+	receipts := receiptCommitment(transactions)
+	counts := concatCounts(uint64(len(transactions)), eventCount, StateDiffLength(*stateDiff), header.L1DAMode)
+	if compareVersions(version, l2GasBlockVersion) < 0 {
+		// the gas prices are hashed inline, without the l2 gas price
+		return crypto.PoseidonArray(
+			blockHashPrefix0,
+			new(felt.Felt).SetUint64(header.BlockNumber),
+			header.NewRoot,
+			header.SequencerAddress,
+			new(felt.Felt).SetUint64(header.Timestamp),
+			counts,
+			StateDiffCommitment(*stateDiff),
+			txCommitment,
+			eventCommitment,
+			receipts,
+			feltOrZero(header.L1GasPrice.PriceInWei),
+			feltOrZero(header.L1GasPrice.PriceInFRI),
+			feltOrZero(header.L1DataGasPrice.PriceInWei),
+			feltOrZero(header.L1DataGasPrice.PriceInFRI),
+			new(felt.Felt).SetBytes([]byte(header.StarknetVersion)),
+			&felt.Zero,
+			header.ParentHash,
+		), nil
+	}
+	return crypto.PoseidonArray(
+		blockHashPrefix1,
+		new(felt.Felt).SetUint64(header.BlockNumber),
+		header.NewRoot,
+		header.SequencerAddress,
+		new(felt.Felt).SetUint64(header.Timestamp),
+		counts,
+		StateDiffCommitment(*stateDiff),
+		txCommitment,
+		eventCommitment,
+		receipts,
+		gasPricesHash(header),
+		new(felt.Felt).SetBytes([]byte(header.StarknetVersion)),
+		&felt.Zero,
+		header.ParentHash,
+	), nil
+}
+
+// VerifyBlock checks that the hash of a block, as returned by
+// BlockWithReceipts, matches its content and, if a state update is given,
+// that the state update is the one of the block. The block is hashed with
+// BlockHash.
+//
+// Parameters:
+// - block: The block with its receipts
+// - stateUpdate: The state update of the block, as returned by StateUpdate, or nil before Starknet v0.13.2
+// Returns:
+// - error: an error matching ErrBlockHashMismatch or ErrStateUpdateMismatch if the block doesn't verify, or the error of BlockHash
+func VerifyBlock(block *rpc.BlockWithReceipts, stateUpdate *rpc.StateUpdateOutput) error {
+	var stateDiff *rpc.StateDiff
+	if stateUpdate != nil {
+		if !equalFelts(stateUpdate.BlockHash, block.BlockHash) {
+			return fmt.Errorf("%w: the state update is the one of block %s, not %s", ErrStateUpdateMismatch, stateUpdate.BlockHash, block.BlockHash)
+		}
+		if !equalFelts(stateUpdate.NewRoot, block.NewRoot) {
+			return fmt.Errorf("%w: the new root is %s in the state update and %s in the block", ErrStateUpdateMismatch, stateUpdate.NewRoot, block.NewRoot)
+		}
+		stateDiff = &stateUpdate.StateDiff
+	}
+	hash, err := BlockHash(block.BlockHeader, block.Transactions, stateDiff)
+	if err != nil {
+		return err
+	}
+	if !equalFelts(hash, block.BlockHash) {
+		return fmt.Errorf("%w: computed %s, got %s", ErrBlockHashMismatch, hash, block.BlockHash)
+	}
+	return nil
+}
+
+// TransactionCommitment computes the root of the trie of the transactions
+// of a block. Each leaf commits to the hash and the signature of a
+// transaction, with Poseidon since Starknet v0.13.2 and with Pedersen before.
+//
+// Parameters:
+// - transactions: The transactions of the block, in order
+// - starknetVersion: The Starknet version of the block
+// Returns:
+// - *felt.Felt: the transaction commitment
+// - error: an error if the version can't be parsed
+func TransactionCommitment(transactions []rpc.TransactionWithReceipt, starknetVersion string) (*felt.Felt, error) {
+	version, err := parseStarknetVersion(starknetVersion)
+	if err != nil {
+		return nil, err
+	}
+	leaves := make([]*felt.Felt, len(transactions))
+	if compareVersions(version, poseidonBlockVersion) >= 0 {
+		// the empty signatures are hashed as a zero until Starknet v0.13.4
+		zeroSignature := compareVersions(version, l2GasBlockVersion) < 0
+		for i, txn := range transactions {
+			signature := transactionSignature(txn.Transaction.Transaction)
+			if len(signature) == 0 && zeroSignature {
+				signature = []*felt.Felt{&felt.Zero}
+			}
+			leaves[i] = crypto.PoseidonArray(append([]*felt.Felt{txn.Receipt.TransactionHash}, signature...)...)
+		}
+		return commitmentRoot(leaves, crypto.Poseidon), nil
+	}
+
+	allSignatures := compareVersions(version, allSignaturesBlockVersion) >= 0
+	for i, txn := range transactions {
+		var signature []*felt.Felt
+		if allSignatures || txn.Receipt.Type == rpc.TransactionType_Invoke {
+			signature = transactionSignature(txn.Transaction.Transaction)
+		}
+		leaves[i] = crypto.Pedersen(txn.Receipt.TransactionHash, crypto.PedersenArray(signature...))
+	}
+	return commitmentRoot(leaves, crypto.Pedersen), nil
+}
+
+// EventCommitment computes the root of the trie of the events of a block,
+// numbered across the transactions. Since Starknet v0.13.2 the leaves are
+// hashed with Poseidon and commit to the hash of the emitting transaction.
+//
+// Parameters:
+// - transactions: The transactions of the block with their receipts, in order
+// - starknetVersion: The Starknet version of the block
+// Returns:
+// - *felt.Felt: the event commitment
+// - error: an error if the version can't be parsed
+func EventCommitment(transactions []rpc.TransactionWithReceipt, starknetVersion string) (*felt.Felt, error) {
+	version, err := parseStarknetVersion(starknetVersion)
+	if err != nil {
+		return nil, err
+	}
+	poseidon := compareVersions(version, poseidonBlockVersion) >= 0
+	leaves := []*felt.Felt{}
+	for _, txn := range transactions {
+		for _, event := range txn.Receipt.Events {
+			if !poseidon {
+				leaves = append(leaves, crypto.PedersenArray(
+					event.FromAddress,
+					crypto.PedersenArray(event.Keys...),
+					crypto.PedersenArray(event.Data...),
+				))
+				continue
+			}
+			elems := []*felt.Felt{event.FromAddress, txn.Receipt.TransactionHash, new(felt.Felt).SetUint64(uint64(len(event.Keys)))}
+			elems = append(elems, event.Keys...)
+			elems = append(elems, new(felt.Felt).SetUint64(uint64(len(event.Data))))
+			elems = append(elems, event.Data...)
+			leaves = append(leaves, crypto.PoseidonArray(elems...))
+		}
+	}
+	if poseidon {
+		return commitmentRoot(leaves, crypto.Poseidon), nil
+	}
+	return commitmentRoot(leaves, crypto.Pedersen), nil
+}
+
+// ReceiptCommitment computes the root of the trie of the receipts of a
+// block, part of the block hash since Starknet v0.13.2.
+//
+// Parameters:
+// - transactions: The transactions of the block with their receipts, in order
+// - starknetVersion: The Starknet version of the block
+// Returns:
+// - *felt.Felt: the receipt commitment
+// - error: an error if the version can't be parsed or is older than v0.13.2
+func ReceiptCommitment(transactions []rpc.TransactionWithReceipt, starknetVersion string) (*felt.Felt, error) {
+	version, err := parseStarknetVersion(starknetVersion)
+	if err != nil {
+		return nil, err
+	}
+	if compareVersions(version, poseidonBlockVersion) < 0 {
+		return nil, fmt.Errorf("%w: no receipt commitment before Starknet 0.13.2", ErrUnsupportedBlockVersion)
+	}
+	return receiptCommitment(transactions), nil
+}
+
+// receiptCommitment computes the receipt commitment. The l2 gas consumed is
+// hashed as a zero, even since Starknet v0.13.4.
+func receiptCommitment(transactions []rpc.TransactionWithReceipt) *felt.Felt {
+	leaves := make([]*felt.Felt, len(transactions))
+	for i, txn := range transactions {
+		receipt := txn.Receipt
+		revertReasonHash := &felt.Zero
+		if receipt.ExecutionStatus == rpc.TxnExecutionStatusREVERTED {
+			// StarknetKeccak never fails
+			revertReasonHash, _ = crypto.StarknetKeccak([]byte(receipt.RevertReason))
+		}
+		leaves[i] = crypto.PoseidonArray(
+			receipt.TransactionHash,
+			receipt.ActualFee.Amount,
+			messagesSentHash(receipt.MessagesSent),
+			revertReasonHash,
+			&felt.Zero,
+			new(felt.Felt).SetUint64(uint64(receipt.ExecutionResources.L1Gas)),
+			new(felt.Felt).SetUint64(uint64(receipt.ExecutionResources.L1DataGas)),
+		)
+	}
+	return commitmentRoot(leaves, crypto.Poseidon)
+}
+
+// messagesSentHash hashes the messages sent to L1 by a transaction.
+func messagesSentHash(messages []rpc.MsgToL1) *felt.Felt {
+	elems := []*felt.Felt{new(felt.Felt).SetUint64(uint64(len(messages)))}
+	for _, message := range messages {
+		elems = append(elems, message.FromAddress, message.ToAddress, new(felt.Felt).SetUint64(uint64(len(message.Payload))))
+		elems = append(elems, message.Payload...)
+	}
+	return crypto.PoseidonArray(elems...)
+}
+
+// StateDiffCommitment computes the hash of a state diff, part of the block
+// hash since Starknet v0.13.2. The entries are sorted, so their order in the
+// state diff doesn't matter.
+//
+// Parameters:
+// - diff: The state diff of a block
+// Returns:
+// - *felt.Felt: the state diff commitment
+func StateDiffCommitment(diff rpc.StateDiff) *felt.Felt {
+	elems := []*felt.Felt{stateDiffPrefix}
+
+	// the deployed contracts and the replaced classes are hashed together
+	updated := make([][2]*felt.Felt, 0, len(diff.DeployedContracts)+len(diff.ReplacedClasses))
+	for _, deployed := range diff.DeployedContracts {
+		updated = append(updated, [2]*felt.Felt{deployed.Address, deployed.ClassHash})
+	}
+	for _, replaced := range diff.ReplacedClasses {
+		updated = append(updated, [2]*felt.Felt{replaced.ContractClass, replaced.ClassHash})
+	}
+	elems = appendSortedPairs(elems, updated)
+
+	declared := make([][2]*felt.Felt, len(diff.DeclaredClasses))
+	for i, class := range diff.DeclaredClasses {
+		declared[i] = [2]*felt.Felt{class.ClassHash, class.CompiledClassHash}
+	}
+	elems = appendSortedPairs(elems, declared)
+
+	deprecated := append([]*felt.Felt(nil), diff.DeprecatedDeclaredClasses...)
+	sort.Slice(deprecated, func(i, j int) bool { return deprecated[i].Cmp(deprecated[j]) < 0 })
+	elems = append(elems, new(felt.Felt).SetUint64(uint64(len(deprecated))))
+	elems = append(elems, deprecated...)
+
+	// placeholders of the data availability modes
+	elems = append(elems, new(felt.Felt).SetUint64(1), &felt.Zero)
+
+	storageDiffs := append([]rpc.ContractStorageDiffItem(nil), diff.StorageDiffs...)
+	sort.Slice(storageDiffs, func(i, j int) bool { return storageDiffs[i].Address.Cmp(storageDiffs[j].Address) < 0 })
+	elems = append(elems, new(felt.Felt).SetUint64(uint64(len(storageDiffs))))
+	for _, contract := range storageDiffs {
+		entries := make([][2]*felt.Felt, len(contract.StorageEntries))
+		for i, entry := range contract.StorageEntries {
+			entries[i] = [2]*felt.Felt{entry.Key, entry.Value}
+		}
+		elems = append(elems, contract.Address)
+		elems = appendSortedPairs(elems, entries)
+	}
+
+	nonces := make([][2]*felt.Felt, len(diff.Nonces))
+	for i, nonce := range diff.Nonces {
+		nonces[i] = [2]*felt.Felt{nonce.ContractAddress, nonce.Nonce}
+	}
+	elems = appendSortedPairs(elems, nonces)
+
+	return crypto.PoseidonArray(elems...)
+}
+
+// appendSortedPairs appends the number of pairs, then the pairs sorted by their first element.
+func appendSortedPairs(elems []*felt.Felt, pairs [][2]*felt.Felt) []*felt.Felt {
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i][0].Cmp(pairs[j][0]) < 0 })
+	elems = append(elems, new(felt.Felt).SetUint64(uint64(len(pairs))))
+	for _, pair := range pairs {
+		elems = append(elems, pair[0], pair[1])
+	}
+	return elems
+}
+
+// StateDiffLength returns the number of entries of a state diff, part of
+// the block hash since Starknet v0.13.2.
+//
+// Parameters:
+// - diff: The state diff of a block
+// Returns:
+// - uint64: the number of storage entries, nonces, deployed contracts, replaced and declared classes
+func StateDiffLength(diff rpc.StateDiff) uint64 {
+	length := len(diff.DeprecatedDeclaredClasses) + len(diff.DeclaredClasses) +
+		len(diff.DeployedContracts) + len(diff.ReplacedClasses) + len(diff.Nonces)
+	for _, contract := range diff.StorageDiffs {
+		length += len(contract.StorageEntries)
+	}
+	return uint64(length)
+}
+
+// concatCounts packs the transaction, event and state diff counts of a block
+// in a felt, along with its data availability mode.
+func concatCounts(txCount, eventCount, stateDiffLength uint64, mode rpc.L1DAMode) *felt.Felt {
+	var buf [32]byte
+	binary.BigEndian.PutUint64(buf[0:8], txCount)
+	binary.BigEndian.PutUint64(buf[8:16], eventCount)
+	binary.BigEndian.PutUint64(buf[16:24], stateDiffLength)
+	if mode == rpc.L1DAModeBlob {
+		buf[24] = 0b1000_0000
+	}
+	return new(felt.Felt).SetBytes(buf[:])
+}
+
+// gasPricesHash hashes the L1, L1 data and L2 gas prices of a block, part of
+// the block hash since Starknet v0.13.4.
+func gasPricesHash(header rpc.BlockHeader) *felt.Felt {
+	l2GasPrice := rpc.ResourcePrice{}
+	if header.L2GasPrice != nil {
+		l2GasPrice = *header.L2GasPrice
+	}
+	return crypto.PoseidonArray(
+		gasPricesPrefix,
+		feltOrZero(header.L1GasPrice.PriceInWei),
+		feltOrZero(header.L1GasPrice.PriceInFRI),
+		feltOrZero(header.L1DataGasPrice.PriceInWei),
+		feltOrZero(header.L1DataGasPrice.PriceInFRI),
+		feltOrZero(l2GasPrice.PriceInWei),
+		feltOrZero(l2GasPrice.PriceInFRI),
+	)
+}
+
+// transactionSignature returns the signature of a transaction, nil for the
+// transactions without one.
+func transactionSignature(txn rpc.Transaction) []*felt.Felt {
+	switch tx := txn.(type) {
+	case rpc.InvokeTxnV0:
+		return tx.Signature
+	case rpc.InvokeTxnV1:
+		return tx.Signature
+	case rpc.InvokeTxnV3:
+		return tx.Signature
+	case rpc.DeclareTxnV0:
+		return tx.Signature
+	case rpc.DeclareTxnV1:
+		return tx.Signature
+	case rpc.DeclareTxnV2:
+		return tx.Signature
+	case rpc.DeclareTxnV3:
+		return tx.Signature
+	case rpc.DeployAccountTxn:
+		return tx.Signature
+	case rpc.DeployAccountTxnV3:
+		return tx.Signature
+	}
+	return nil
+}
+
+// parseStarknetVersion parses a Starknet version such as "0.13.2" or
+// "0.11.0.2". An empty version is the one of the oldest blocks, 0.0.0.
+func parseStarknetVersion(version string) ([4]uint64, error) {
+	var parsed [4]uint64
+	if version == "" {
+		return parsed, nil
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) > len(parsed) {
+		return parsed, fmt.Errorf("invalid Starknet version %q", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return parsed, fmt.Errorf("invalid Starknet version %q", version)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
+
+// compareVersions returns -1, 0 or 1 if a is older than, the same as or newer than b.
+func compareVersions(a, b [4]uint64) int {
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// feltOrZero returns f, or zero if f is nil.
+func feltOrZero(f *felt.Felt) *felt.Felt {
+	if f == nil {
+		return &felt.Zero
+	}
+	return f
+}
+
+// equalFelts reports whether a and b are equal, nil being equal to nil only.
+func equalFelts(a, b *felt.Felt) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(b)
+}
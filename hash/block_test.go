@@ -0,0 +1,147 @@
+package hash_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBlockHashPedersen tests the hash of a block older than Starknet v0.13.2 against the one of the network.
+func TestBlockHashPedersen(t *testing.T) {
+	content, err := os.ReadFile("./tests/integration332275.json")
+	require.NoError(t, err)
+	var response struct {
+		Result rpc.BlockWithReceipts `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(content, &response))
+	block := response.Result
+
+	blockHash, err := hash.BlockHash(block.BlockHeader, block.Transactions, nil)
+	require.NoError(t, err)
+	require.Equal(t, block.BlockHash, blockHash)
+	require.NoError(t, hash.VerifyBlock(&block, nil))
+
+	block.Transactions[0].Receipt.Events[0].Data[0] = new(felt.Felt).SetUint64(1)
+	require.ErrorIs(t, hash.VerifyBlock(&block, nil), hash.ErrBlockHashMismatch)
+
+	block.StarknetVersion = "0.6.2"
+	_, err = hash.BlockHash(block.BlockHeader, block.Transactions, nil)
+	require.ErrorIs(t, err, hash.ErrUnsupportedBlockVersion)
+}
+
+// TestTransactionCommitment tests the edges and the binary nodes of the trie of the transactions.
+func TestTransactionCommitment(t *testing.T) {
+	txns := make([]rpc.TransactionWithReceipt, 3)
+	leaves := make([]*felt.Felt, 3)
+	for i := range txns {
+		txHash := new(felt.Felt).SetUint64(uint64(i + 1))
+		txns[i] = rpc.TransactionWithReceipt{
+			Transaction: rpc.UnknownTransaction{Transaction: rpc.L1HandlerTxn{Type: rpc.TransactionType_L1Handler}},
+			Receipt:     rpc.TransactionReceipt{TransactionHash: txHash},
+		}
+		// the transactions without signature commit to a zero signature
+		leaves[i] = crypto.PoseidonArray(txHash, &felt.Zero)
+	}
+	edge := func(child *felt.Felt, length uint64) *felt.Felt {
+		hash := crypto.Poseidon(child, &felt.Zero)
+		return hash.Add(hash, new(felt.Felt).SetUint64(length))
+	}
+
+	type testSetType struct {
+		Transactions []rpc.TransactionWithReceipt
+		ExpectedRoot *felt.Felt
+	}
+	testSet := []testSetType{
+		{Transactions: nil, ExpectedRoot: &felt.Zero},
+		{Transactions: txns[:1], ExpectedRoot: edge(leaves[0], 64)},
+		{Transactions: txns[:2], ExpectedRoot: edge(crypto.Poseidon(leaves[0], leaves[1]), 63)},
+		{Transactions: txns, ExpectedRoot: edge(crypto.Poseidon(crypto.Poseidon(leaves[0], leaves[1]), edge(leaves[2], 1)), 62)},
+	}
+	for _, test := range testSet {
+		root, err := hash.TransactionCommitment(test.Transactions, "0.13.2")
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedRoot, root)
+	}
+
+	// since Starknet v0.13.4, the transactions without signature commit to their hash alone
+	root, err := hash.TransactionCommitment(txns[:1], "0.13.4")
+	require.NoError(t, err)
+	require.Equal(t, edge(crypto.PoseidonArray(txns[0].Receipt.TransactionHash), 64), root)
+}
+
+// TestVerifyBlockPoseidon tests the hashes of blocks of Starknet v0.13.2 and
+// v0.13.4 against the ones of the network, and that they commit to their
+// receipts, their state diff and their gas prices.
+func TestVerifyBlockPoseidon(t *testing.T) {
+	type testSetType struct {
+		BlockPath       string
+		StateUpdatePath string
+	}
+	testSet := []testSetType{
+		{
+			// an L1 handler without signature, a deploy account and invokes v1 and v3
+			BlockPath:       "./tests/integration35749.json",
+			StateUpdatePath: "./tests/integration35749_state_update.json",
+		},
+		{
+			// the first version hashing the l2 gas price
+			BlockPath:       "./tests/integration64164.json",
+			StateUpdatePath: "./tests/integration64164_state_update.json",
+		},
+	}
+	for _, test := range testSet {
+		content, err := os.ReadFile(test.BlockPath)
+		require.NoError(t, err)
+		var blockResponse struct {
+			Result rpc.BlockWithReceipts `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal(content, &blockResponse))
+		block := blockResponse.Result
+		content, err = os.ReadFile(test.StateUpdatePath)
+		require.NoError(t, err)
+		var updateResponse struct {
+			Result rpc.StateUpdateOutput `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal(content, &updateResponse))
+		update := updateResponse.Result
+
+		blockHash, err := hash.BlockHash(block.BlockHeader, block.Transactions, &update.StateDiff)
+		require.NoError(t, err, test.BlockPath)
+		require.Equal(t, block.BlockHash, blockHash, test.BlockPath)
+		require.NoError(t, hash.VerifyBlock(&block, &update))
+
+		_, err = hash.BlockHash(block.BlockHeader, block.Transactions, nil)
+		require.Error(t, err)
+
+		// the state diff commitment doesn't depend on the order of the entries
+		reordered := update.StateDiff
+		reordered.StorageDiffs = append([]rpc.ContractStorageDiffItem(nil), update.StateDiff.StorageDiffs...)
+		last := len(reordered.StorageDiffs) - 1
+		reordered.StorageDiffs[0], reordered.StorageDiffs[last] = reordered.StorageDiffs[last], reordered.StorageDiffs[0]
+		require.Equal(t, hash.StateDiffCommitment(update.StateDiff), hash.StateDiffCommitment(reordered))
+
+		// the receipts, the state diff and the gas prices are committed to
+		l1DataGas := block.Transactions[0].Receipt.ExecutionResources.L1DataGas
+		block.Transactions[0].Receipt.ExecutionResources.L1DataGas++
+		require.ErrorIs(t, hash.VerifyBlock(&block, &update), hash.ErrBlockHashMismatch)
+		block.Transactions[0].Receipt.ExecutionResources.L1DataGas = l1DataGas
+		nonce := update.StateDiff.Nonces[0].Nonce
+		update.StateDiff.Nonces[0].Nonce = new(felt.Felt).SetUint64(1)
+		require.ErrorIs(t, hash.VerifyBlock(&block, &update), hash.ErrBlockHashMismatch)
+		update.StateDiff.Nonces[0].Nonce = nonce
+		price := block.L1DataGasPrice.PriceInFRI
+		block.L1DataGasPrice.PriceInFRI = new(felt.Felt).SetUint64(1)
+		require.ErrorIs(t, hash.VerifyBlock(&block, &update), hash.ErrBlockHashMismatch)
+		block.L1DataGasPrice.PriceInFRI = price
+		require.NoError(t, hash.VerifyBlock(&block, &update))
+
+		update.NewRoot = new(felt.Felt).SetUint64(1)
+		require.ErrorIs(t, hash.VerifyBlock(&block, &update), hash.ErrStateUpdateMismatch)
+	}
+}
@@ -0,0 +1,27 @@
+package hash
+
+import (
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/merkle"
+)
+
+// commitmentHeight is the height of the Patricia tries of the block commitments.
+const commitmentHeight = 64
+
+// commitmentRoot computes the root of a height-64 binary Patricia trie
+// whose leaves are the values, keyed by their index, as the transaction,
+// event and receipt commitments are. An empty trie has a zero root.
+//
+// Parameters:
+// - values: The leaves of the trie, in the order of their index
+// - hashFn: The hash of the trie, Pedersen or Poseidon
+// Returns:
+// - *felt.Felt: the root of the trie
+func commitmentRoot(values []*felt.Felt, hashFn merkle.HashFunc) *felt.Felt {
+	trie := merkle.NewTrie(commitmentHeight, hashFn)
+	for i, value := range values {
+		// the indexes always fit in the height of the trie
+		_ = trie.Insert(new(felt.Felt).SetUint64(uint64(i)), value)
+	}
+	return trie.Root()
+}
@@ -0,0 +1,178 @@
+package hash
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/merkle"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// ErrStateRootMismatch is matched with errors.Is by the errors of the proof
+// verifiers when the global roots of a proof don't hash to the trusted state root.
+var ErrStateRootMismatch = errors.New("the global roots don't match the state root")
+
+var stateRootPrefix = new(felt.Felt).SetBytes([]byte("STARKNET_STATE_V0"))
+
+// StateRoot computes the global state root of a block from the roots of its
+// contracts and classes tries. Before the classes trie existed, the state
+// root is the root of the contracts trie.
+//
+// Parameters:
+// - contractsRoot: The root of the contracts trie
+// - classesRoot: The root of the classes trie
+// Returns:
+// - *felt.Felt: the global state root
+func StateRoot(contractsRoot, classesRoot *felt.Felt) *felt.Felt {
+	if classesRoot == nil || classesRoot.IsZero() {
+		return new(felt.Felt).Set(feltOrZero(contractsRoot))
+	}
+	return curve.Curve.PoseidonArray(stateRootPrefix, contractsRoot, classesRoot)
+}
+
+// ContractLeafHash computes the leaf of a contract in the contracts trie.
+//
+// Parameters:
+// - leaf: The class hash, nonce and storage root of the contract
+// Returns:
+// - *felt.Felt: the hash of the leaf
+func ContractLeafHash(leaf rpc.ContractLeafData) *felt.Felt {
+	h := curve.Curve.Pedersen(feltOrZero(leaf.ClassHash), feltOrZero(leaf.StorageRoot))
+	h = curve.Curve.Pedersen(h, feltOrZero(leaf.Nonce))
+	return curve.Curve.Pedersen(h, &felt.Zero)
+}
+
+// VerifyContractProof checks the proof of a contract returned by
+// starknet_getStorageProof against a trusted state root, such as the one of
+// a verified block header, and returns the proven class hash, nonce and
+// storage root of the contract. The leaf of a contract that isn't deployed
+// is zero.
+//
+// Parameters:
+// - stateRoot: The trusted global state root of the block
+// - contractAddress: The address of the contract, among the requested ones
+// - proof: The storage proof answered by the node
+// Returns:
+// - *rpc.ContractLeafData: the proven leaf of the contract
+// - error: an error matching ErrStateRootMismatch or merkle.ErrInvalidProof if the proof doesn't hold
+func VerifyContractProof(stateRoot, contractAddress *felt.Felt, proof *rpc.StorageProof) (*rpc.ContractLeafData, error) {
+	roots := proof.GlobalRoots
+	if !StateRoot(roots.ContractsTreeRoot, roots.ClassesTreeRoot).Equal(stateRoot) {
+		return nil, ErrStateRootMismatch
+	}
+	contractsRoot := feltOrZero(roots.ContractsTreeRoot)
+	path, err := proofPath(contractsRoot, contractAddress, proof.ContractsProof.Nodes)
+	if err != nil {
+		return nil, fmt.Errorf("contract %s: %w", contractAddress, err)
+	}
+	leafHash, err := merkle.VerifyProof(contractsRoot, contractAddress, path, merkle.StorageTrieHeight, merkle.PedersenHash)
+	if err != nil {
+		return nil, fmt.Errorf("contract %s: %w", contractAddress, err)
+	}
+	if leafHash.IsZero() {
+		return &rpc.ContractLeafData{Nonce: new(felt.Felt), ClassHash: new(felt.Felt), StorageRoot: new(felt.Felt)}, nil
+	}
+	for _, leaf := range proof.ContractsProof.ContractLeavesData {
+		if ContractLeafHash(leaf).Equal(leafHash) {
+			return &rpc.ContractLeafData{
+				Nonce:       new(felt.Felt).Set(feltOrZero(leaf.Nonce)),
+				ClassHash:   new(felt.Felt).Set(feltOrZero(leaf.ClassHash)),
+				StorageRoot: new(felt.Felt).Set(feltOrZero(leaf.StorageRoot)),
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("contract %s: %w: no contract leaf data hashes to %s", contractAddress, merkle.ErrInvalidProof, leafHash)
+}
+
+// VerifyStorageProof checks the proof of a storage slot returned by
+// starknet_getStorageProof against a trusted state root, going through the
+// contracts trie to the leaf of the contract, then through its storage trie
+// to the slot, and returns the proven value. The value of a slot that was
+// never written is zero.
+//
+// Parameters:
+// - stateRoot: The trusted global state root of the block
+// - contractAddress: The address of the contract, among the requested ones
+// - key: The storage key, among the requested ones of the contract
+// - proof: The storage proof answered by the node
+// Returns:
+// - *felt.Felt: the proven value of the slot
+// - error: an error matching ErrStateRootMismatch or merkle.ErrInvalidProof if the proof doesn't hold
+func VerifyStorageProof(stateRoot, contractAddress, key *felt.Felt, proof *rpc.StorageProof) (*felt.Felt, error) {
+	leaf, err := VerifyContractProof(stateRoot, contractAddress, proof)
+	if err != nil {
+		return nil, err
+	}
+	// the nodes are looked up by hash, the lists of all the contracts can be searched
+	var nodes []rpc.NodeHashToNode
+	for _, contractNodes := range proof.ContractsStorageProofs {
+		nodes = append(nodes, contractNodes...)
+	}
+	path, err := proofPath(leaf.StorageRoot, key, nodes)
+	if err != nil {
+		return nil, fmt.Errorf("contract %s, storage key %s: %w", contractAddress, key, err)
+	}
+	value, err := merkle.VerifyProof(leaf.StorageRoot, key, path, merkle.StorageTrieHeight, merkle.PedersenHash)
+	if err != nil {
+		return nil, fmt.Errorf("contract %s, storage key %s: %w", contractAddress, key, err)
+	}
+	return value, nil
+}
+
+// proofPath orders the nodes of a proof, which the nodes answer keyed by
+// hash, into the path from the root of a storage trie down to a key. The
+// path stops at the first edge diverging from the key. The hashes of the
+// nodes are checked by merkle.VerifyProof.
+func proofPath(root, key *felt.Felt, nodes []rpc.NodeHashToNode) ([]merkle.ProofNode, error) {
+	path := []merkle.ProofNode{}
+	if root.IsZero() {
+		return path, nil
+	}
+	byHash := make(map[felt.Felt]rpc.MerkleNode, len(nodes))
+	for _, node := range nodes {
+		if node.NodeHash != nil {
+			byHash[*node.NodeHash] = node.Node
+		}
+	}
+
+	const height = merkle.StorageTrieHeight
+	bits := key.BigInt(new(big.Int))
+	if bits.BitLen() > height {
+		return nil, merkle.ErrKeyOutOfRange
+	}
+	expected := root
+	for depth := uint(0); depth < height; {
+		node, ok := byHash[*expected]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing the node %s at depth %d", merkle.ErrInvalidProof, expected, depth)
+		}
+		if !node.IsEdge() {
+			if node.Left == nil || node.Right == nil {
+				return nil, fmt.Errorf("%w: binary node %s without children", merkle.ErrInvalidProof, expected)
+			}
+			path = append(path, merkle.ProofNode{Left: node.Left, Right: node.Right})
+			if bits.Bit(int(height-1-depth)) == 0 {
+				expected = node.Left
+			} else {
+				expected = node.Right
+			}
+			depth++
+			continue
+		}
+		if node.Path == nil || node.Length == 0 || depth+node.Length > height {
+			return nil, fmt.Errorf("%w: invalid edge node %s at depth %d", merkle.ErrInvalidProof, expected, depth)
+		}
+		path = append(path, merkle.ProofNode{Path: node.Path, Length: uint8(node.Length), Child: node.Child})
+		keyPath := new(big.Int).Rsh(bits, height-depth-node.Length)
+		keyPath.And(keyPath, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), node.Length), big.NewInt(1)))
+		if keyPath.Cmp(node.Path.BigInt(new(big.Int))) != 0 {
+			break
+		}
+		expected = node.Child
+		depth += node.Length
+	}
+	return path, nil
+}
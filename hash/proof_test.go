@@ -0,0 +1,142 @@
+package hash_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/merkle"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// proofNodes converts the nodes of a merkle proof to the ones of a storage proof answer.
+func proofNodes(t *testing.T, proof []merkle.ProofNode) []rpc.NodeHashToNode {
+	nodes := make([]rpc.NodeHashToNode, len(proof))
+	for i, node := range proof {
+		nodeHash, err := node.Hash(merkle.PedersenHash)
+		require.NoError(t, err)
+		nodes[i] = rpc.NodeHashToNode{
+			NodeHash: nodeHash,
+			Node:     rpc.MerkleNode{Left: node.Left, Right: node.Right, Path: node.Path, Length: uint(node.Length), Child: node.Child},
+		}
+	}
+	return nodes
+}
+
+// TestVerifyStorageProof tests the proofs of contracts and storage slots, and the rejection of the lying ones.
+func TestVerifyStorageProof(t *testing.T) {
+	contract := utils.TestHexToFelt(t, "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+	undeployed := utils.TestHexToFelt(t, "0x1234")
+	keys := utils.TestHexArrToFelt(t, []string{"0x5", "0x6", "0x3c1"})
+
+	storage := merkle.NewTrie(merkle.StorageTrieHeight, merkle.PedersenHash)
+	require.NoError(t, storage.Insert(keys[0], utils.TestHexToFelt(t, "0x64")))
+	require.NoError(t, storage.Insert(keys[1], utils.TestHexToFelt(t, "0x65")))
+	leaf := rpc.ContractLeafData{
+		Nonce:       utils.TestHexToFelt(t, "0x3"),
+		ClassHash:   utils.TestHexToFelt(t, "0x7b3e05f48f0c69e4a65ce5e076a66271a527aff2c34ce1083ec6e1526997a69"),
+		StorageRoot: storage.Root(),
+	}
+
+	contracts := merkle.NewTrie(merkle.StorageTrieHeight, merkle.PedersenHash)
+	require.NoError(t, contracts.Insert(contract, hash.ContractLeafHash(leaf)))
+	require.NoError(t, contracts.Insert(utils.TestHexToFelt(t, "0x1"), utils.TestHexToFelt(t, "0x99")))
+	classesRoot := utils.TestHexToFelt(t, "0x5a7")
+	stateRoot := hash.StateRoot(contracts.Root(), classesRoot)
+
+	proof := rpc.StorageProof{
+		ContractsProof:         rpc.ContractsProof{ContractLeavesData: []rpc.ContractLeafData{leaf}},
+		ContractsStorageProofs: [][]rpc.NodeHashToNode{{}},
+		GlobalRoots:            rpc.GlobalRoots{ContractsTreeRoot: contracts.Root(), ClassesTreeRoot: classesRoot},
+	}
+	for _, address := range []*felt.Felt{contract, undeployed} {
+		nodes, err := contracts.Prove(address)
+		require.NoError(t, err)
+		proof.ContractsProof.Nodes = append(proof.ContractsProof.Nodes, proofNodes(t, nodes)...)
+	}
+	for _, key := range keys {
+		nodes, err := storage.Prove(key)
+		require.NoError(t, err)
+		proof.ContractsStorageProofs[0] = append(proof.ContractsStorageProofs[0], proofNodes(t, nodes)...)
+	}
+
+	proven, err := hash.VerifyContractProof(stateRoot, contract, &proof)
+	require.NoError(t, err)
+	require.Equal(t, leaf, *proven)
+	proven, err = hash.VerifyContractProof(stateRoot, undeployed, &proof)
+	require.NoError(t, err)
+	require.Equal(t, &felt.Zero, proven.ClassHash)
+
+	type testSetType struct {
+		Key   *felt.Felt
+		Value *felt.Felt
+	}
+	testSet := []testSetType{
+		{Key: keys[0], Value: utils.TestHexToFelt(t, "0x64")},
+		{Key: keys[1], Value: utils.TestHexToFelt(t, "0x65")},
+		{Key: keys[2], Value: &felt.Zero},
+	}
+	for _, test := range testSet {
+		value, err := hash.VerifyStorageProof(stateRoot, contract, test.Key, &proof)
+		require.NoError(t, err)
+		require.Equal(t, test.Value, value)
+	}
+
+	// another state root
+	_, err = hash.VerifyStorageProof(contracts.Root(), contract, keys[0], &proof)
+	require.ErrorIs(t, err, hash.ErrStateRootMismatch)
+
+	// a lying nonce
+	proof.ContractsProof.ContractLeavesData[0].Nonce = utils.TestHexToFelt(t, "0x4")
+	_, err = hash.VerifyContractProof(stateRoot, contract, &proof)
+	require.ErrorIs(t, err, merkle.ErrInvalidProof)
+	proof.ContractsProof.ContractLeavesData[0].Nonce = leaf.Nonce
+
+	// a lying value, the node keeping its hash
+	storageNodes := proof.ContractsStorageProofs[0]
+	for i := range storageNodes {
+		if storageNodes[i].Node.IsEdge() && storageNodes[i].Node.Child.Equal(utils.TestHexToFelt(t, "0x64")) {
+			storageNodes[i].Node.Child = utils.TestHexToFelt(t, "0x65")
+		} else if !storageNodes[i].Node.IsEdge() && storageNodes[i].Node.Left.Equal(utils.TestHexToFelt(t, "0x64")) {
+			storageNodes[i].Node.Left = utils.TestHexToFelt(t, "0x65")
+		}
+	}
+	_, err = hash.VerifyStorageProof(stateRoot, contract, keys[0], &proof)
+	require.ErrorIs(t, err, merkle.ErrInvalidProof)
+
+	// a missing node
+	proof.ContractsStorageProofs = nil
+	_, err = hash.VerifyStorageProof(stateRoot, contract, keys[0], &proof)
+	require.ErrorIs(t, err, merkle.ErrInvalidProof)
+}
+
+// TestVerifyStorageProofMainnet tests the proof of a storage slot of the mainnet block 2 against its state root.
+func TestVerifyStorageProofMainnet(t *testing.T) {
+	content, err := os.ReadFile("./tests/mainnet2_storage_proof.json")
+	require.NoError(t, err)
+	var response struct {
+		Result rpc.StorageProof `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(content, &response))
+	proof := response.Result
+
+	// the state_root of the block 0x4e1f77f39545afe866ac151ac908bd1a347a2a8a7d58bef1276db4f06fdf2f6
+	stateRoot := utils.TestHexToFelt(t, "0x3ceee867d50b5926bb88c0ec7e0b9c20ae6b537e74aac44b8fcf6bb6da138d9")
+	contract := utils.TestHexToFelt(t, "0x2d6c9569dea5f18628f1ef7c15978ee3093d2d3eec3b893aac08004e678ead3")
+	key := utils.TestHexToFelt(t, "0x7f93985c1baa5bd9b2200dd2151821bd90abb87186d0be295d7d4b9bc8ca41f")
+
+	leaf, err := hash.VerifyContractProof(stateRoot, contract, &proof)
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0x10455c752b86932ce552f2b0fe81a880746649b9aee7e0d842bf3f52378f9f8"), leaf.ClassHash)
+	value, err := hash.VerifyStorageProof(stateRoot, contract, key, &proof)
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0x127cd00a078199381403a33d315061123ce246c8e5f19aa7f66391a9d3bf7c6"), value)
+
+	// the state root of the block 1
+	_, err = hash.VerifyStorageProof(utils.TestHexToFelt(t, "0x525aed4da9cc6cce2de31ba79059546b0828903279e4eaa38768de33e2cac32"), contract, key, &proof)
+	require.ErrorIs(t, err, hash.ErrStateRootMismatch)
+}
@@ -0,0 +1,420 @@
+package hash
+
+import (
+	"errors"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	// ErrTxnTypeUnSupported is returned for the values that aren't transactions of the given kind.
+	ErrTxnTypeUnSupported = errors.New("unsupported transaction type")
+)
+
+var (
+	prefixInvoke        = new(felt.Felt).SetBytes([]byte("invoke"))
+	prefixDeclare       = new(felt.Felt).SetBytes([]byte("declare"))
+	prefixDeploy        = new(felt.Felt).SetBytes([]byte("deploy"))
+	prefixDeployAccount = new(felt.Felt).SetBytes([]byte("deploy_account"))
+	prefixL1Handler     = new(felt.Felt).SetBytes([]byte("l1_handler"))
+
+	// the selector of the constructor, the entry point of the deploy transactions
+	constructorSelector = utils.GetSelectorFromNameFelt("constructor")
+)
+
+// TransactionHash calculates the hash of any transaction, such as the
+// transactions returned by a node, without an account. The query versions,
+// 2^128 plus the version, used to estimate and simulate transactions, are
+// hashed as they are.
+//
+// Parameters:
+// - txn: The transaction, an rpc.Transaction value such as rpc.InvokeTxnV3
+// - chainID: The chain id, such as the felt of "SN_MAIN"
+// Returns:
+// - *felt.Felt: the hash of the transaction
+// - error: ErrTxnTypeUnSupported for an unknown transaction type, or an error if a field is missing or invalid
+func TransactionHash(txn rpc.Transaction, chainID *felt.Felt) (*felt.Felt, error) {
+	switch tx := txn.(type) {
+	case rpc.InvokeTxnV0, rpc.InvokeTxnV1, rpc.InvokeTxnV3:
+		return TransactionHashInvoke(tx, chainID)
+	case rpc.DeclareTxnV0, rpc.DeclareTxnV1, rpc.DeclareTxnV2, rpc.DeclareTxnV3:
+		return TransactionHashDeclare(tx, chainID)
+	case rpc.DeployAccountTxn:
+		address, err := contracts.PrecomputeAddress(&felt.Zero, tx.ContractAddressSalt, tx.ClassHash, tx.ConstructorCalldata)
+		if err != nil {
+			return nil, err
+		}
+		return TransactionHashDeployAccount(tx, address, chainID)
+	case rpc.DeployAccountTxnV3:
+		address, err := contracts.PrecomputeAddress(&felt.Zero, tx.ContractAddressSalt, tx.ClassHash, tx.ConstructorCalldata)
+		if err != nil {
+			return nil, err
+		}
+		return TransactionHashDeployAccount(tx, address, chainID)
+	case rpc.DeployTxn:
+		return TransactionHashDeploy(tx, chainID)
+	case rpc.L1HandlerTxn:
+		return TransactionHashL1Handler(tx, chainID)
+	}
+	return nil, ErrTxnTypeUnSupported
+}
+
+// TransactionHashInvoke calculates the hash of an invoke transaction.
+//
+// Parameters:
+// - tx: The transaction, an rpc.InvokeTxnV0, rpc.InvokeTxnV1 or rpc.InvokeTxnV3
+// - chainID: The chain id
+// Returns:
+// - *felt.Felt: the hash of the transaction
+// - error: ErrTxnTypeUnSupported if tx isn't an invoke transaction, or an error if a field is invalid
+func TransactionHashInvoke(tx rpc.InvokeTxnType, chainID *felt.Felt) (*felt.Felt, error) {
+	// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#v0_hash_calculation
+	switch txn := tx.(type) {
+	case rpc.InvokeTxnV0:
+		calldataHash, err := ComputeHashOnElementsFelt(txn.Calldata)
+		if err != nil {
+			return nil, err
+		}
+		version, err := versionFelt(txn.Version)
+		if err != nil {
+			return nil, err
+		}
+		return CalculateTransactionHashCommon(
+			prefixInvoke,
+			version,
+			txn.ContractAddress,
+			txn.EntryPointSelector,
+			calldataHash,
+			txn.MaxFee,
+			chainID,
+			[]*felt.Felt{},
+		)
+	case rpc.InvokeTxnV1:
+		calldataHash, err := ComputeHashOnElementsFelt(txn.Calldata)
+		if err != nil {
+			return nil, err
+		}
+		version, err := versionFelt(txn.Version)
+		if err != nil {
+			return nil, err
+		}
+		return CalculateTransactionHashCommon(
+			prefixInvoke,
+			version,
+			txn.SenderAddress,
+			&felt.Zero,
+			calldataHash,
+			txn.MaxFee,
+			chainID,
+			[]*felt.Felt{txn.Nonce},
+		)
+	case rpc.InvokeTxnV3:
+		// https://github.com/starknet-io/SNIPs/blob/main/SNIPS/snip-8.md#protocol-changes
+		version, err := versionFelt(txn.Version)
+		if err != nil {
+			return nil, err
+		}
+		daMode, err := dataAvailabilityMode(txn.FeeMode, txn.NonceDataMode)
+		if err != nil {
+			return nil, err
+		}
+		tipAndResourceHash, err := tipAndResourcesHash(txn.Tip, txn.ResourceBounds)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.PoseidonArray(
+			prefixInvoke,
+			version,
+			txn.SenderAddress,
+			tipAndResourceHash,
+			crypto.PoseidonArray(txn.PayMasterData...),
+			chainID,
+			txn.Nonce,
+			daMode,
+			crypto.PoseidonArray(txn.AccountDeploymentData...),
+			crypto.PoseidonArray(txn.Calldata...),
+		), nil
+	}
+	return nil, ErrTxnTypeUnSupported
+}
+
+// TransactionHashDeclare calculates the hash of a declare transaction.
+//
+// Parameters:
+// - tx: The transaction, an rpc.DeclareTxnV0, rpc.DeclareTxnV1, rpc.DeclareTxnV2 or rpc.DeclareTxnV3
+// - chainID: The chain id
+// Returns:
+// - *felt.Felt: the hash of the transaction
+// - error: ErrTxnTypeUnSupported if tx isn't a declare transaction, or an error if a field is invalid
+func TransactionHashDeclare(tx rpc.DeclareTxnType, chainID *felt.Felt) (*felt.Felt, error) {
+	switch txn := tx.(type) {
+	case rpc.DeclareTxnV0:
+		calldataHash, err := ComputeHashOnElementsFelt([]*felt.Felt{})
+		if err != nil {
+			return nil, err
+		}
+		version, err := versionFelt(txn.Version)
+		if err != nil {
+			return nil, err
+		}
+		return CalculateTransactionHashCommon(
+			prefixDeclare,
+			version,
+			txn.SenderAddress,
+			&felt.Zero,
+			calldataHash,
+			txn.MaxFee,
+			chainID,
+			[]*felt.Felt{txn.ClassHash},
+		)
+	case rpc.DeclareTxnV1:
+		calldataHash, err := ComputeHashOnElementsFelt([]*felt.Felt{txn.ClassHash})
+		if err != nil {
+			return nil, err
+		}
+		version, err := versionFelt(txn.Version)
+		if err != nil {
+			return nil, err
+		}
+		return CalculateTransactionHashCommon(
+			prefixDeclare,
+			version,
+			txn.SenderAddress,
+			&felt.Zero,
+			calldataHash,
+			txn.MaxFee,
+			chainID,
+			[]*felt.Felt{txn.Nonce},
+		)
+	case rpc.DeclareTxnV2:
+		calldataHash, err := ComputeHashOnElementsFelt([]*felt.Felt{txn.ClassHash})
+		if err != nil {
+			return nil, err
+		}
+		version, err := versionFelt(txn.Version)
+		if err != nil {
+			return nil, err
+		}
+		return CalculateTransactionHashCommon(
+			prefixDeclare,
+			version,
+			txn.SenderAddress,
+			&felt.Zero,
+			calldataHash,
+			txn.MaxFee,
+			chainID,
+			[]*felt.Felt{txn.Nonce, txn.CompiledClassHash},
+		)
+	case rpc.DeclareTxnV3:
+		// https://github.com/starknet-io/SNIPs/blob/main/SNIPS/snip-8.md#protocol-changes
+		version, err := versionFelt(txn.Version)
+		if err != nil {
+			return nil, err
+		}
+		daMode, err := dataAvailabilityMode(txn.FeeMode, txn.NonceDataMode)
+		if err != nil {
+			return nil, err
+		}
+		tipAndResourceHash, err := tipAndResourcesHash(txn.Tip, txn.ResourceBounds)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.PoseidonArray(
+			prefixDeclare,
+			version,
+			txn.SenderAddress,
+			tipAndResourceHash,
+			crypto.PoseidonArray(txn.PayMasterData...),
+			chainID,
+			txn.Nonce,
+			daMode,
+			crypto.PoseidonArray(txn.AccountDeploymentData...),
+			txn.ClassHash,
+			txn.CompiledClassHash,
+		), nil
+	}
+	return nil, ErrTxnTypeUnSupported
+}
+
+// TransactionHashDeployAccount calculates the hash of a deploy account transaction.
+//
+// Parameters:
+// - tx: The transaction, an rpc.DeployAccountTxn or rpc.DeployAccountTxnV3
+// - contractAddress: The address of the deployed account, see contracts.PrecomputeAddress
+// - chainID: The chain id
+// Returns:
+// - *felt.Felt: the hash of the transaction
+// - error: ErrTxnTypeUnSupported if tx isn't a deploy account transaction, or an error if a field is invalid
+func TransactionHashDeployAccount(tx rpc.DeployAccountType, contractAddress *felt.Felt, chainID *felt.Felt) (*felt.Felt, error) {
+	// https://docs.starknet.io/documentation/architecture_and_concepts/Network_Architecture/transactions/#deploy_account_transaction
+	switch txn := tx.(type) {
+	case rpc.DeployAccountTxn:
+		calldata := []*felt.Felt{txn.ClassHash, txn.ContractAddressSalt}
+		calldata = append(calldata, txn.ConstructorCalldata...)
+		calldataHash, err := ComputeHashOnElementsFelt(calldata)
+		if err != nil {
+			return nil, err
+		}
+		version, err := versionFelt(txn.Version)
+		if err != nil {
+			return nil, err
+		}
+		return CalculateTransactionHashCommon(
+			prefixDeployAccount,
+			version,
+			contractAddress,
+			&felt.Zero,
+			calldataHash,
+			txn.MaxFee,
+			chainID,
+			[]*felt.Felt{txn.Nonce},
+		)
+	case rpc.DeployAccountTxnV3:
+		version, err := versionFelt(txn.Version)
+		if err != nil {
+			return nil, err
+		}
+		daMode, err := dataAvailabilityMode(txn.FeeMode, txn.NonceDataMode)
+		if err != nil {
+			return nil, err
+		}
+		tipAndResourceHash, err := tipAndResourcesHash(txn.Tip, txn.ResourceBounds)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.PoseidonArray(
+			prefixDeployAccount,
+			version,
+			contractAddress,
+			tipAndResourceHash,
+			crypto.PoseidonArray(txn.PayMasterData...),
+			chainID,
+			txn.Nonce,
+			daMode,
+			crypto.PoseidonArray(txn.ConstructorCalldata...),
+			txn.ClassHash,
+			txn.ContractAddressSalt,
+		), nil
+	}
+	return nil, ErrTxnTypeUnSupported
+}
+
+// TransactionHashDeploy calculates the hash of a legacy deploy transaction.
+//
+// Parameters:
+// - txn: The transaction
+// - chainID: The chain id
+// Returns:
+// - *felt.Felt: the hash of the transaction
+// - error: an error if a field is invalid
+func TransactionHashDeploy(txn rpc.DeployTxn, chainID *felt.Felt) (*felt.Felt, error) {
+	contractAddress, err := contracts.PrecomputeAddress(&felt.Zero, txn.ContractAddressSalt, txn.ClassHash, txn.ConstructorCalldata)
+	if err != nil {
+		return nil, err
+	}
+	calldataHash, err := ComputeHashOnElementsFelt(txn.ConstructorCalldata)
+	if err != nil {
+		return nil, err
+	}
+	version, err := versionFelt(txn.Version)
+	if err != nil {
+		return nil, err
+	}
+	return CalculateTransactionHashCommon(
+		prefixDeploy,
+		version,
+		contractAddress,
+		constructorSelector,
+		calldataHash,
+		&felt.Zero,
+		chainID,
+		[]*felt.Felt{},
+	)
+}
+
+// TransactionHashL1Handler calculates the hash of an L1 handler transaction,
+// as hashed since Starknet v0.10 and the nonce of the L1 messages.
+//
+// Parameters:
+// - txn: The transaction
+// - chainID: The chain id
+// Returns:
+// - *felt.Felt: the hash of the transaction
+// - error: an error if a field is invalid
+func TransactionHashL1Handler(txn rpc.L1HandlerTxn, chainID *felt.Felt) (*felt.Felt, error) {
+	calldataHash, err := ComputeHashOnElementsFelt(txn.Calldata)
+	if err != nil {
+		return nil, err
+	}
+	version, err := versionFelt(rpc.TransactionVersion(txn.Version))
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := new(felt.Felt).SetString(txn.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	return CalculateTransactionHashCommon(
+		prefixL1Handler,
+		version,
+		txn.ContractAddress,
+		txn.EntryPointSelector,
+		calldataHash,
+		&felt.Zero,
+		chainID,
+		[]*felt.Felt{nonce},
+	)
+}
+
+// versionFelt returns the felt of a transaction version, the zero version
+// being the default of the transactions that omit it.
+func versionFelt(version rpc.TransactionVersion) (*felt.Felt, error) {
+	if version == "" {
+		return new(felt.Felt), nil
+	}
+	return new(felt.Felt).SetString(string(version))
+}
+
+// tipAndResourcesHash hashes the tip and the resource bounds of a v3
+// transaction, the l1 data gas bound being hashed when set.
+func tipAndResourcesHash(tip rpc.U64, resourceBounds rpc.ResourceBoundsMapping) (*felt.Felt, error) {
+	tipUint64, err := tip.ToUint64()
+	if err != nil {
+		return nil, err
+	}
+	l1Bytes, err := resourceBounds.L1Gas.Bytes(rpc.ResourceL1Gas)
+	if err != nil {
+		return nil, err
+	}
+	l2Bytes, err := resourceBounds.L2Gas.Bytes(rpc.ResourceL2Gas)
+	if err != nil {
+		return nil, err
+	}
+	elems := []*felt.Felt{new(felt.Felt).SetUint64(tipUint64), new(felt.Felt).SetBytes(l1Bytes), new(felt.Felt).SetBytes(l2Bytes)}
+	if resourceBounds.L1DataGas != nil {
+		l1DataBytes, err := resourceBounds.L1DataGas.Bytes(rpc.ResourceL1DataGas)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, new(felt.Felt).SetBytes(l1DataBytes))
+	}
+	return crypto.PoseidonArray(elems...), nil
+}
+
+// dataAvailabilityMode packs the data availability modes of a v3 transaction.
+func dataAvailabilityMode(feeDAMode, nonceDAMode rpc.DataAvailabilityMode) (*felt.Felt, error) {
+	const dataAvailabilityModeBits = 32
+	fee64, err := feeDAMode.UInt64()
+	if err != nil {
+		return nil, err
+	}
+	nonce64, err := nonceDAMode.UInt64()
+	if err != nil {
+		return nil, err
+	}
+	return new(felt.Felt).SetUint64(fee64 + nonce64<<dataAvailabilityModeBits), nil
+}
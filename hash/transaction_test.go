@@ -0,0 +1,191 @@
+package hash_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransactionHashFetched tests the hashes of the invoke transactions returned by a node.
+func TestTransactionHashFetched(t *testing.T) {
+	content, err := os.ReadFile("./tests/integration332275.json")
+	require.NoError(t, err)
+	var response struct {
+		Result rpc.BlockWithReceipts `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(content, &response))
+
+	chainID := new(felt.Felt).SetBytes([]byte("SN_GOERLI"))
+	for _, txn := range response.Result.Transactions {
+		txHash, err := hash.TransactionHash(txn.Transaction.Transaction, chainID)
+		require.NoError(t, err)
+		require.Equal(t, txn.Receipt.TransactionHash, txHash)
+	}
+}
+
+// TestTransactionHashDeployAccount tests that the address of the deployed account is derived from the transaction.
+func TestTransactionHashDeployAccount(t *testing.T) {
+	type testSetType struct {
+		Txn          rpc.Transaction
+		ExpectedHash *felt.Felt
+	}
+	testSet := []testSetType{
+		{
+			// https://sepolia.voyager.online/tx/0x66d1d9d50d308a9eb16efedbad208b0672769a545a0b828d357757f444e9188
+			Txn: rpc.DeployAccountTxn{
+				Nonce:               utils.TestHexToFelt(t, "0x0"),
+				Type:                rpc.TransactionType_DeployAccount,
+				MaxFee:              utils.TestHexToFelt(t, "0x1d2109b99cf94"),
+				Version:             rpc.TransactionV1,
+				ClassHash:           utils.TestHexToFelt(t, "0x1e60c8722677cfb7dd8dbea5be86c09265db02cdfe77113e77da7d44c017388"),
+				ContractAddressSalt: utils.TestHexToFelt(t, "0x15d621f9515c6197d3117eb1a25c7a4a669317be8f49831e03fcc00d855352e"),
+				ConstructorCalldata: utils.TestHexArrToFelt(t, []string{"0x960532cfba33384bbec41aa669727a9c51e995c87e101c86706aaf244f7e4e"}),
+			},
+			ExpectedHash: utils.TestHexToFelt(t, "0x66d1d9d50d308a9eb16efedbad208b0672769a545a0b828d357757f444e9188"),
+		},
+		{
+			// https://sepolia.voyager.online/tx/0x4bf28fb0142063f1b9725ae490c6949e6f1842c79b49f7cc674b7e3f5ad4875
+			Txn: rpc.DeployAccountTxnV3{
+				Nonce:   utils.TestHexToFelt(t, "0x0"),
+				Type:    rpc.TransactionType_DeployAccount,
+				Version: rpc.TransactionV3,
+				ResourceBounds: rpc.ResourceBoundsMapping{
+					L1Gas: rpc.ResourceBounds{MaxAmount: "0x38", MaxPricePerUnit: "0x7cd9b6080b35"},
+					L2Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+				},
+				Tip:                 "0x0",
+				PayMasterData:       []*felt.Felt{},
+				NonceDataMode:       rpc.DAModeL1,
+				FeeMode:             rpc.DAModeL1,
+				ClassHash:           utils.TestHexToFelt(t, "0x29927c8af6bccf3f6fda035981e765a7bdbf18a2dc0d630494f8758aa908e2b"),
+				ConstructorCalldata: utils.TestHexArrToFelt(t, []string{"0x1a09f0001cc46f82b1a805d07c13e235248a44ed13d87f170d7d925e3c86082", "0x0"}),
+				ContractAddressSalt: utils.TestHexToFelt(t, "0x1a09f0001cc46f82b1a805d07c13e235248a44ed13d87f170d7d925e3c86082"),
+			},
+			ExpectedHash: utils.TestHexToFelt(t, "0x4bf28fb0142063f1b9725ae490c6949e6f1842c79b49f7cc674b7e3f5ad4875"),
+		},
+	}
+	for _, test := range testSet {
+		txHash, err := hash.TransactionHash(test.Txn, new(felt.Felt).SetBytes([]byte("SN_SEPOLIA")))
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedHash, txHash)
+	}
+}
+
+// TestTransactionHashLegacy tests the hashes of the declare v0, deploy and L1 handler transactions of the mainnet.
+func TestTransactionHashLegacy(t *testing.T) {
+	type testSetType struct {
+		Txn          rpc.Transaction
+		ExpectedHash *felt.Felt
+	}
+	testSet := []testSetType{
+		{
+			// https://voyager.online/tx/0x222f8902d1eeea76fa2642a90e2411bfd71cffb299b3a299029e1937fab3fe4
+			Txn: rpc.DeclareTxnV0{
+				Type:          rpc.TransactionType_Declare,
+				SenderAddress: utils.TestHexToFelt(t, "0x1"),
+				MaxFee:        utils.TestHexToFelt(t, "0x0"),
+				Version:       rpc.TransactionV0,
+				Signature:     []*felt.Felt{},
+				ClassHash:     utils.TestHexToFelt(t, "0x2760f25d5a4fb2bdde5f561fd0b44a3dee78c28903577d37d669939d97036a0"),
+			},
+			ExpectedHash: utils.TestHexToFelt(t, "0x222f8902d1eeea76fa2642a90e2411bfd71cffb299b3a299029e1937fab3fe4"),
+		},
+		{
+			// https://voyager.online/tx/0x6486c6303dba2f364c684a2e9609211c5b8e417e767f37b527cda51e776e6f0
+			Txn: rpc.DeployTxn{
+				Type:                rpc.TransactionType_Deploy,
+				Version:             rpc.TransactionV0,
+				ClassHash:           utils.TestHexToFelt(t, "0x46f844ea1a3b3668f81d38b5c1bd55e816e0373802aefe732138628f0133486"),
+				ContractAddressSalt: utils.TestHexToFelt(t, "0x74dc2fe193daf1abd8241b63329c1123214842b96ad7fd003d25512598a956b"),
+				ConstructorCalldata: utils.TestHexArrToFelt(t, []string{
+					"0x6d706cfbac9b8262d601c38251c5fbe0497c3a96cc91a92b08d91b61d9e70c4",
+					"0x79dc0da7c54b95f10aa182ad0a46400db63156920adb65eca2654c0945a463",
+					"0x2",
+					"0x6658165b4984816ab189568637bedec5aa0a18305909c7f5726e4a16e3afef6",
+					"0x6b648b36b074a91eee55730f5f5e075ec19c0a8f9ffb0903cefeee93b6ff328",
+				}),
+			},
+			ExpectedHash: utils.TestHexToFelt(t, "0x6486c6303dba2f364c684a2e9609211c5b8e417e767f37b527cda51e776e6f0"),
+		},
+		{
+			// https://voyager.online/tx/0xc470e30f97f64255a62215633e35a7c6ae10332a9011776dde1143ab0202c3
+			Txn: rpc.L1HandlerTxn{
+				Type:    rpc.TransactionType_L1Handler,
+				Version: rpc.L1HandlerTxnVersionV0,
+				Nonce:   "0x195c3c",
+				FunctionCall: rpc.FunctionCall{
+					ContractAddress:    utils.TestHexToFelt(t, "0x38862e1b15526eda31ed6fd26805c40748458db8e420cb3be3bc65c332c023b"),
+					EntryPointSelector: utils.TestHexToFelt(t, "0x3593216f3a8b22f4cf375e5486e3d13bfde9d0f26976d20ac6f653c73f7e507"),
+					Calldata: utils.TestHexArrToFelt(t, []string{
+						"0x7ad94e71308bb65c6bc9df35cc69cc9f953d69e5",
+						"0xc3b49b03a6d9d71f8d3fa6582437374e650f3c46",
+						"0x3a1bf949fa7424b4bd48661a62ded82bc6f6e3c5f5c6d5904c07e6143187d1b",
+						"0x61",
+					}),
+				},
+			},
+			ExpectedHash: utils.TestHexToFelt(t, "0xc470e30f97f64255a62215633e35a7c6ae10332a9011776dde1143ab0202c3"),
+		},
+	}
+	for _, test := range testSet {
+		txHash, err := hash.TransactionHash(test.Txn, new(felt.Felt).SetBytes([]byte("SN_MAIN")))
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedHash, txHash)
+	}
+}
+
+// TestTransactionHashQueryVersion tests that the query versions are hashed as they are.
+func TestTransactionHashQueryVersion(t *testing.T) {
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+	// https://sepolia.voyager.online/tx/0x76b52e17bc09064bd986ead34263e6305ef3cecfb3ae9e19b86bf4f1a1a20ea
+	invoke := rpc.InvokeTxnV3{
+		Type:          rpc.TransactionType_Invoke,
+		SenderAddress: utils.TestHexToFelt(t, "0x745d525a3582e91299d8d7c71730ffc4b1f191f5b219d800334bc0edad0983b"),
+		Calldata: utils.TestHexArrToFelt(t, []string{
+			"0x1",
+			"0x4138fd51f90d171df37e9d4419c8cdb67d525840c58f8a5c347be93a1c5277d",
+			"0x2468d193cd15b621b24c2a602b8dbcfa5eaa14f88416c40c09d7fd12592cb4b",
+			"0x0",
+		}),
+		Version: rpc.TransactionV3,
+		Signature: utils.TestHexArrToFelt(t, []string{
+			"0x17bacc700df6c82682139e8e550078a5daa75dfe356577f78f7e57fd7c56245",
+			"0x4eb8734727eb9412b79ba6d14ff1c9a6beb0dc0b811e3f97168c747f8d427b3",
+		}),
+		Nonce: utils.TestHexToFelt(t, "0x9803"),
+		ResourceBounds: rpc.ResourceBoundsMapping{
+			L1Gas:     rpc.ResourceBounds{MaxAmount: "0x186a0", MaxPricePerUnit: "0x2d79883d20000"},
+			L2Gas:     rpc.ResourceBounds{MaxAmount: "0x5f5e100", MaxPricePerUnit: "0xba43b7400"},
+			L1DataGas: &rpc.ResourceBounds{MaxAmount: "0x186a0", MaxPricePerUnit: "0x2d79883d20000"},
+		},
+		Tip:                   "0x0",
+		PayMasterData:         []*felt.Felt{},
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         rpc.DAModeL1,
+		FeeMode:               rpc.DAModeL1,
+	}
+	txHash, err := hash.TransactionHash(invoke, chainID)
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0x76b52e17bc09064bd986ead34263e6305ef3cecfb3ae9e19b86bf4f1a1a20ea"), txHash)
+
+	// the query transactions never reach the chain, this hash is cross-checked with another implementation
+	invoke.Version = rpc.TransactionV3WithQueryBit
+	queryHash, err := hash.TransactionHash(invoke, chainID)
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0x2da57da613c8fd880752fc81c8fdf6ea6d076d8cdbb58f304a0aff9ceea9aec"), queryHash)
+
+	// the l1 data gas bound is hashed when set
+	invoke.ResourceBounds.L1DataGas = nil
+	withoutDataGas, err := hash.TransactionHash(invoke, chainID)
+	require.NoError(t, err)
+	require.NotEqual(t, queryHash, withoutDataGas)
+
+	_, err = hash.TransactionHash(&invoke, chainID)
+	require.ErrorIs(t, err, hash.ErrTxnTypeUnSupported)
+}
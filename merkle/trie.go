@@ -0,0 +1,368 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// StorageTrieHeight is the height of the tries of the Starknet state: the
+// global contracts trie, the classes trie and the storage tries of the contracts.
+const StorageTrieHeight = 251
+
+// HashFunc hashes two nodes of a Trie.
+type HashFunc func(a, b *felt.Felt) *felt.Felt
+
+var (
+	// PedersenHash is the hash of the contracts trie and of the storage tries.
+	PedersenHash HashFunc = crypto.Pedersen
+	// PoseidonHash is the hash of the classes trie and of the tries of the block commitments since Starknet v0.13.2.
+	PoseidonHash HashFunc = crypto.Poseidon
+)
+
+var (
+	// ErrKeyOutOfRange is returned for a key that doesn't fit in the height of the trie.
+	ErrKeyOutOfRange = errors.New("the key doesn't fit in the height of the trie")
+	// ErrInvalidProof is matched with errors.Is by the errors of VerifyProof.
+	ErrInvalidProof = errors.New("invalid merkle proof")
+)
+
+// Trie is a binary Patricia-Merkle trie as used by Starknet. The leaves are
+// keyed by the height bits of their key, most significant bit first, and the
+// paths without branches are compressed into edge nodes.
+//
+// A binary node hashes as H(left, right), an edge node as H(child, path) +
+// length, and the root of an empty trie is zero. Setting a leaf to zero
+// removes it, as in the Starknet state.
+type Trie struct {
+	height uint8
+	hash   HashFunc
+	leaves map[felt.Felt]*felt.Felt
+	// sorted the leaves sorted by key, nil when the leaves changed
+	sorted []trieLeaf
+	// root the cached root, nil when the leaves changed
+	root *felt.Felt
+}
+
+// trieLeaf is a leaf of a Trie.
+type trieLeaf struct {
+	key   *big.Int
+	value *felt.Felt
+}
+
+// ProofNode is a node of a merkle proof, from the root of a Trie down to a
+// leaf: a binary node with the hashes of its two children, or an edge node.
+type ProofNode struct {
+	// Left the hash of the left child of a binary node
+	Left *felt.Felt
+	// Right the hash of the right child of a binary node
+	Right *felt.Felt
+	// Path the bits of the path of an edge node
+	Path *felt.Felt
+	// Length the number of bits of the path of an edge node
+	Length uint8
+	// Child the hash of the node below an edge node
+	Child *felt.Felt
+}
+
+// NewTrie creates an empty trie.
+//
+// Parameters:
+// - height: The number of bits of the keys, StorageTrieHeight for the tries of the state
+// - hash: The hash of the nodes, PedersenHash or PoseidonHash
+// Returns:
+// - *Trie: the trie
+func NewTrie(height uint8, hash HashFunc) *Trie {
+	return &Trie{
+		height: height,
+		hash:   hash,
+		leaves: map[felt.Felt]*felt.Felt{},
+	}
+}
+
+// Insert sets the value of a leaf, a zero value removing the leaf.
+//
+// Parameters:
+// - key: The key of the leaf
+// - value: The value of the leaf
+// Returns:
+// - error: ErrKeyOutOfRange if the key doesn't fit in the height of the trie
+func (t *Trie) Insert(key, value *felt.Felt) error {
+	if err := t.checkKey(key); err != nil {
+		return err
+	}
+	if value.IsZero() {
+		delete(t.leaves, *key)
+	} else {
+		t.leaves[*key] = new(felt.Felt).Set(value)
+	}
+	t.sorted, t.root = nil, nil
+	return nil
+}
+
+// Delete removes a leaf, if it exists.
+//
+// Parameters:
+// - key: The key of the leaf
+// Returns:
+// - error: ErrKeyOutOfRange if the key doesn't fit in the height of the trie
+func (t *Trie) Delete(key *felt.Felt) error {
+	return t.Insert(key, &felt.Zero)
+}
+
+// Get returns the value of a leaf.
+//
+// Parameters:
+// - key: The key of the leaf
+// Returns:
+// - *felt.Felt: the value of the leaf, zero if the leaf doesn't exist
+func (t *Trie) Get(key *felt.Felt) *felt.Felt {
+	if value, ok := t.leaves[*key]; ok {
+		return new(felt.Felt).Set(value)
+	}
+	return new(felt.Felt)
+}
+
+// Root returns the hash of the root of the trie.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the root, zero for an empty trie
+func (t *Trie) Root() *felt.Felt {
+	if t.root == nil {
+		leaves := t.sortedLeaves()
+		if len(leaves) == 0 {
+			t.root = new(felt.Felt)
+		} else {
+			t.root = t.subtreeHash(leaves, 0)
+		}
+	}
+	return new(felt.Felt).Set(t.root)
+}
+
+// Prove returns the proof of the value of a leaf, the nodes from the root
+// down to the leaf. For a leaf that doesn't exist, the proof goes down to
+// the edge node whose path diverges from the key, and proves that the leaf is zero.
+//
+// Parameters:
+// - key: The key of the leaf
+// Returns:
+// - []ProofNode: the proof, empty for an empty trie
+// - error: ErrKeyOutOfRange if the key doesn't fit in the height of the trie
+func (t *Trie) Prove(key *felt.Felt) ([]ProofNode, error) {
+	if err := t.checkKey(key); err != nil {
+		return nil, err
+	}
+	bits := key.BigInt(new(big.Int))
+	leaves := t.sortedLeaves()
+	proof := []ProofNode{}
+	depth := uint8(0)
+	for len(leaves) > 0 {
+		length := t.commonLength(leaves, depth)
+		if length > 0 {
+			path := t.pathOf(leaves[0].key, depth, length)
+			proof = append(proof, ProofNode{
+				Path:   new(felt.Felt).SetBytes(path.Bytes()),
+				Length: length,
+				Child:  t.bottomHash(leaves, depth+length),
+			})
+			if t.pathOf(bits, depth, length).Cmp(path) != 0 {
+				break
+			}
+			depth += length
+		}
+		if depth == t.height {
+			break
+		}
+		left, right := t.split(leaves, depth)
+		proof = append(proof, ProofNode{Left: t.subtreeHash(left, depth+1), Right: t.subtreeHash(right, depth+1)})
+		if bits.Bit(int(t.height-1-depth)) == 0 {
+			leaves = left
+		} else {
+			leaves = right
+		}
+		depth++
+	}
+	return proof, nil
+}
+
+// VerifyProof checks a proof of a leaf against a trusted root and returns
+// the value of the leaf it proves, zero if it proves that the leaf doesn't exist.
+//
+// Parameters:
+// - root: The trusted root of the trie
+// - key: The key of the leaf
+// - proof: The nodes from the root down to the leaf, as returned by Trie.Prove
+// - height: The height of the trie
+// - hash: The hash of the trie
+// Returns:
+// - *felt.Felt: the value of the leaf, zero for a non-membership proof
+// - error: an error matching ErrInvalidProof if the proof doesn't match the root or the key
+func VerifyProof(root, key *felt.Felt, proof []ProofNode, height uint8, hash HashFunc) (*felt.Felt, error) {
+	if key.BigInt(new(big.Int)).BitLen() > int(height) {
+		return nil, ErrKeyOutOfRange
+	}
+	if root.IsZero() {
+		if len(proof) != 0 {
+			return nil, fmt.Errorf("%w: nodes given for an empty trie", ErrInvalidProof)
+		}
+		return new(felt.Felt), nil
+	}
+	bits := key.BigInt(new(big.Int))
+	expected := root
+	depth := uint8(0)
+	for i, node := range proof {
+		if depth == height {
+			return nil, fmt.Errorf("%w: node %d is below the leaves", ErrInvalidProof, i)
+		}
+		nodeHash, err := node.Hash(hash)
+		if err != nil {
+			return nil, fmt.Errorf("%w: node %d: %v", ErrInvalidProof, i, err)
+		}
+		if !nodeHash.Equal(expected) {
+			return nil, fmt.Errorf("%w: node %d hashes to %s, expected %s", ErrInvalidProof, i, nodeHash, expected)
+		}
+		if !node.IsEdge() {
+			if bits.Bit(int(height-1-depth)) == 0 {
+				expected = node.Left
+			} else {
+				expected = node.Right
+			}
+			depth++
+			continue
+		}
+		if node.Length == 0 || int(depth)+int(node.Length) > int(height) {
+			return nil, fmt.Errorf("%w: node %d has an edge of length %d at depth %d", ErrInvalidProof, i, node.Length, depth)
+		}
+		keyPath := new(big.Int).Rsh(bits, uint(height-depth-node.Length))
+		keyPath.And(keyPath, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(node.Length)), big.NewInt(1)))
+		if keyPath.Cmp(node.Path.BigInt(new(big.Int))) != 0 {
+			// the only path below the edge leads to other leaves
+			if i != len(proof)-1 {
+				return nil, fmt.Errorf("%w: nodes given below a diverging edge", ErrInvalidProof)
+			}
+			return new(felt.Felt), nil
+		}
+		expected = node.Child
+		depth += node.Length
+	}
+	if depth != height {
+		return nil, fmt.Errorf("%w: the proof stops at depth %d", ErrInvalidProof, depth)
+	}
+	return new(felt.Felt).Set(expected), nil
+}
+
+// IsEdge reports whether the node is an edge node.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true for an edge node, false for a binary node
+func (n ProofNode) IsEdge() bool {
+	return n.Child != nil
+}
+
+// Hash returns the hash of the node.
+//
+// Parameters:
+// - hash: The hash of the trie
+// Returns:
+// - *felt.Felt: the hash of the node
+// - error: an error if the node misses a field
+func (n ProofNode) Hash(hash HashFunc) (*felt.Felt, error) {
+	if n.IsEdge() {
+		if n.Path == nil {
+			return nil, errors.New("edge node without path")
+		}
+		return edgeHash(hash, n.Child, n.Path, n.Length), nil
+	}
+	if n.Left == nil || n.Right == nil {
+		return nil, errors.New("binary node without children")
+	}
+	return hash(n.Left, n.Right), nil
+}
+
+// edgeHash returns the hash of an edge node.
+func edgeHash(hash HashFunc, child, path *felt.Felt, length uint8) *felt.Felt {
+	h := hash(child, path)
+	return h.Add(h, new(felt.Felt).SetUint64(uint64(length)))
+}
+
+// checkKey returns ErrKeyOutOfRange if the key doesn't fit in the height of the trie.
+func (t *Trie) checkKey(key *felt.Felt) error {
+	if key.BigInt(new(big.Int)).BitLen() > int(t.height) {
+		return fmt.Errorf("%w: %s", ErrKeyOutOfRange, key)
+	}
+	return nil
+}
+
+// sortedLeaves returns the leaves sorted by key.
+func (t *Trie) sortedLeaves() []trieLeaf {
+	if t.sorted == nil {
+		t.sorted = make([]trieLeaf, 0, len(t.leaves))
+		for key, value := range t.leaves {
+			key := key
+			t.sorted = append(t.sorted, trieLeaf{key: key.BigInt(new(big.Int)), value: value})
+		}
+		sort.Slice(t.sorted, func(i, j int) bool { return t.sorted[i].key.Cmp(t.sorted[j].key) < 0 })
+	}
+	return t.sorted
+}
+
+// subtreeHash returns the hash of the subtree of the sorted leaves below
+// depth, an edge node if they share the next bits of their keys.
+func (t *Trie) subtreeHash(leaves []trieLeaf, depth uint8) *felt.Felt {
+	length := t.commonLength(leaves, depth)
+	bottom := t.bottomHash(leaves, depth+length)
+	if length == 0 {
+		return bottom
+	}
+	return edgeHash(t.hash, bottom, new(felt.Felt).SetBytes(t.pathOf(leaves[0].key, depth, length).Bytes()), length)
+}
+
+// bottomHash returns the hash of the node at depth below which the sorted
+// leaves branch, or of the leaf at the bottom of the trie.
+func (t *Trie) bottomHash(leaves []trieLeaf, depth uint8) *felt.Felt {
+	if depth == t.height {
+		return leaves[0].value
+	}
+	left, right := t.split(leaves, depth)
+	return t.hash(t.subtreeHash(left, depth+1), t.subtreeHash(right, depth+1))
+}
+
+// commonLength returns the number of bits below depth shared by the keys of
+// the sorted leaves, the height left for a single leaf.
+func (t *Trie) commonLength(leaves []trieLeaf, depth uint8) uint8 {
+	first, last := leaves[0].key, leaves[len(leaves)-1].key
+	length := uint8(0)
+	for depth+length < t.height {
+		bit := int(t.height - 1 - depth - length)
+		if first.Bit(bit) != last.Bit(bit) {
+			break
+		}
+		length++
+	}
+	return length
+}
+
+// split splits the sorted leaves on the bit of their keys at depth.
+func (t *Trie) split(leaves []trieLeaf, depth uint8) (left, right []trieLeaf) {
+	bit := int(t.height - 1 - depth)
+	i := sort.Search(len(leaves), func(i int) bool { return leaves[i].key.Bit(bit) == 1 })
+	return leaves[:i], leaves[i:]
+}
+
+// pathOf returns the length bits of key below depth.
+func (t *Trie) pathOf(key *big.Int, depth, length uint8) *big.Int {
+	path := new(big.Int).Rsh(key, uint(t.height-depth-length))
+	return path.And(path, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(length)), big.NewInt(1)))
+}
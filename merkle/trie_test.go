@@ -0,0 +1,118 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTrieRoot tests the root of a trie against the hashes of its edge and binary nodes.
+func TestTrieRoot(t *testing.T) {
+	type testSetType struct {
+		Hash   HashFunc
+		Leaves map[string]string
+	}
+	testSet := []testSetType{
+		{Hash: PedersenHash, Leaves: map[string]string{"0x1": "0xa"}},
+		{Hash: PoseidonHash, Leaves: map[string]string{"0x1": "0xa", "0x2": "0xb"}},
+		{Hash: PedersenHash, Leaves: map[string]string{"0x1": "0xa", "0x2": "0xb", "0x3": "0xc"}},
+	}
+	for _, test := range testSet {
+		trie := NewTrie(StorageTrieHeight, test.Hash)
+		require.Equal(t, &felt.Zero, trie.Root())
+		for key, value := range test.Leaves {
+			require.NoError(t, trie.Insert(utils.TestHexToFelt(t, key), utils.TestHexToFelt(t, value)))
+		}
+
+		a, b, c := utils.TestHexToFelt(t, "0xa"), utils.TestHexToFelt(t, "0xb"), utils.TestHexToFelt(t, "0xc")
+		var expected *felt.Felt
+		switch len(test.Leaves) {
+		case 1:
+			expected = edgeHash(test.Hash, a, utils.TestHexToFelt(t, "0x1"), 251)
+		case 2:
+			// the leaves branch at the second to last bit
+			binary := test.Hash(edgeHash(test.Hash, a, utils.TestHexToFelt(t, "0x1"), 1), edgeHash(test.Hash, b, &felt.Zero, 1))
+			expected = edgeHash(test.Hash, binary, &felt.Zero, 249)
+		case 3:
+			left := edgeHash(test.Hash, a, utils.TestHexToFelt(t, "0x1"), 1)
+			binary := test.Hash(left, test.Hash(b, c))
+			expected = edgeHash(test.Hash, binary, &felt.Zero, 249)
+		}
+		require.Equal(t, expected, trie.Root())
+	}
+}
+
+// TestTrieInsertDelete tests that the root depends on the leaves only.
+func TestTrieInsertDelete(t *testing.T) {
+	trie := NewTrie(StorageTrieHeight, PedersenHash)
+	keys := utils.TestHexArrToFelt(t, []string{"0x5", "0x3", "0x7ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", "0x100"})
+	for i, key := range keys[:3] {
+		require.NoError(t, trie.Insert(key, new(felt.Felt).SetUint64(uint64(i+1))))
+	}
+	root := trie.Root()
+
+	require.NoError(t, trie.Insert(keys[3], utils.TestHexToFelt(t, "0x4")))
+	require.NotEqual(t, root, trie.Root())
+	require.Equal(t, utils.TestHexToFelt(t, "0x4"), trie.Get(keys[3]))
+	require.NoError(t, trie.Delete(keys[3]))
+	require.Equal(t, root, trie.Root())
+	require.Equal(t, &felt.Zero, trie.Get(keys[3]))
+
+	// inserted in another order, zero values removing the leaves
+	other := NewTrie(StorageTrieHeight, PedersenHash)
+	for i := len(keys) - 2; i >= 0; i-- {
+		require.NoError(t, other.Insert(keys[i], new(felt.Felt).SetUint64(uint64(i+1))))
+	}
+	require.NoError(t, other.Insert(keys[3], &felt.Zero))
+	require.Equal(t, root, other.Root())
+
+	require.ErrorIs(t, trie.Insert(utils.TestHexToFelt(t, "0x800000000000000000000000000000000000000000000000000000000000000"), keys[0]), ErrKeyOutOfRange)
+}
+
+// TestTrieProof tests the membership and non-membership proofs.
+func TestTrieProof(t *testing.T) {
+	trie := NewTrie(StorageTrieHeight, PoseidonHash)
+	_, err := VerifyProof(trie.Root(), utils.TestHexToFelt(t, "0x1"), []ProofNode{}, StorageTrieHeight, PoseidonHash)
+	require.NoError(t, err)
+
+	leaves := map[string]string{"0x1": "0xa", "0x2": "0xb", "0x3": "0xc", "0x1000": "0xd", "0x7abc": "0xe"}
+	for key, value := range leaves {
+		require.NoError(t, trie.Insert(utils.TestHexToFelt(t, key), utils.TestHexToFelt(t, value)))
+	}
+	root := trie.Root()
+
+	for _, key := range []string{"0x1", "0x2", "0x3", "0x1000", "0x7abc", "0x0", "0x4", "0x1001", "0x7abd", "0x400000000000"} {
+		proof, err := trie.Prove(utils.TestHexToFelt(t, key))
+		require.NoError(t, err)
+		value, err := VerifyProof(root, utils.TestHexToFelt(t, key), proof, StorageTrieHeight, PoseidonHash)
+		require.NoError(t, err, key)
+		expected := "0x0"
+		if leaf, ok := leaves[key]; ok {
+			expected = leaf
+		}
+		require.Equal(t, utils.TestHexToFelt(t, expected), value, key)
+	}
+
+	proof, err := trie.Prove(utils.TestHexToFelt(t, "0x2"))
+	require.NoError(t, err)
+
+	// the proof of a leaf doesn't prove a leaf of another subtree
+	_, err = VerifyProof(root, utils.TestHexToFelt(t, "0x7abc"), proof, StorageTrieHeight, PoseidonHash)
+	require.ErrorIs(t, err, ErrInvalidProof)
+	// nor matches another root
+	_, err = VerifyProof(utils.TestHexToFelt(t, "0x1"), utils.TestHexToFelt(t, "0x2"), proof, StorageTrieHeight, PoseidonHash)
+	require.ErrorIs(t, err, ErrInvalidProof)
+	// nor a truncated or tampered proof
+	_, err = VerifyProof(root, utils.TestHexToFelt(t, "0x2"), proof[:len(proof)-1], StorageTrieHeight, PoseidonHash)
+	require.ErrorIs(t, err, ErrInvalidProof)
+	last := &proof[len(proof)-1]
+	if last.IsEdge() {
+		last.Child = utils.TestHexToFelt(t, "0xf")
+	} else {
+		last.Left = utils.TestHexToFelt(t, "0xf")
+	}
+	_, err = VerifyProof(root, utils.TestHexToFelt(t, "0x2"), proof, StorageTrieHeight, PoseidonHash)
+	require.ErrorIs(t, err, ErrInvalidProof)
+}
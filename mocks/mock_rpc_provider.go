@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	felt "github.com/NethermindEth/juno/core/felt"
+	contracts "github.com/NethermindEth/starknet.go/contracts"
 	rpc "github.com/NethermindEth/starknet.go/rpc"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -251,6 +252,21 @@ func (mr *MockRpcProviderMockRecorder) ClassHashAt(ctx, blockID, contractAddress
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClassHashAt", reflect.TypeOf((*MockRpcProvider)(nil).ClassHashAt), ctx, blockID, contractAddress)
 }
 
+// CompiledCasm mocks base method.
+func (m *MockRpcProvider) CompiledCasm(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompiledCasm", ctx, classHash)
+	ret0, _ := ret[0].(*contracts.CasmClass)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompiledCasm indicates an expected call of CompiledCasm.
+func (mr *MockRpcProviderMockRecorder) CompiledCasm(ctx, classHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompiledCasm", reflect.TypeOf((*MockRpcProvider)(nil).CompiledCasm), ctx, classHash)
+}
+
 // EstimateFee mocks base method.
 func (m *MockRpcProvider) EstimateFee(ctx context.Context, requests []rpc.BroadcastTxn, simulationFlags []rpc.SimulationFlag, blockID rpc.BlockID) ([]rpc.FeeEstimate, error) {
 	m.ctrl.T.Helper()
@@ -311,6 +327,21 @@ func (mr *MockRpcProviderMockRecorder) GetTransactionStatus(ctx, transactionHash
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionStatus", reflect.TypeOf((*MockRpcProvider)(nil).GetTransactionStatus), ctx, transactionHash)
 }
 
+// MessagesStatus mocks base method.
+func (m *MockRpcProvider) MessagesStatus(ctx context.Context, transactionHash rpc.NumAsHex) ([]rpc.MessageStatusResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MessagesStatus", ctx, transactionHash)
+	ret0, _ := ret[0].([]rpc.MessageStatusResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MessagesStatus indicates an expected call of MessagesStatus.
+func (mr *MockRpcProviderMockRecorder) MessagesStatus(ctx, transactionHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MessagesStatus", reflect.TypeOf((*MockRpcProvider)(nil).MessagesStatus), ctx, transactionHash)
+}
+
 // Nonce mocks base method.
 func (m *MockRpcProvider) Nonce(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
 	m.ctrl.T.Helper()
@@ -386,6 +417,21 @@ func (mr *MockRpcProviderMockRecorder) StorageAt(ctx, contractAddress, key, bloc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageAt", reflect.TypeOf((*MockRpcProvider)(nil).StorageAt), ctx, contractAddress, key, blockID)
 }
 
+// StorageProof mocks base method.
+func (m *MockRpcProvider) StorageProof(ctx context.Context, input rpc.StorageProofInput) (*rpc.StorageProof, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorageProof", ctx, input)
+	ret0, _ := ret[0].(*rpc.StorageProof)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StorageProof indicates an expected call of StorageProof.
+func (mr *MockRpcProviderMockRecorder) StorageProof(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageProof", reflect.TypeOf((*MockRpcProvider)(nil).StorageProof), ctx, input)
+}
+
 // Syncing mocks base method.
 func (m *MockRpcProvider) Syncing(ctx context.Context) (*rpc.SyncStatus, error) {
 	m.ctrl.T.Helper()
@@ -475,3 +521,48 @@ func (mr *MockRpcProviderMockRecorder) TransactionReceipt(ctx, transactionHash a
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionReceipt", reflect.TypeOf((*MockRpcProvider)(nil).TransactionReceipt), ctx, transactionHash)
 }
+
+// TypedBlockWithReceipts mocks base method.
+func (m *MockRpcProvider) TypedBlockWithReceipts(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockWithReceiptsResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TypedBlockWithReceipts", ctx, blockID)
+	ret0, _ := ret[0].(*rpc.BlockWithReceiptsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TypedBlockWithReceipts indicates an expected call of TypedBlockWithReceipts.
+func (mr *MockRpcProviderMockRecorder) TypedBlockWithReceipts(ctx, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TypedBlockWithReceipts", reflect.TypeOf((*MockRpcProvider)(nil).TypedBlockWithReceipts), ctx, blockID)
+}
+
+// TypedBlockWithTxHashes mocks base method.
+func (m *MockRpcProvider) TypedBlockWithTxHashes(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockTxHashesResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TypedBlockWithTxHashes", ctx, blockID)
+	ret0, _ := ret[0].(*rpc.BlockTxHashesResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TypedBlockWithTxHashes indicates an expected call of TypedBlockWithTxHashes.
+func (mr *MockRpcProviderMockRecorder) TypedBlockWithTxHashes(ctx, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TypedBlockWithTxHashes", reflect.TypeOf((*MockRpcProvider)(nil).TypedBlockWithTxHashes), ctx, blockID)
+}
+
+// TypedBlockWithTxs mocks base method.
+func (m *MockRpcProvider) TypedBlockWithTxs(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TypedBlockWithTxs", ctx, blockID)
+	ret0, _ := ret[0].(*rpc.BlockResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TypedBlockWithTxs indicates an expected call of TypedBlockWithTxs.
+func (mr *MockRpcProviderMockRecorder) TypedBlockWithTxs(ctx, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TypedBlockWithTxs", reflect.TypeOf((*MockRpcProvider)(nil).TypedBlockWithTxs), ctx, blockID)
+}
@@ -0,0 +1,433 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+var errBatchNotSent = errors.New("batch has not been sent")
+
+// batchCallCloser is a callCloser that can also send JSON-RPC batch requests.
+type batchCallCloser interface {
+	callCloser
+	BatchCallContext(ctx context.Context, b []ethrpc.BatchElem) error
+}
+
+// Batch queues calls to be sent to the node as a single JSON-RPC batch
+// request. Each queued call writes its result to the destination it was
+// given once the batch has been sent.
+type Batch struct {
+	provider *Provider
+	calls    []*BatchCall
+}
+
+// BatchCall is a call queued in a Batch.
+type BatchCall struct {
+	method string
+	args   []interface{}
+	raw    json.RawMessage
+	decode func(raw json.RawMessage) error
+	mapErr func(err error) error
+	err    error
+	sent   bool
+}
+
+// Err returns the error of the call once the batch has been sent. The error
+// is mapped to the Starknet RPC errors the same way as the Provider method.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: the error of the call, if any
+func (call *BatchCall) Err() error {
+	if !call.sent {
+		return errBatchNotSent
+	}
+	return call.err
+}
+
+// NewBatch creates an empty Batch sent through the provider.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *Batch: a new Batch
+func (provider *Provider) NewBatch() *Batch {
+	return &Batch{provider: provider}
+}
+
+// Len returns the number of calls waiting to be sent.
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// Send sends all the queued calls as one batch request and empties the batch
+// so that it can be reused. The returned error only reports transport
+// failures; the error of each call is available through BatchCall.Err.
+//
+// Parameters:
+// - ctx: The context.Context of the request
+// Returns:
+// - error: An error if the batch could not be sent
+func (b *Batch) Send(ctx context.Context) error {
+	calls := b.calls
+	b.calls = nil
+	if len(calls) == 0 {
+		return nil
+	}
+
+	elems := make([]ethrpc.BatchElem, len(calls))
+	for i, call := range calls {
+		elems[i] = ethrpc.BatchElem{Method: call.method, Args: call.args, Result: &call.raw}
+	}
+
+	if bc, ok := b.provider.c.(batchCallCloser); ok {
+		if err := bc.BatchCallContext(ctx, elems); err != nil {
+			rpcErr := Err(InternalError, err)
+			for _, call := range calls {
+				call.sent = true
+				call.err = rpcErr
+			}
+			return rpcErr
+		}
+	} else {
+		// the client can't batch, fall back to one request per call
+		for i := range elems {
+			elems[i].Error = b.provider.c.CallContext(ctx, elems[i].Result, elems[i].Method, elems[i].Args...)
+		}
+	}
+
+	for i, call := range calls {
+		call.sent = true
+		switch {
+		case elems[i].Error != nil:
+			call.err = call.mapErr(elems[i].Error)
+		case len(call.raw) == 0:
+			call.err = call.mapErr(errNotFound)
+		default:
+			call.err = call.decode(call.raw)
+		}
+	}
+	return nil
+}
+
+// queue adds a call to the batch.
+func (b *Batch) queue(method string, decode func(json.RawMessage) error, mapErr func(error) error, args ...interface{}) *BatchCall {
+	if args == nil {
+		// force an empty `params[]` in the jsonrpc request
+		args = []interface{}{}
+	}
+	call := &BatchCall{method: method, args: args, decode: decode, mapErr: mapErr}
+	b.calls = append(b.calls, call)
+	return call
+}
+
+// unmarshalTo returns a decoder writing the raw result to dest.
+func unmarshalTo(dest interface{}) func(json.RawMessage) error {
+	return func(raw json.RawMessage) error {
+		if err := json.Unmarshal(raw, dest); err != nil {
+			return Err(InternalError, err)
+		}
+		return nil
+	}
+}
+
+// unwrapTo returns an error mapper that goes through tryUnwrapToRPCErr.
+func unwrapTo(rpcErrors ...*RPCError) func(error) error {
+	return func(err error) error {
+		return tryUnwrapToRPCErr(err, rpcErrors...)
+	}
+}
+
+// internalErr is the error mapper of the methods that report every failure as an InternalError.
+func internalErr(err error) error {
+	return Err(InternalError, err)
+}
+
+// AddInvokeTransaction queues a starknet_addInvokeTransaction call.
+func (b *Batch) AddInvokeTransaction(invokeTxn BroadcastInvokeTxnType, result *AddInvokeTransactionResponse) *BatchCall {
+	return b.queue("starknet_addInvokeTransaction", unmarshalTo(result), unwrapTo(
+		ErrInsufficientAccountBalance,
+		ErrInsufficientMaxFee,
+		ErrInvalidTransactionNonce,
+		ErrValidationFailure,
+		ErrNonAccount,
+		ErrDuplicateTx,
+		ErrUnsupportedTxVersion,
+		ErrUnexpectedError,
+	), invokeTxn)
+}
+
+// AddDeclareTransaction queues a starknet_addDeclareTransaction call.
+func (b *Batch) AddDeclareTransaction(declareTransaction BroadcastDeclareTxnType, result *AddDeclareTransactionResponse) *BatchCall {
+	return b.queue("starknet_addDeclareTransaction", unmarshalTo(result), unwrapTo(
+		ErrClassAlreadyDeclared,
+		ErrCompilationFailed,
+		ErrCompiledClassHashMismatch,
+		ErrInsufficientAccountBalance,
+		ErrInsufficientMaxFee,
+		ErrInvalidTransactionNonce,
+		ErrValidationFailure,
+		ErrNonAccount,
+		ErrDuplicateTx,
+		ErrContractClassSizeTooLarge,
+		ErrUnsupportedTxVersion,
+		ErrUnsupportedContractClassVersion,
+	), declareTransaction)
+}
+
+// AddDeployAccountTransaction queues a starknet_addDeployAccountTransaction call.
+func (b *Batch) AddDeployAccountTransaction(deployAccountTransaction BroadcastAddDeployTxnType, result *AddDeployAccountTransactionResponse) *BatchCall {
+	return b.queue("starknet_addDeployAccountTransaction", unmarshalTo(result), unwrapTo(
+		ErrInsufficientAccountBalance,
+		ErrInsufficientMaxFee,
+		ErrInvalidTransactionNonce,
+		ErrValidationFailure,
+		ErrNonAccount,
+		ErrClassHashNotFound,
+		ErrDuplicateTx,
+		ErrUnsupportedTxVersion,
+	), deployAccountTransaction)
+}
+
+// BlockHashAndNumber queues a starknet_blockHashAndNumber call.
+func (b *Batch) BlockHashAndNumber(result *BlockHashAndNumberOutput) *BatchCall {
+	return b.queue("starknet_blockHashAndNumber", unmarshalTo(result), unwrapTo(ErrNoBlocks))
+}
+
+// BlockNumber queues a starknet_blockNumber call.
+func (b *Batch) BlockNumber(result *uint64) *BatchCall {
+	return b.queue("starknet_blockNumber", unmarshalTo(result), func(err error) error {
+		if errors.Is(err, errNotFound) {
+			return ErrNoBlocks
+		}
+		return Err(InternalError, err)
+	})
+}
+
+// BlockTransactionCount queues a starknet_getBlockTransactionCount call.
+func (b *Batch) BlockTransactionCount(blockID BlockID, result *uint64) *BatchCall {
+	return b.queue("starknet_getBlockTransactionCount", unmarshalTo(result), func(err error) error {
+		if errors.Is(err, errNotFound) {
+			return ErrBlockNotFound
+		}
+		return Err(InternalError, err)
+	}, blockID)
+}
+
+// BlockWithTxHashes queues a starknet_getBlockWithTxHashes call. The result is
+// a *BlockTxHashes or a *PendingBlockTxHashes.
+func (b *Batch) BlockWithTxHashes(blockID BlockID, result *interface{}) *BatchCall {
+	return b.queue("starknet_getBlockWithTxHashes", func(raw json.RawMessage) error {
+		var block BlockTxHashes
+		if err := unmarshalTo(&block)(raw); err != nil {
+			return err
+		}
+		*result = adaptBlockTxHashes(&block)
+		return nil
+	}, unwrapTo(ErrBlockNotFound), blockID)
+}
+
+// BlockWithTxs queues a starknet_getBlockWithTxs call. The result is a *Block
+// or a *PendingBlock.
+func (b *Batch) BlockWithTxs(blockID BlockID, result *interface{}) *BatchCall {
+	return b.queue("starknet_getBlockWithTxs", func(raw json.RawMessage) error {
+		var block Block
+		if err := unmarshalTo(&block)(raw); err != nil {
+			return err
+		}
+		*result = adaptBlock(&block)
+		return nil
+	}, unwrapTo(ErrBlockNotFound), blockID)
+}
+
+// BlockWithReceipts queues a starknet_getBlockWithReceipts call. The result is
+// a *BlockWithReceipts or a *PendingBlockWithReceipts.
+func (b *Batch) BlockWithReceipts(blockID BlockID, result *interface{}) *BatchCall {
+	return b.queue("starknet_getBlockWithReceipts", func(raw json.RawMessage) error {
+		block, err := unmarshalBlockWithReceipts(raw)
+		if err != nil {
+			return err
+		}
+		*result = block
+		return nil
+	}, unwrapTo(ErrBlockNotFound), blockID)
+}
+
+// Call queues a starknet_call call.
+func (b *Batch) Call(request FunctionCall, blockID BlockID, result *[]*felt.Felt) *BatchCall {
+	if len(request.Calldata) == 0 {
+		request.Calldata = make([]*felt.Felt, 0)
+	}
+	return b.queue("starknet_call", unmarshalTo(result), unwrapTo(ErrContractNotFound, ErrBlockNotFound), request, blockID)
+}
+
+// ChainID queues a starknet_chainId call. The result is the decoded chain id,
+// e.g. SN_MAIN, and is cached by the provider.
+func (b *Batch) ChainID(result *string) *BatchCall {
+	return b.queue("starknet_chainId", func(raw json.RawMessage) error {
+		var chainID string
+		if err := unmarshalTo(&chainID)(raw); err != nil {
+			return err
+		}
+		b.provider.chainID = utils.HexToShortStr(chainID)
+		*result = b.provider.chainID
+		return nil
+	}, internalErr)
+}
+
+// Class queues a starknet_getClass call.
+func (b *Batch) Class(blockID BlockID, classHash *felt.Felt, result *ClassOutput) *BatchCall {
+	return b.queue("starknet_getClass", classOutputTo(result), unwrapTo(ErrClassHashNotFound, ErrBlockNotFound), blockID, classHash)
+}
+
+// ClassAt queues a starknet_getClassAt call.
+func (b *Batch) ClassAt(blockID BlockID, contractAddress *felt.Felt, result *ClassOutput) *BatchCall {
+	return b.queue("starknet_getClassAt", classOutputTo(result), unwrapTo(ErrContractNotFound, ErrBlockNotFound), blockID, contractAddress)
+}
+
+// classOutputTo returns a decoder writing the typecast class to result.
+func classOutputTo(result *ClassOutput) func(json.RawMessage) error {
+	return func(raw json.RawMessage) error {
+		var rawClass map[string]any
+		if err := unmarshalTo(&rawClass)(raw); err != nil {
+			return err
+		}
+		class, err := typecastClassOutput(rawClass)
+		if err != nil {
+			return err
+		}
+		*result = class
+		return nil
+	}
+}
+
+// ClassHashAt queues a starknet_getClassHashAt call.
+func (b *Batch) ClassHashAt(blockID BlockID, contractAddress *felt.Felt, result **felt.Felt) *BatchCall {
+	return b.queue("starknet_getClassHashAt", unmarshalTo(result), unwrapTo(ErrContractNotFound, ErrBlockNotFound), blockID, contractAddress)
+}
+
+// EstimateFee queues a starknet_estimateFee call.
+func (b *Batch) EstimateFee(requests []BroadcastTxn, simulationFlags []SimulationFlag, blockID BlockID, result *[]FeeEstimate) *BatchCall {
+	return b.queue("starknet_estimateFee", unmarshalTo(result), unwrapTo(ErrTxnExec, ErrBlockNotFound), requests, simulationFlags, blockID)
+}
+
+// EstimateMessageFee queues a starknet_estimateMessageFee call.
+func (b *Batch) EstimateMessageFee(msg MsgFromL1, blockID BlockID, result *FeeEstimate) *BatchCall {
+	return b.queue("starknet_estimateMessageFee", unmarshalTo(result), unwrapTo(ErrContractNotFound, ErrBlockNotFound), msg, blockID)
+}
+
+// Events queues a starknet_getEvents call.
+func (b *Batch) Events(input EventsInput, result *EventChunk) *BatchCall {
+	return b.queue("starknet_getEvents", unmarshalTo(result), unwrapTo(ErrPageSizeTooBig, ErrInvalidContinuationToken, ErrBlockNotFound, ErrTooManyKeysInFilter), input)
+}
+
+// GetTransactionStatus queues a starknet_getTransactionStatus call.
+func (b *Batch) GetTransactionStatus(transactionHash *felt.Felt, result *TxnStatusResp) *BatchCall {
+	return b.queue("starknet_getTransactionStatus", unmarshalTo(result), unwrapTo(ErrHashNotFound), transactionHash)
+}
+
+// Nonce queues a starknet_getNonce call.
+func (b *Batch) Nonce(blockID BlockID, contractAddress *felt.Felt, result **felt.Felt) *BatchCall {
+	return b.queue("starknet_getNonce", unmarshalTo(result), unwrapTo(ErrContractNotFound, ErrBlockNotFound), blockID, contractAddress)
+}
+
+// SimulateTransactions queues a starknet_simulateTransactions call.
+func (b *Batch) SimulateTransactions(blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag, result *[]SimulatedTransaction) *BatchCall {
+	return b.queue("starknet_simulateTransactions", unmarshalTo(result), unwrapTo(ErrTxnExec, ErrBlockNotFound), blockID, txns, simulationFlags)
+}
+
+// StateUpdate queues a starknet_getStateUpdate call.
+func (b *Batch) StateUpdate(blockID BlockID, result *StateUpdateOutput) *BatchCall {
+	return b.queue("starknet_getStateUpdate", unmarshalTo(result), unwrapTo(ErrBlockNotFound), blockID)
+}
+
+// StorageAt queues a starknet_getStorageAt call. As with Provider.StorageAt,
+// key is the name of the storage variable.
+func (b *Batch) StorageAt(contractAddress *felt.Felt, key string, blockID BlockID, result *string) *BatchCall {
+	hashKey := fmt.Sprintf("0x%x", utils.GetSelectorFromName(key))
+	return b.queue("starknet_getStorageAt", unmarshalTo(result), unwrapTo(ErrContractNotFound, ErrBlockNotFound), contractAddress, hashKey, blockID)
+}
+
+// SpecVersion queues a starknet_specVersion call.
+func (b *Batch) SpecVersion(result *string) *BatchCall {
+	return b.queue("starknet_specVersion", unmarshalTo(result), internalErr)
+}
+
+// Syncing queues a starknet_syncing call.
+func (b *Batch) Syncing(result *SyncStatus) *BatchCall {
+	return b.queue("starknet_syncing", func(raw json.RawMessage) error {
+		var sync interface{}
+		if err := unmarshalTo(&sync)(raw); err != nil {
+			return err
+		}
+		status, err := adaptSyncStatus(sync)
+		if err != nil {
+			return err
+		}
+		*result = *status
+		return nil
+	}, internalErr)
+}
+
+// TraceBlockTransactions queues a starknet_traceBlockTransactions call.
+func (b *Batch) TraceBlockTransactions(blockID BlockID, result *[]Trace) *BatchCall {
+	return b.queue("starknet_traceBlockTransactions", unmarshalTo(result), unwrapTo(ErrBlockNotFound), blockID)
+}
+
+// TransactionByBlockIdAndIndex queues a starknet_getTransactionByBlockIdAndIndex call.
+func (b *Batch) TransactionByBlockIdAndIndex(blockID BlockID, index uint64, result *Transaction) *BatchCall {
+	return b.queue("starknet_getTransactionByBlockIdAndIndex", transactionTo(result), unwrapTo(ErrInvalidTxnIndex, ErrBlockNotFound), blockID, index)
+}
+
+// TransactionByHash queues a starknet_getTransactionByHash call.
+func (b *Batch) TransactionByHash(hash *felt.Felt, result *Transaction) *BatchCall {
+	return b.queue("starknet_getTransactionByHash", transactionTo(result), unwrapTo(ErrHashNotFound), hash)
+}
+
+// transactionTo returns a decoder writing the adapted transaction to result.
+func transactionTo(result *Transaction) func(json.RawMessage) error {
+	return func(raw json.RawMessage) error {
+		var tx TXN
+		if err := unmarshalTo(&tx)(raw); err != nil {
+			return err
+		}
+		txn, err := adaptTransaction(tx)
+		if err != nil {
+			return err
+		}
+		*result = txn
+		return nil
+	}
+}
+
+// TransactionReceipt queues a starknet_getTransactionReceipt call.
+func (b *Batch) TransactionReceipt(transactionHash *felt.Felt, result *TransactionReceiptWithBlockInfo) *BatchCall {
+	return b.queue("starknet_getTransactionReceipt", unmarshalTo(result), unwrapTo(ErrHashNotFound), transactionHash)
+}
+
+// TraceTransaction queues a starknet_traceTransaction call.
+func (b *Batch) TraceTransaction(transactionHash *felt.Felt, result *TxnTrace) *BatchCall {
+	return b.queue("starknet_traceTransaction", func(raw json.RawMessage) error {
+		var rawTxnTrace map[string]any
+		if err := unmarshalTo(&rawTxnTrace)(raw); err != nil {
+			return err
+		}
+		trace, err := typecastTxnTrace(rawTxnTrace)
+		if err != nil {
+			return err
+		}
+		*result = trace
+		return nil
+	}, unwrapTo(ErrHashNotFound, ErrNoTraceAvailable), transactionHash)
+}
@@ -0,0 +1,144 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// newBatchTestServer starts a JSON-RPC server answering batch requests with
+// the result or error registered for each method, and counts the HTTP requests.
+//
+// Parameters:
+// - t: The testing.T object for testing purposes
+// - answers: The JSON result or error object of each method
+// Returns:
+// - *httptest.Server: the running server
+// - *int: the number of HTTP requests received
+func newBatchTestServer(t *testing.T, answers map[string]string) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var batch []struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		resp := []map[string]interface{}{}
+		for _, req := range batch {
+			msg := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+			answer := json.RawMessage(answers[req.Method])
+			var probe struct {
+				Code *int `json:"code"`
+			}
+			if json.Unmarshal(answer, &probe) == nil && probe.Code != nil {
+				msg["error"] = answer
+			} else {
+				msg["result"] = answer
+			}
+			resp = append(resp, msg)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// TestBatch tests that queued calls are sent in a single request and that each
+// result and error reaches its own destination.
+func TestBatch(t *testing.T) {
+	server, requests := newBatchTestServer(t, map[string]string{
+		"starknet_blockNumber":    `123`,
+		"starknet_getNonce":       `"0x5"`,
+		"starknet_getStorageAt":   `"0x64"`,
+		"starknet_chainId":        `"0x534e5f5345504f4c4941"`,
+		"starknet_call":           `{"code":20,"message":"Contract not found"}`,
+		"starknet_getClassHashAt": `{"code":-32603,"message":"boom"}`,
+		"starknet_getBlockWithTxHashes": `{"parent_hash":"0x1","timestamp":2,"sequencer_address":"0x3",` +
+			`"l1_gas_price":{"price_in_wei":"0x1"},"l1_data_gas_price":{"price_in_wei":"0x1"},"l1_da_mode":"BLOB","starknet_version":"0.13.1","transactions":[]}`,
+	})
+	provider, err := NewProvider(server.URL)
+	require.NoError(t, err)
+
+	address := utils.TestHexToFelt(t, "0x1")
+	latest := WithBlockTag("latest")
+
+	var (
+		blockNumber uint64
+		nonce       *felt.Felt
+		storage     string
+		chainID     string
+		callResult  []*felt.Felt
+		classHash   *felt.Felt
+		block       interface{}
+	)
+	batch := provider.NewBatch()
+	blockNumberCall := batch.BlockNumber(&blockNumber)
+	nonceCall := batch.Nonce(latest, address, &nonce)
+	storageCall := batch.StorageAt(address, "balance", latest, &storage)
+	chainIDCall := batch.ChainID(&chainID)
+	callCall := batch.Call(FunctionCall{ContractAddress: address, EntryPointSelector: address}, latest, &callResult)
+	classHashCall := batch.ClassHashAt(latest, address, &classHash)
+	blockCall := batch.BlockWithTxHashes(WithBlockTag("pending"), &block)
+	require.Equal(t, 7, batch.Len())
+	require.ErrorIs(t, nonceCall.Err(), errBatchNotSent)
+
+	require.NoError(t, batch.Send(context.Background()))
+	require.Equal(t, 1, *requests)
+	require.Equal(t, 0, batch.Len())
+
+	require.NoError(t, blockNumberCall.Err())
+	require.Equal(t, uint64(123), blockNumber)
+	require.NoError(t, nonceCall.Err())
+	require.Equal(t, "0x5", nonce.String())
+	require.NoError(t, storageCall.Err())
+	require.Equal(t, "0x64", storage)
+	require.NoError(t, chainIDCall.Err())
+	require.Equal(t, "SN_SEPOLIA", chainID)
+	require.NoError(t, blockCall.Err())
+	require.IsType(t, &PendingBlockTxHashes{}, block)
+
+	require.Equal(t, ErrContractNotFound, callCall.Err())
+	require.Equal(t, InternalError, classHashCall.Err().(*RPCError).Code)
+}
+
+// rawAnswers is a callCloser without batch support that answers each method with a fixed JSON result.
+type rawAnswers map[string]string
+
+// CallContext unmarshals the registered answer of method into result.
+func (r rawAnswers) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	answer, ok := r[method]
+	if !ok {
+		return errNotFound
+	}
+	return json.Unmarshal([]byte(answer), result)
+}
+
+// Close does nothing.
+func (r rawAnswers) Close() {}
+
+// TestBatchWithoutBatchSupport tests the fallback to one request per call
+// when the client does not support batches.
+func TestBatchWithoutBatchSupport(t *testing.T) {
+	provider := &Provider{c: rawAnswers{"starknet_chainId": `"0x534e5f4d41494e"`}}
+
+	var chainID string
+	var count uint64
+	batch := provider.NewBatch()
+	chainIDCall := batch.ChainID(&chainID)
+	countCall := batch.BlockTransactionCount(WithBlockNumber(1), &count)
+	require.NoError(t, batch.Send(context.Background()))
+
+	require.NoError(t, chainIDCall.Err())
+	require.Equal(t, "SN_MAIN", chainID)
+	require.Equal(t, ErrBlockNotFound, countCall.Err())
+	require.NoError(t, provider.NewBatch().Send(context.Background()))
+}
@@ -92,18 +92,49 @@ func (provider *Provider) BlockWithTxHashes(ctx context.Context, blockID BlockID
 		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
 	}
 
+	return adaptBlockTxHashes(&result), nil
+}
+
+// adaptBlockTxHashes returns the PendingBlockTxHashes form of the block if it has no hash.
+//
+// Parameters:
+// - result: The block as returned by starknet_getBlockWithTxHashes
+// Returns:
+// - interface{}: a *BlockTxHashes or a *PendingBlockTxHashes
+func adaptBlockTxHashes(result *BlockTxHashes) interface{} {
+	return toBlockTxHashesResult(result).Value()
+}
+
+// toBlockTxHashesResult wraps the block in a BlockTxHashesResult, in its pending form if it has no hash.
+//
+// Parameters:
+// - result: The block as returned by starknet_getBlockWithTxHashes
+// Returns:
+// - *BlockTxHashesResult: the typed block
+func toBlockTxHashesResult(result *BlockTxHashes) *BlockTxHashesResult {
 	// if header.Hash == nil it's a pending block
 	if result.BlockHeader.BlockHash == nil {
-		return &PendingBlockTxHashes{
-			PendingBlockHeader{
-				ParentHash:       result.ParentHash,
-				Timestamp:        result.Timestamp,
-				SequencerAddress: result.SequencerAddress},
-			result.Transactions,
-		}, nil
+		pending := &PendingBlockTxHashes{pendingHeaderOf(result.BlockHeader), result.Transactions}
+		return &BlockTxHashesResult{AnyBlockHeader: AnyBlockHeader{pending: &pending.PendingBlockHeader}, PendingBlock: pending}
 	}
+	return &BlockTxHashesResult{AnyBlockHeader: AnyBlockHeader{header: &result.BlockHeader}, Block: result}
+}
 
-	return &result, nil
+// TypedBlockWithTxHashes retrieves a block with its transaction hashes, like
+// BlockWithTxHashes, without the need for a type switch on the result.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// - blockID: The ID of the block to retrieve
+// Returns:
+// - *BlockTxHashesResult: The retrieved block
+// - error: An error, if any
+func (provider *Provider) TypedBlockWithTxHashes(ctx context.Context, blockID BlockID) (*BlockTxHashesResult, error) {
+	var result BlockTxHashes
+	if err := do(ctx, provider.c, "starknet_getBlockWithTxHashes", &result, blockID); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
+	}
+	return toBlockTxHashesResult(&result), nil
 }
 
 // StateUpdate is a function that performs a state update operation
@@ -155,17 +186,49 @@ func (provider *Provider) BlockWithTxs(ctx context.Context, blockID BlockID) (in
 	if err := do(ctx, provider.c, "starknet_getBlockWithTxs", &result, blockID); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
 	}
+	return adaptBlock(&result), nil
+}
+
+// adaptBlock returns the PendingBlock form of the block if it has no hash.
+//
+// Parameters:
+// - result: The block as returned by starknet_getBlockWithTxs
+// Returns:
+// - interface{}: a *Block or a *PendingBlock
+func adaptBlock(result *Block) interface{} {
+	return toBlockResult(result).Value()
+}
+
+// toBlockResult wraps the block in a BlockResult, in its pending form if it has no hash.
+//
+// Parameters:
+// - result: The block as returned by starknet_getBlockWithTxs
+// Returns:
+// - *BlockResult: the typed block
+func toBlockResult(result *Block) *BlockResult {
 	// if header.Hash == nil it's a pending block
 	if result.BlockHeader.BlockHash == nil {
-		return &PendingBlock{
-			PendingBlockHeader{
-				ParentHash:       result.ParentHash,
-				Timestamp:        result.Timestamp,
-				SequencerAddress: result.SequencerAddress},
-			result.Transactions,
-		}, nil
+		pending := &PendingBlock{pendingHeaderOf(result.BlockHeader), result.Transactions}
+		return &BlockResult{AnyBlockHeader: AnyBlockHeader{pending: &pending.PendingBlockHeader}, PendingBlock: pending}
 	}
-	return &result, nil
+	return &BlockResult{AnyBlockHeader: AnyBlockHeader{header: &result.BlockHeader}, Block: result}
+}
+
+// TypedBlockWithTxs retrieves a block with its transactions, like
+// BlockWithTxs, without the need for a type switch on the result.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// - blockID: The ID of the block to retrieve
+// Returns:
+// - *BlockResult: The retrieved block
+// - error: An error, if any
+func (provider *Provider) TypedBlockWithTxs(ctx context.Context, blockID BlockID) (*BlockResult, error) {
+	var result Block
+	if err := do(ctx, provider.c, "starknet_getBlockWithTxs", &result, blockID); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
+	}
+	return toBlockResult(&result), nil
 }
 
 // Get block information with full transactions and receipts given the block id
@@ -174,25 +237,67 @@ func (provider *Provider) BlockWithReceipts(ctx context.Context, blockID BlockID
 	if err := do(ctx, provider.c, "starknet_getBlockWithReceipts", &result, blockID); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
 	}
+	return unmarshalBlockWithReceipts(result)
+}
+
+// unmarshalBlockWithReceipts decodes the result of starknet_getBlockWithReceipts.
+//
+// Parameters:
+// - result: The raw JSON result
+// Returns:
+// - interface{}: a *BlockWithReceipts or a *PendingBlockWithReceipts
+// - error: An error, if any
+func unmarshalBlockWithReceipts(result json.RawMessage) (interface{}, error) {
+	block, err := toBlockWithReceiptsResult(result)
+	if err != nil {
+		return nil, err
+	}
+	return block.Value(), nil
+}
 
-	var m map[string]interface{}
-	if err := json.Unmarshal(result, &m); err != nil {
+// toBlockWithReceiptsResult decodes the result of starknet_getBlockWithReceipts into a BlockWithReceiptsResult.
+//
+// Parameters:
+// - result: The raw JSON result
+// Returns:
+// - *BlockWithReceiptsResult: the typed block
+// - error: An error, if any
+func toBlockWithReceiptsResult(result json.RawMessage) (*BlockWithReceiptsResult, error) {
+	var probe struct {
+		Status json.RawMessage `json:"status"`
+	}
+	if err := json.Unmarshal(result, &probe); err != nil {
 		return nil, Err(InternalError, err.Error())
 	}
 
 	// PendingBlockWithReceipts doesn't contain a "status" field
-	if _, ok := m["status"]; ok {
+	if probe.Status != nil {
 		var block BlockWithReceipts
 		if err := json.Unmarshal(result, &block); err != nil {
 			return nil, Err(InternalError, err.Error())
 		}
-		return &block, nil
-	} else {
-		var pendingBlock PendingBlockWithReceipts
-		if err := json.Unmarshal(result, &pendingBlock); err != nil {
-			return nil, Err(InternalError, err.Error())
-		}
-		return &pendingBlock, nil
+		return &BlockWithReceiptsResult{AnyBlockHeader: AnyBlockHeader{header: &block.BlockHeader}, Block: &block}, nil
 	}
+	var pendingBlock PendingBlockWithReceipts
+	if err := json.Unmarshal(result, &pendingBlock); err != nil {
+		return nil, Err(InternalError, err.Error())
+	}
+	return &BlockWithReceiptsResult{AnyBlockHeader: AnyBlockHeader{pending: &pendingBlock.PendingBlockHeader}, PendingBlock: &pendingBlock}, nil
+}
 
+// TypedBlockWithReceipts retrieves a block with its transactions and their
+// receipts, like BlockWithReceipts, without the need for a type switch on the result.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// - blockID: The ID of the block to retrieve
+// Returns:
+// - *BlockWithReceiptsResult: The retrieved block
+// - error: An error, if any
+func (provider *Provider) TypedBlockWithReceipts(ctx context.Context, blockID BlockID) (*BlockWithReceiptsResult, error) {
+	var result json.RawMessage
+	if err := do(ctx, provider.c, "starknet_getBlockWithReceipts", &result, blockID); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
+	}
+	return toBlockWithReceiptsResult(result)
 }
@@ -1091,3 +1091,58 @@ func TestStateUpdate(t *testing.T) {
 		)
 	}
 }
+
+// TestTypedBlockMethods tests that the typed block methods report pending
+// blocks and expose the shared header fields.
+func TestTypedBlockMethods(t *testing.T) {
+	header := `"parent_hash":"0x1","timestamp":2,"sequencer_address":"0x3","l1_gas_price":{"price_in_wei":"0x4"},` +
+		`"l1_data_gas_price":{"price_in_wei":"0x5"},"l1_da_mode":"CALLDATA","starknet_version":"0.13.1"`
+	accepted := `{"status":"ACCEPTED_ON_L2","block_hash":"0xa","block_number":7,"new_root":"0xb",` + header + `,"transactions":[]}`
+	pending := `{` + header + `,"transactions":[]}`
+
+	type testSetType struct {
+		Answer          string
+		ExpectedPending bool
+	}
+	testSet := []testSetType{
+		{Answer: accepted, ExpectedPending: false},
+		{Answer: pending, ExpectedPending: true},
+	}
+
+	for _, test := range testSet {
+		provider := &Provider{c: rawAnswers{
+			"starknet_getBlockWithTxHashes": test.Answer,
+			"starknet_getBlockWithTxs":      test.Answer,
+			"starknet_getBlockWithReceipts": test.Answer,
+		}}
+
+		txHashes, err := provider.TypedBlockWithTxHashes(context.Background(), WithBlockTag("pending"))
+		require.NoError(t, err)
+		txs, err := provider.TypedBlockWithTxs(context.Background(), WithBlockTag("pending"))
+		require.NoError(t, err)
+		receipts, err := provider.TypedBlockWithReceipts(context.Background(), WithBlockTag("pending"))
+		require.NoError(t, err)
+
+		for _, h := range []AnyBlockHeader{txHashes.AnyBlockHeader, txs.AnyBlockHeader, receipts.AnyBlockHeader} {
+			require.Equal(t, test.ExpectedPending, h.Pending())
+			require.Equal(t, test.ExpectedPending, h.Header() == nil)
+			require.Equal(t, "0x1", h.ParentHash().String())
+			require.Equal(t, uint64(2), h.Timestamp())
+			require.Equal(t, "0x3", h.SequencerAddress().String())
+			require.Equal(t, "0x4", h.L1GasPrice().PriceInWei.String())
+			require.Equal(t, "0x5", h.L1DataGasPrice().PriceInWei.String())
+			require.Equal(t, L1DAModeCalldata, h.L1DAMode())
+			require.Equal(t, "0.13.1", h.StarknetVersion())
+		}
+		require.Equal(t, test.ExpectedPending, txHashes.PendingBlock != nil)
+		require.Equal(t, test.ExpectedPending, txs.PendingBlock != nil)
+		require.Equal(t, test.ExpectedPending, receipts.PendingBlock != nil)
+		require.Empty(t, txHashes.Transactions())
+		require.Empty(t, txs.Transactions())
+		require.Empty(t, receipts.Transactions())
+		if !test.ExpectedPending {
+			require.Equal(t, uint64(7), txs.Header().BlockNumber)
+			require.IsType(t, &Block{}, txs.Value())
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package rpc
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// Cache stores the raw JSON results of the calls that can't change. A cache
+// must only be shared by providers connected to the same network.
+type Cache interface {
+	// Get returns the value stored under key, if any.
+	Get(key string) ([]byte, bool)
+	// Set stores value under key. It may drop the value or evict others.
+	Set(key string, value []byte)
+}
+
+// blockScopedMethods are the methods whose result is immutable once they
+// are pinned to a block hash or number.
+var blockScopedMethods = map[string]bool{
+	"starknet_getClass":                        true,
+	"starknet_getClassAt":                      true,
+	"starknet_getClassHashAt":                  true,
+	"starknet_getStorageAt":                    true,
+	"starknet_getNonce":                        true,
+	"starknet_getStorageProof":                 true,
+	"starknet_call":                            true,
+	"starknet_getBlockWithTxHashes":            true,
+	"starknet_getBlockWithTxs":                 true,
+	"starknet_getBlockWithReceipts":            true,
+	"starknet_getStateUpdate":                  true,
+	"starknet_getBlockTransactionCount":        true,
+	"starknet_getTransactionByBlockIdAndIndex": true,
+}
+
+// WithCache is a NewProvider option caching the results of the calls that
+// can't change. It is equivalent to WithMiddleware(CacheMiddleware(cache)).
+//
+// Parameters:
+// - cache: The cache storing the results
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithCache(cache Cache) ethrpc.ClientOption {
+	return WithMiddleware(CacheMiddleware(cache))
+}
+
+// NewCachingProvider returns a Provider that serves the calls that can't
+// change from cache and sends the others through provider. Both providers
+// share the same connection, closing one closes the other.
+//
+// Parameters:
+// - provider: The provider sending the calls
+// - cache: The cache storing the results
+// Returns:
+// - *Provider: the caching provider
+func NewCachingProvider(provider *Provider, cache Cache) *Provider {
+	return &Provider{c: newMiddlewareClient(provider.c, CacheMiddleware(cache)), chainID: provider.chainID}
+}
+
+// CacheMiddleware serves the calls that can't change from cache. These are
+// starknet_chainId, starknet_getCompiledCasm, the reads pinned to a block hash or number, never the
+// "latest" and "pending" tags, and the receipts of transactions accepted on L1.
+// Calls in a batch are cached one by one.
+//
+// Parameters:
+// - cache: The cache storing the results
+// Returns:
+// - Middleware: the cache middleware
+func CacheMiddleware(cache Cache) Middleware {
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			if method == BatchMethod && len(args) == 1 {
+				if b, ok := args[0].([]ethrpc.BatchElem); ok {
+					return cachedBatch(ctx, cache, next, b)
+				}
+			}
+
+			key, ok := cacheKey(method, args)
+			if !ok {
+				return next(ctx, result, method, args...)
+			}
+			if raw, ok := cache.Get(key); ok {
+				return json.Unmarshal(raw, result)
+			}
+			var raw json.RawMessage
+			if err := next(ctx, &raw, method, args...); err != nil {
+				return err
+			}
+			if len(raw) == 0 {
+				return nil
+			}
+			if isCacheableResult(method, raw) {
+				cache.Set(key, raw)
+			}
+			return json.Unmarshal(raw, result)
+		}
+	}
+}
+
+// cachedBatch serves the cached calls of a batch and sends the others.
+//
+// Parameters:
+// - ctx: The context of the batch
+// - cache: The cache storing the results
+// - next: The next CallFunc of the chain
+// - b: The calls of the batch
+// Returns:
+// - error: the error of the batch request, if any
+func cachedBatch(ctx context.Context, cache Cache, next CallFunc, b []ethrpc.BatchElem) error {
+	var missed []ethrpc.BatchElem
+	var missedAt []int
+	for i, elem := range b {
+		key, ok := cacheKey(elem.Method, elem.Args)
+		if ok {
+			if raw, ok := cache.Get(key); ok {
+				b[i].Error = json.Unmarshal(raw, elem.Result)
+				continue
+			}
+		}
+		missed = append(missed, elem)
+		missedAt = append(missedAt, i)
+	}
+	if len(missed) == 0 {
+		return nil
+	}
+
+	raws := make([]json.RawMessage, len(missed))
+	for i := range missed {
+		missed[i].Result = &raws[i]
+	}
+	if err := next(ctx, nil, BatchMethod, missed); err != nil {
+		return err
+	}
+	for i, elem := range missed {
+		at := missedAt[i]
+		b[at].Error = elem.Error
+		if elem.Error != nil || len(raws[i]) == 0 {
+			continue
+		}
+		if key, ok := cacheKey(elem.Method, elem.Args); ok && isCacheableResult(elem.Method, raws[i]) {
+			cache.Set(key, raws[i])
+		}
+		b[at].Error = json.Unmarshal(raws[i], b[at].Result)
+	}
+	return nil
+}
+
+// cacheKey returns the cache key of a call if its result may be cacheable.
+//
+// Parameters:
+// - method: The method of the call
+// - args: The arguments of the call
+// Returns:
+// - string: the cache key
+// - bool: whether the call may be served from cache
+func cacheKey(method string, args []interface{}) (string, bool) {
+	switch {
+	case method == "starknet_chainId", method == "starknet_getTransactionReceipt", method == "starknet_getCompiledCasm":
+	case blockScopedMethods[method]:
+		if !hasFixedBlockID(args) {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	params, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	return method + string(params), true
+}
+
+// hasFixedBlockID reports whether the arguments pin the call to a block hash
+// or number, given as an argument or as the block of a StorageProofInput.
+func hasFixedBlockID(args []interface{}) bool {
+	for _, arg := range args {
+		var blockID BlockID
+		switch arg := arg.(type) {
+		case BlockID:
+			blockID = arg
+		case *BlockID:
+			if arg == nil {
+				continue
+			}
+			blockID = *arg
+		case StorageProofInput:
+			blockID = arg.BlockID
+		case *StorageProofInput:
+			if arg == nil {
+				continue
+			}
+			blockID = arg.BlockID
+		default:
+			continue
+		}
+		return blockID.Tag == "" && (blockID.Hash != nil || blockID.Number != nil)
+	}
+	return false
+}
+
+// isCacheableResult reports whether the result of a call can be cached. The
+// receipts of transactions that are not accepted on L1 yet may still change.
+func isCacheableResult(method string, raw json.RawMessage) bool {
+	if string(raw) == "null" {
+		return false
+	}
+	if method != "starknet_getTransactionReceipt" {
+		return true
+	}
+	var receipt struct {
+		FinalityStatus TxnFinalityStatus `json:"finality_status"`
+	}
+	return json.Unmarshal(raw, &receipt) == nil && receipt.FinalityStatus == TxnFinalityStatusAcceptedOnL1
+}
+
+// LRUCache is an in-memory Cache evicting the least recently used entries
+// beyond its capacity.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+// lruEntry is an entry of an LRUCache.
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+// NewLRUCache creates an LRUCache holding up to capacity entries.
+//
+// Parameters:
+// - capacity: The maximum number of entries, at least 1
+// Returns:
+// - *LRUCache: a new LRUCache
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{capacity: capacity, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+// Get returns the value stored under key and marks it as recently used.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).value, true
+}
+
+// Set stores value under key, evicting the least recently used entry if the cache is full.
+func (c *LRUCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// DiskCache is a Cache storing each entry in a file of a directory, so that
+// it survives restarts. Use a separate directory for each network.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache creates a DiskCache in dir, creating the directory if needed.
+//
+// Parameters:
+// - dir: The directory of the cache files
+// Returns:
+// - *DiskCache: a new DiskCache
+// - error: an error if the directory could not be created
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// path returns the file of the entry stored under key.
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the value stored under key.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	value, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Set stores value under key. The file is written atomically; a failure to
+// write only means that the value is not cached.
+func (c *DiskCache) Set(key string, value []byte) {
+	tmp, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(value)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,128 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// countingAnswers is a rawAnswers callCloser counting the calls of each method.
+type countingAnswers struct {
+	rawAnswers
+	calls map[string]int
+}
+
+// CallContext counts the call and answers it.
+func (c *countingAnswers) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	c.calls[method]++
+	return c.rawAnswers.CallContext(ctx, result, method, args...)
+}
+
+// newCountingAnswers returns a countingAnswers answering with answers.
+func newCountingAnswers(answers map[string]string) *countingAnswers {
+	return &countingAnswers{rawAnswers: answers, calls: map[string]int{}}
+}
+
+// TestCachingProvider tests which calls are served from cache.
+func TestCachingProvider(t *testing.T) {
+	node := newCountingAnswers(map[string]string{
+		"starknet_chainId":               `"0x534e5f4d41494e"`,
+		"starknet_getClassHashAt":        `"0x123"`,
+		"starknet_getTransactionReceipt": `{"type":"INVOKE","transaction_hash":"0x1","actual_fee":{"amount":"0x1","unit":"WEI"},"execution_status":"SUCCEEDED","finality_status":"ACCEPTED_ON_L2","block_hash":"0x2","block_number":2,"messages_sent":[],"events":[],"execution_resources":{"steps":1}}`,
+	})
+	cache := NewLRUCache(16)
+	ctx := context.Background()
+	address := utils.TestHexToFelt(t, "0x1")
+
+	for i := 0; i < 2; i++ {
+		// the chain id is cached across providers sharing the cache
+		provider := NewCachingProvider(&Provider{c: node}, cache)
+		chainID, err := provider.ChainID(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "SN_MAIN", chainID)
+
+		for _, blockID := range []BlockID{WithBlockNumber(1), WithBlockHash(new(felt.Felt).SetUint64(2)), WithBlockTag("latest"), WithBlockTag("pending")} {
+			classHash, err := provider.ClassHashAt(ctx, blockID, address)
+			require.NoError(t, err)
+			require.Equal(t, "0x123", classHash.String())
+		}
+
+		_, err = provider.TransactionReceipt(ctx, address)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, node.calls["starknet_chainId"])
+	require.Equal(t, 6, node.calls["starknet_getClassHashAt"], "only latest and pending are sent twice")
+	require.Equal(t, 2, node.calls["starknet_getTransactionReceipt"], "the receipt is not accepted on L1")
+
+	node.rawAnswers["starknet_getTransactionReceipt"] = `{"type":"INVOKE","transaction_hash":"0x1","actual_fee":{"amount":"0x1","unit":"WEI"},"execution_status":"SUCCEEDED","finality_status":"ACCEPTED_ON_L1","block_hash":"0x2","block_number":2,"messages_sent":[],"events":[],"execution_resources":{"steps":1}}`
+	provider := NewCachingProvider(&Provider{c: node}, cache)
+	for i := 0; i < 2; i++ {
+		receipt, err := provider.TransactionReceipt(ctx, address)
+		require.NoError(t, err)
+		require.Equal(t, TxnFinalityStatusAcceptedOnL1, receipt.FinalityStatus)
+	}
+	require.Equal(t, 3, node.calls["starknet_getTransactionReceipt"])
+
+	// batched calls are cached one by one
+	var classHash *felt.Felt
+	var blockNumber uint64
+	node.rawAnswers["starknet_blockNumber"] = `7`
+	batch := provider.NewBatch()
+	classHashCall := batch.ClassHashAt(WithBlockNumber(1), address, &classHash)
+	blockNumberCall := batch.BlockNumber(&blockNumber)
+	require.NoError(t, batch.Send(ctx))
+	require.NoError(t, classHashCall.Err())
+	require.NoError(t, blockNumberCall.Err())
+	require.Equal(t, "0x123", classHash.String())
+	require.Equal(t, uint64(7), blockNumber)
+	require.Equal(t, 6, node.calls["starknet_getClassHashAt"])
+	require.Equal(t, 1, node.calls["starknet_blockNumber"])
+
+	// the storage proofs of a block number are cached, the latest ones aren't
+	node.rawAnswers["starknet_getStorageProof"] = `{"classes_proof": [], "contracts_proof": {"nodes": [], "contract_leaves_data": []}, "contracts_storage_proofs": [], "global_roots": {"contracts_tree_root": "0x1", "classes_tree_root": "0x2", "block_hash": "0x3"}}`
+	for i := 0; i < 2; i++ {
+		for _, blockID := range []BlockID{WithBlockNumber(1), WithBlockTag("latest")} {
+			_, err := provider.StorageProof(ctx, StorageProofInput{BlockID: blockID, ContractAddresses: []*felt.Felt{address}})
+			require.NoError(t, err)
+		}
+	}
+	require.Equal(t, 3, node.calls["starknet_getStorageProof"])
+	require.True(t, hasFixedBlockID([]interface{}{StorageProofInput{BlockID: WithBlockNumber(1)}}))
+	require.False(t, hasFixedBlockID([]interface{}{&StorageProofInput{BlockID: WithBlockTag("latest")}}))
+}
+
+// TestLRUCache tests that the least recently used entry is evicted.
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", []byte("1"))
+	cache.Set("b", []byte("2"))
+	_, ok := cache.Get("a")
+	require.True(t, ok)
+	cache.Set("c", []byte("3"))
+
+	require.Equal(t, 2, cache.Len())
+	_, ok = cache.Get("b")
+	require.False(t, ok)
+	value, ok := cache.Get("a")
+	require.True(t, ok)
+	require.Equal(t, "1", string(value))
+}
+
+// TestDiskCache tests that entries survive across instances.
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir)
+	require.NoError(t, err)
+	_, ok := cache.Get(`starknet_chainId[]`)
+	require.False(t, ok)
+	cache.Set(`starknet_chainId[]`, []byte(`"0x1"`))
+
+	cache, err = NewDiskCache(dir)
+	require.NoError(t, err)
+	value, ok := cache.Get(`starknet_chainId[]`)
+	require.True(t, ok)
+	require.Equal(t, `"0x1"`, string(value))
+}
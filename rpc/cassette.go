@@ -0,0 +1,390 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/nsf/jsondiff"
+)
+
+// CassetteMode selects what a Cassette does with the calls of a Provider.
+type CassetteMode int
+
+const (
+	// CassetteReplay answers the calls with the recorded results, without
+	// contacting the node, and fails the calls that weren't recorded.
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord sends the calls to the node and records them, Save
+	// writes them to the fixture file.
+	CassetteRecord
+	// CassetteCompare sends the calls to the node and compares the results
+	// with the recorded ones, see Cassette.Diffs.
+	CassetteCompare
+)
+
+// ErrCassetteMismatch is matched with errors.Is by the error of a call that
+// doesn't match any recorded call in CassetteReplay mode.
+var ErrCassetteMismatch = errors.New("the call wasn't recorded in the cassette")
+
+// Interaction is a call recorded in a Cassette.
+type Interaction struct {
+	Method string `json:"method"`
+	// Params the normalised parameters of the call
+	Params json.RawMessage `json:"params"`
+	// Result the raw result of the call, empty if the call failed
+	Result json.RawMessage `json:"result,omitempty"`
+	// Error the error answered by the node, if any
+	Error *RPCError `json:"error,omitempty"`
+}
+
+// CassetteDiff is a difference found in CassetteCompare mode between a live and a recorded call.
+type CassetteDiff struct {
+	Method string
+	Params json.RawMessage
+	// Diff the difference between the recorded and the live results, or a
+	// description of the mismatch when the call wasn't recorded
+	Diff string
+}
+
+// Cassette records the calls of a Provider to a fixture file and replays
+// them, so that integration tests run without a node. It is installed with
+// WithCassette.
+//
+// A call matches a recorded call with the same method and normalised
+// parameters. The calls repeated with the same parameters, such as a
+// receipt polled until the transaction is accepted, are replayed in order,
+// the last result being served again once they are exhausted.
+type Cassette struct {
+	path string
+	mode CassetteMode
+	// Normalize rewrites the parameters of the calls before they are
+	// recorded and matched. It defaults to NormalizeParams.
+	Normalize func(method string, params json.RawMessage) json.RawMessage
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+	diffs        []CassetteDiff
+}
+
+// LoadCassette opens the cassette stored at path. The file is read in
+// CassetteReplay and CassetteCompare modes. In CassetteRecord mode the cassette
+// starts empty and the file is only written by Save.
+//
+// Parameters:
+// - path: The path of the fixture file
+// - mode: What to do with the calls
+// Returns:
+// - *Cassette: the cassette
+// - error: an error if the fixture file can't be read
+func LoadCassette(path string, mode CassetteMode) (*Cassette, error) {
+	c := &Cassette{path: path, mode: mode, Normalize: NormalizeParams}
+	if mode == CassetteRecord {
+		return c, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &c.interactions); err != nil {
+		return nil, fmt.Errorf("decoding the cassette %s: %w", path, err)
+	}
+	for i := range c.interactions {
+		// the params are matched byte for byte with the compact form of the calls
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, c.interactions[i].Params); err != nil {
+			return nil, fmt.Errorf("decoding the cassette %s: %w", path, err)
+		}
+		c.interactions[i].Params = compact.Bytes()
+	}
+	c.replayed = make([]bool, len(c.interactions))
+	return c, nil
+}
+
+// Save writes the recorded calls to the fixture file.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: an error if the file can't be written
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	content, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(content, '\n'), 0o644)
+}
+
+// Interactions returns the calls of the cassette, recorded or loaded.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []Interaction: the calls, in order
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.interactions...)
+}
+
+// Diffs returns the differences found so far in CassetteCompare mode.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []CassetteDiff: the differences, in the order of the calls
+func (c *Cassette) Diffs() []CassetteDiff {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]CassetteDiff(nil), c.diffs...)
+}
+
+// WithCassette is a NewProvider option sending the calls of the Provider
+// through a cassette. The cassette sits below the other middlewares, so the
+// spec version query is recorded and replayed too. In CassetteReplay mode
+// the node is never contacted and the URL given to NewProvider is not used.
+//
+// Parameters:
+// - cassette: The cassette
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithCassette(cassette *Cassette) ethrpc.ClientOption {
+	return newProviderOption(func(cfg *providerConfig) {
+		cfg.cassette = cassette
+	})
+}
+
+// NormalizeParams is the default normaliser of the parameters of a
+// Cassette. The JSON is re-encoded with sorted keys and the "latest" and
+// "pending" block tags, whose block changes between the recording and the
+// replay, are replaced with "head".
+//
+// Parameters:
+// - method: The method of the call
+// - params: The parameters of the call, as a JSON array
+// Returns:
+// - json.RawMessage: the normalised parameters
+func NormalizeParams(method string, params json.RawMessage) json.RawMessage {
+	var decoded interface{}
+	if err := json.Unmarshal(params, &decoded); err != nil {
+		return params
+	}
+	decoded = normalizeTags(decoded)
+	normalized, err := json.Marshal(decoded)
+	if err != nil {
+		return params
+	}
+	return normalized
+}
+
+// normalizeTags replaces the head block tags of a decoded JSON value.
+func normalizeTags(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if v == "latest" || v == "pending" {
+			return "head"
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeTags(v[i])
+		}
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = normalizeTags(v[key])
+		}
+	}
+	return value
+}
+
+// params returns the normalised parameters of a call.
+func (c *Cassette) params(method string, args []interface{}) (json.RawMessage, error) {
+	if args == nil {
+		args = []interface{}{}
+	}
+	params, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	if c.Normalize != nil {
+		params = c.Normalize(method, params)
+	}
+	return params, nil
+}
+
+// match returns the recorded call matching a call, the first one not
+// replayed yet or else the last one. It must be called with the lock held.
+func (c *Cassette) match(method string, params json.RawMessage) (int, bool) {
+	last := -1
+	for i, interaction := range c.interactions {
+		if interaction.Method != method || !bytes.Equal(interaction.Params, params) {
+			continue
+		}
+		if !c.replayed[i] {
+			return i, true
+		}
+		last = i
+	}
+	return last, last >= 0
+}
+
+// replay answers a call with the recorded result.
+func (c *Cassette) replay(result interface{}, method string, params json.RawMessage) error {
+	c.mu.Lock()
+	i, ok := c.match(method, params)
+	if !ok {
+		c.mu.Unlock()
+		return fmt.Errorf("%w: %s %s", ErrCassetteMismatch, method, params)
+	}
+	c.replayed[i] = true
+	interaction := c.interactions[i]
+	c.mu.Unlock()
+
+	if interaction.Error != nil {
+		return &RPCError{Code: interaction.Error.Code, Message: interaction.Error.Message, Data: interaction.Error.Data}
+	}
+	return json.Unmarshal(interaction.Result, result)
+}
+
+// observe records or compares the outcome of a call sent to the node and
+// decodes its result.
+func (c *Cassette) observe(result interface{}, method string, params, raw json.RawMessage, err error) error {
+	interaction := Interaction{Method: method, Params: params}
+	if err != nil {
+		var nodeErr ethrpc.Error
+		if !errors.As(err, &nodeErr) {
+			// not answered by the node, nothing to record
+			return err
+		}
+		interaction.Error = &RPCError{Code: nodeErr.ErrorCode(), Message: nodeErr.Error()}
+		var dataErr ethrpc.DataError
+		if errors.As(err, &dataErr) {
+			interaction.Error.Data = dataErr.ErrorData()
+		}
+	} else {
+		interaction.Result = raw
+	}
+
+	c.mu.Lock()
+	if c.mode == CassetteRecord {
+		c.interactions = append(c.interactions, interaction)
+		c.replayed = append(c.replayed, true)
+	} else if diff := c.compare(interaction); diff != "" {
+		c.diffs = append(c.diffs, CassetteDiff{Method: method, Params: params, Diff: diff})
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// compare returns the difference between a live call and the matching
+// recorded call, empty if they match. It must be called with the lock held.
+func (c *Cassette) compare(live Interaction) string {
+	i, ok := c.match(live.Method, live.Params)
+	if !ok {
+		return "the call wasn't recorded"
+	}
+	c.replayed[i] = true
+	recorded := c.interactions[i]
+
+	recordedAnswer, err := json.Marshal(answerOf(recorded))
+	if err != nil {
+		return err.Error()
+	}
+	liveAnswer, err := json.Marshal(answerOf(live))
+	if err != nil {
+		return err.Error()
+	}
+	options := jsondiff.DefaultConsoleOptions()
+	difference, diff := jsondiff.Compare(recordedAnswer, liveAnswer, &options)
+	if difference == jsondiff.FullMatch {
+		return ""
+	}
+	return diff
+}
+
+// answerOf returns the result or the error of a call, for the comparisons.
+func answerOf(interaction Interaction) interface{} {
+	if interaction.Error != nil {
+		return map[string]interface{}{"error": interaction.Error}
+	}
+	return map[string]interface{}{"result": interaction.Result}
+}
+
+// cassetteClient is a callCloser sending its calls through a Cassette.
+type cassetteClient struct {
+	c        callCloser
+	cassette *Cassette
+}
+
+// CallContext replays the call, or sends it to the node and records or compares it.
+func (c *cassetteClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	params, err := c.cassette.params(method, args)
+	if err != nil {
+		return err
+	}
+	if c.cassette.mode == CassetteReplay {
+		return c.cassette.replay(result, method, params)
+	}
+	var raw json.RawMessage
+	err = c.c.CallContext(ctx, &raw, method, args...)
+	return c.cassette.observe(result, method, params, raw, err)
+}
+
+// BatchCallContext handles the calls of a batch one by one, the batch being
+// sent to the node as a whole.
+func (c *cassetteClient) BatchCallContext(ctx context.Context, b []ethrpc.BatchElem) error {
+	params := make([]json.RawMessage, len(b))
+	for i := range b {
+		var err error
+		if params[i], err = c.cassette.params(b[i].Method, b[i].Args); err != nil {
+			return err
+		}
+	}
+	if c.cassette.mode == CassetteReplay {
+		for i := range b {
+			b[i].Error = c.cassette.replay(b[i].Result, b[i].Method, params[i])
+		}
+		return nil
+	}
+
+	live := make([]ethrpc.BatchElem, len(b))
+	raws := make([]json.RawMessage, len(b))
+	for i := range b {
+		live[i] = ethrpc.BatchElem{Method: b[i].Method, Args: b[i].Args, Result: &raws[i]}
+	}
+	if bc, ok := c.c.(batchCallCloser); ok {
+		if err := bc.BatchCallContext(ctx, live); err != nil {
+			return err
+		}
+	} else {
+		for i := range live {
+			live[i].Error = c.c.CallContext(ctx, live[i].Result, live[i].Method, live[i].Args...)
+		}
+	}
+	for i := range b {
+		b[i].Error = c.cassette.observe(b[i].Result, b[i].Method, params[i], raws[i], live[i].Error)
+	}
+	return nil
+}
+
+// Close closes the underlying client.
+func (c *cassetteClient) Close() {
+	c.c.Close()
+}
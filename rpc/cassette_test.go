@@ -0,0 +1,121 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// cassetteNode is a versionedNode failing the methods of errs.
+type cassetteNode struct {
+	versionedNode
+	errs map[string]error
+}
+
+// CallContext answers with the error of method if any, with the JSON answer otherwise.
+func (n *cassetteNode) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if err, ok := n.errs[method]; ok {
+		n.versionedNode.calls[method]++
+		return err
+	}
+	return n.versionedNode.CallContext(ctx, result, method, args...)
+}
+
+// TestCassette tests that the recorded calls are replayed without the node and compared with the live ones.
+func TestCassette(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	node := &cassetteNode{
+		versionedNode: versionedNode{
+			answers: map[string]string{
+				"starknet_specVersion": `"0.8.0"`,
+				"starknet_blockNumber": `7`,
+				"starknet_getNonce":    `"0x2"`,
+			},
+			calls: map[string]int{},
+			args:  map[string]string{},
+		},
+		errs: map[string]error{"starknet_getClassHashAt": &nodeError{code: 20, message: "Contract not found"}},
+	}
+	address := utils.TestHexToFelt(t, "0x1")
+
+	exercise := func(provider *Provider) {
+		number, err := provider.BlockNumber(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(7), number)
+		nonce, err := provider.Nonce(context.Background(), WithBlockTag("latest"), address)
+		require.NoError(t, err)
+		require.Equal(t, utils.TestHexToFelt(t, "0x2"), nonce)
+		_, err = provider.ClassHashAt(context.Background(), WithBlockTag("latest"), address)
+		require.ErrorIs(t, err, ErrContractNotFound)
+	}
+
+	recorder, err := LoadCassette(path, CassetteRecord)
+	require.NoError(t, err)
+	exercise(&Provider{c: newProviderClient(node, providerConfig{cassette: recorder})})
+	require.NoError(t, recorder.Save())
+	require.Len(t, recorder.Interactions(), 3)
+
+	// replayed without the node
+	player, err := LoadCassette(path, CassetteReplay)
+	require.NoError(t, err)
+	offline := &cassetteNode{errs: map[string]error{}}
+	provider := &Provider{c: newProviderClient(offline, providerConfig{cassette: player})}
+	exercise(provider)
+	require.Empty(t, offline.calls)
+
+	// the block tags are normalised, the other parameters must match
+	_, err = provider.Nonce(context.Background(), WithBlockTag("pending"), address)
+	require.NoError(t, err)
+	_, err = provider.Nonce(context.Background(), WithBlockNumber(3), address)
+	require.True(t, errors.Is(err, ErrCassetteMismatch))
+
+	// compared with the live answers
+	node.answers["starknet_getNonce"] = `"0x3"`
+	comparer, err := LoadCassette(path, CassetteCompare)
+	require.NoError(t, err)
+	provider = &Provider{c: newProviderClient(node, providerConfig{cassette: comparer})}
+	nonce, err := provider.Nonce(context.Background(), WithBlockTag("latest"), address)
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0x3"), nonce)
+	diffs := comparer.Diffs()
+	require.Len(t, diffs, 1)
+	require.Equal(t, "starknet_getNonce", diffs[0].Method)
+	require.Contains(t, diffs[0].Diff, "0x3")
+}
+
+// TestCassetteRepeatedCalls tests that the repeated calls are replayed in order.
+func TestCassetteRepeatedCalls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	node := &versionedNode{answers: map[string]string{"starknet_blockNumber": `1`}}
+
+	recorder, err := LoadCassette(path, CassetteRecord)
+	require.NoError(t, err)
+	provider := &Provider{c: newProviderClient(node, providerConfig{cassette: recorder, specVersionMode: SpecVersionUnchecked})}
+	for _, answer := range []string{`1`, `2`} {
+		node.answers["starknet_blockNumber"] = answer
+		_, err := provider.BlockNumber(context.Background())
+		require.NoError(t, err)
+	}
+	require.NoError(t, recorder.Save())
+
+	player, err := LoadCassette(path, CassetteReplay)
+	require.NoError(t, err)
+	provider = &Provider{c: newProviderClient(node, providerConfig{cassette: player, specVersionMode: SpecVersionUnchecked})}
+	for _, expected := range []uint64{1, 2, 2} {
+		number, err := provider.BlockNumber(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, expected, number)
+	}
+
+	// batches are recorded call by call
+	var number uint64
+	batch := provider.NewBatch()
+	call := batch.BlockNumber(&number)
+	require.NoError(t, batch.Send(context.Background()))
+	require.NoError(t, call.Err())
+	require.Equal(t, uint64(2), number)
+}
@@ -39,6 +39,17 @@ func (provider *Provider) Syncing(ctx context.Context) (*SyncStatus, error) {
 	if err := provider.c.CallContext(ctx, &result, "starknet_syncing", []interface{}{}...); err != nil {
 		return nil, Err(InternalError, err)
 	}
+	return adaptSyncStatus(result)
+}
+
+// adaptSyncStatus converts the result of starknet_syncing to a SyncStatus.
+//
+// Parameters:
+// - result: The decoded result of starknet_syncing
+// Returns:
+// - *SyncStatus: The synchronization status
+// - error: An error if the result has an unexpected shape
+func adaptSyncStatus(result interface{}) (*SyncStatus, error) {
 	switch res := result.(type) {
 	case bool:
 		return &SyncStatus{SyncStatus: res}, nil
@@ -47,5 +58,4 @@ func (provider *Provider) Syncing(ctx context.Context) (*SyncStatus, error) {
 	default:
 		return nil, Err(InternalError, "internal error with starknet_syncing")
 	}
-
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
 	"github.com/NethermindEth/starknet.go/utils"
 )
 
@@ -160,3 +161,46 @@ func (provider *Provider) EstimateMessageFee(ctx context.Context, msg MsgFromL1,
 	}
 	return &raw, nil
 }
+
+// StorageProof returns the Merkle paths of the requested classes, contracts
+// and storage slots in the state tries of a block, with the roots of the tries.
+//
+// Parameters:
+// - ctx: The context.Context for the function call
+// - input: The block and the items to prove
+// Returns:
+// - *StorageProof: the proofs and the global roots of the block
+// - error: an error if any
+func (provider *Provider) StorageProof(ctx context.Context, input StorageProofInput) (*StorageProof, error) {
+	classHashes, contractAddresses, storageKeys := input.ClassHashes, input.ContractAddresses, input.ContractsStorageKeys
+	if classHashes == nil {
+		classHashes = []*felt.Felt{}
+	}
+	if contractAddresses == nil {
+		contractAddresses = []*felt.Felt{}
+	}
+	if storageKeys == nil {
+		storageKeys = []ContractStorageKeys{}
+	}
+	var proof StorageProof
+	if err := do(ctx, provider.c, "starknet_getStorageProof", &proof, input.BlockID, classHashes, contractAddresses, storageKeys); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound, ErrStorageProofNotSupported)
+	}
+	return &proof, nil
+}
+
+// CompiledCasm returns the CASM compiled from the Sierra class with the given hash.
+//
+// Parameters:
+// - ctx: The context.Context for the function call
+// - classHash: The hash of the Sierra class
+// Returns:
+// - *contracts.CasmClass: the compiled class
+// - error: an error if any
+func (provider *Provider) CompiledCasm(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error) {
+	var casm contracts.CasmClass
+	if err := do(ctx, provider.c, "starknet_getCompiledCasm", &casm, classHash); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrCompilationError, ErrClassHashNotFound)
+	}
+	return &casm, nil
+}
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"reflect"
 	"strings"
 	"testing"
@@ -530,3 +531,86 @@ func TestEstimateFee(t *testing.T) {
 		require.Equal(t, test.expectedResp, resp)
 	}
 }
+
+// TestStorageProof tests the decoding of the starknet_getStorageProof result and its errors.
+func TestStorageProof(t *testing.T) {
+	provider := &Provider{c: rawAnswers{"starknet_getStorageProof": `{
+		"classes_proof": [{"node_hash": "0x1", "node": {"left": "0x2", "right": "0x3"}}],
+		"contracts_proof": {
+			"nodes": [{"node_hash": "0x4", "node": {"path": "0x5", "length": 3, "child": "0x6"}}],
+			"contract_leaves_data": [{"nonce": "0x0", "class_hash": "0x7", "storage_root": "0x8"}]
+		},
+		"contracts_storage_proofs": [[]],
+		"global_roots": {"contracts_tree_root": "0x9", "classes_tree_root": "0xa", "block_hash": "0xb"}
+	}`}}
+
+	proof, err := provider.StorageProof(context.Background(), StorageProofInput{BlockID: WithBlockTag("latest")})
+	require.NoError(t, err)
+	require.False(t, proof.ClassesProof[0].Node.IsEdge())
+	require.Equal(t, utils.TestHexToFelt(t, "0x3"), proof.ClassesProof[0].Node.Right)
+	edge := proof.ContractsProof.Nodes[0].Node
+	require.True(t, edge.IsEdge())
+	require.Equal(t, uint(3), edge.Length)
+	require.Equal(t, utils.TestHexToFelt(t, "0x8"), proof.ContractsProof.ContractLeavesData[0].StorageRoot)
+	require.Len(t, proof.ContractsStorageProofs, 1)
+	require.Equal(t, utils.TestHexToFelt(t, "0xb"), proof.GlobalRoots.BlockHash)
+
+	provider = &Provider{c: &scriptedErrors{errs: []error{ErrStorageProofNotSupported}}}
+	_, err = provider.StorageProof(context.Background(), StorageProofInput{BlockID: WithBlockNumber(1)})
+	require.Equal(t, ErrStorageProofNotSupported, err)
+}
+
+// TestCompiledCasm tests the decoding of the starknet_getCompiledCasm result and its errors.
+func TestCompiledCasm(t *testing.T) {
+	provider := &Provider{c: rawAnswers{"starknet_getCompiledCasm": `{
+		"prime": "0x800000000000011000000000000000000000000000000000000000000000001",
+		"compiler_version": "2.8.2",
+		"bytecode": ["0x1", "0x2"],
+		"hints": [[0, [{"AllocSegment": {"dst": {"register": "AP", "offset": 0}}}]]],
+		"bytecode_segment_lengths": [1, 1],
+		"entry_points_by_type": {
+			"CONSTRUCTOR": [],
+			"EXTERNAL": [{"selector": "0x3", "offset": 0, "builtins": ["range_check"]}],
+			"L1_HANDLER": []
+		}
+	}`}}
+
+	casm, err := provider.CompiledCasm(context.Background(), utils.TestHexToFelt(t, "0x1"))
+	require.NoError(t, err)
+	require.Equal(t, "2.8.2", casm.Version)
+	require.Len(t, casm.ByteCode, 2)
+	require.Equal(t, []string{"range_check"}, casm.EntryPointByType.External[0].Builtins)
+	require.JSONEq(t, `[1, 1]`, string(casm.BytecodeSegmentLengths))
+
+	compilationErr := &RPCError{Code: ErrCompilationError.Code, Message: ErrCompilationError.Message, Data: map[string]interface{}{"compilation_error": "boom"}}
+	provider = &Provider{c: &scriptedErrors{errs: []error{compilationErr}}}
+	_, err = provider.CompiledCasm(context.Background(), utils.TestHexToFelt(t, "0x1"))
+	require.ErrorIs(t, err, ErrCompilationError)
+	var data *CompilationErrData
+	require.ErrorAs(t, err, &data)
+	require.Equal(t, "boom", data.CompilationError)
+}
+
+// TestFeeEstimateV08 tests that the v0.8 gas fields are decoded and that the L1 data gas bound is only sent when set.
+func TestFeeEstimateV08(t *testing.T) {
+	var estimate FeeEstimate
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"l1_gas_consumed": "0x1", "l1_gas_price": "0x2",
+		"l2_gas_consumed": "0x3", "l2_gas_price": "0x4",
+		"l1_data_gas_consumed": "0x5", "l1_data_gas_price": "0x6",
+		"overall_fee": "0x2c", "unit": "FRI"
+	}`), &estimate))
+	require.Equal(t, utils.TestHexToFelt(t, "0x3"), estimate.L2GasConsumed)
+	require.Equal(t, utils.TestHexToFelt(t, "0x6"), estimate.L1DataGasPrice)
+	require.Equal(t, UnitStrk, estimate.FeeUnit)
+
+	bounds := ResourceBoundsMapping{L1Gas: ResourceBounds{MaxAmount: "0x1", MaxPricePerUnit: "0x2"}, L2Gas: ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"}}
+	raw, err := json.Marshal(bounds)
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "l1_data_gas")
+
+	bounds.L1DataGas = &ResourceBounds{MaxAmount: "0x3", MaxPricePerUnit: "0x4"}
+	raw, err = json.Marshal(bounds)
+	require.NoError(t, err)
+	require.Contains(t, string(raw), `"l1_data_gas":{"max_amount":"0x3","max_price_per_unit":"0x4"}`)
+}
@@ -2,7 +2,12 @@ package rpc
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
 )
 
 const (
@@ -35,33 +40,74 @@ func Err(code int, data any) *RPCError {
 	}
 }
 
-// tryUnwrapToRPCErr unwraps the error and checks if it matches any of the given RPC errors.
-// If a match is found, the corresponding RPC error is returned.
-// If no match is found, the function returns an InternalError with the original error.
+// tryUnwrapToRPCErr converts the error of a call into an *RPCError.
+//
+// The errors answered by the node keep their code, message and data. The data
+// of the Starknet errors that carry a structure, such as ErrContractError and
+// ErrTxnExec, is decoded into its typed form, see ContractErrData and
+// TxnExecErrData. The errors of the transport, such as an *ethrpc.HTTPError
+// or a context error, are returned as an InternalError wrapping them, so that
+// errors.Is and errors.As still see them.
 //
 // Parameters:
 // - err: The error to be unwrapped
-// - rpcErrors: variadic list of *RPCError objects to be checked
+// - rpcErrors: The errors documented for the method, for the reader: every
+// error of the node is kept, whether it is listed or not
 // Returns:
-// - error: the original error
+// - *RPCError: the RPC error
 func tryUnwrapToRPCErr(err error, rpcErrors ...*RPCError) *RPCError {
-	errBytes, errIn := json.Marshal(err)
-	if errIn != nil {
-		return Err(InternalError, errIn.Error())
+	var nodeErr *RPCError
+	var nodeErrIn ethrpc.Error
+	switch {
+	case errors.As(err, &nodeErr):
+		nodeErr = &RPCError{Code: nodeErr.Code, Message: nodeErr.Message, Data: nodeErr.Data}
+	case errors.As(err, &nodeErrIn):
+		nodeErr = &RPCError{Code: nodeErrIn.ErrorCode(), Message: nodeErrIn.Error()}
+		var dataErr ethrpc.DataError
+		if errors.As(err, &dataErr) {
+			nodeErr.Data = dataErr.ErrorData()
+		}
+	default:
+		// not answered by the node, keep the transport error for the callers inspecting it
+		return Err(InternalError, err)
 	}
 
-	var nodeErr RPCError
-	errIn = json.Unmarshal(errBytes, &nodeErr)
-	if errIn != nil {
-		return Err(InternalError, errIn.Error())
-	}
+	nodeErr.Data = decodeErrorData(nodeErr.Code, nodeErr.Data)
+	return nodeErr
+}
 
-	for _, rpcErr := range rpcErrors {
-		if nodeErr.Code == rpcErr.Code && nodeErr.Message == rpcErr.Message {
-			return &nodeErr
-		}
+// decodeErrorData decodes the data of the Starknet errors that carry a structure.
+//
+// Parameters:
+// - code: The code of the error
+// - data: The data of the error, as decoded from JSON
+// Returns:
+// - any: the typed data, or data itself if it has no typed form or doesn't decode
+func decodeErrorData(code int, data any) any {
+	if data == nil {
+		return nil
 	}
-	return Err(InternalError, fmt.Sprintln(nodeErr.Code, nodeErr.Message, nodeErr.Data))
+	var typed interface{}
+	switch code {
+	case ErrContractError.Code:
+		typed = &ContractErrData{}
+	case ErrTxnExec.Code:
+		typed = &TxnExecErrData{}
+	case ErrNoTraceAvailable.Code:
+		typed = &TraceStatusErrData{}
+	case ErrCompilationError.Code:
+		typed = &CompilationErrData{}
+	default:
+		return data
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	if err := json.Unmarshal(raw, typed); err != nil {
+		return data
+	}
+	return typed
 }
 
 type RPCError struct {
@@ -74,6 +120,35 @@ func (e RPCError) Error() string {
 	return e.Message
 }
 
+// Is reports whether target is an *RPCError with the same code, so that the
+// error sentinels match with errors.Is whatever the message and the data.
+//
+// Parameters:
+// - target: The error to compare to
+// Returns:
+// - bool: true if target is an *RPCError with the same code
+func (e RPCError) Is(target error) bool {
+	t, ok := target.(*RPCError)
+	return ok && t != nil && t.Code == e.Code
+}
+
+// Unwrap returns the data of the error if it is an error, such as the typed
+// data of ErrContractError and ErrTxnExec or the transport error wrapped in
+// an InternalError.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: the data of the error, nil if it isn't an error
+func (e RPCError) Unwrap() error {
+	if err, ok := e.Data.(error); ok {
+		return err
+	}
+	return nil
+}
+
 var (
 	ErrFailedToReceiveTxn = &RPCError{
 		Code:    1,
@@ -87,6 +162,10 @@ var (
 		Code:    20,
 		Message: "Contract not found",
 	}
+	ErrEntrypointNotFound = &RPCError{
+		Code:    21,
+		Message: "Requested entrypoint does not exist in the contract",
+	}
 	ErrBlockNotFound = &RPCError{
 		Code:    24,
 		Message: "Block not found",
@@ -135,6 +214,10 @@ var (
 		Code:    41,
 		Message: "Transaction execution error",
 	}
+	ErrStorageProofNotSupported = &RPCError{
+		Code:    42,
+		Message: "the node doesn't support storage proofs for blocks that are too far in the past",
+	}
 	ErrInvalidContractClass = &RPCError{
 		Code:    50,
 		Message: "Invalid contract class",
@@ -191,4 +274,150 @@ var (
 		Code:    63,
 		Message: "An unexpected error occurred",
 	}
+	ErrReplacementTransactionUnderpriced = &RPCError{
+		Code:    64,
+		Message: "Replacement transaction is underpriced",
+	}
+	ErrFeeBelowMinimum = &RPCError{
+		Code:    65,
+		Message: "Transaction fee below minimum",
+	}
+	ErrInvalidSubscriptionID = &RPCError{
+		Code:    66,
+		Message: "Invalid subscription id",
+	}
+	ErrTooManyAddressesInFilter = &RPCError{
+		Code:    67,
+		Message: "Too many addresses in filter sender_address filter",
+	}
+	ErrTooManyBlocksBack = &RPCError{
+		Code:    68,
+		Message: "Cannot go back more than 1024 blocks",
+	}
+	ErrCompilationError = &RPCError{
+		Code:    100,
+		Message: "Failed to compile the contract",
+	}
 )
+
+// ContractErrData is the data of ErrContractError.
+type ContractErrData struct {
+	RevertError ContractExecutionError `json:"revert_error"`
+}
+
+// Error returns the revert error of the contract.
+func (d *ContractErrData) Error() string {
+	return d.RevertError.String()
+}
+
+// TxnExecErrData is the data of ErrTxnExec.
+type TxnExecErrData struct {
+	// The index of the failed transaction in the request
+	TransactionIndex int                    `json:"transaction_index"`
+	ExecutionError   ContractExecutionError `json:"execution_error"`
+}
+
+// Error returns the index and the execution error of the transaction.
+func (d *TxnExecErrData) Error() string {
+	return fmt.Sprintf("transaction %d: %s", d.TransactionIndex, d.ExecutionError.String())
+}
+
+// TraceStatusErrData is the data of ErrNoTraceAvailable.
+type TraceStatusErrData struct {
+	// The status of the transaction, RECEIVED or REJECTED
+	Status string `json:"status"`
+}
+
+// Error returns the status of the transaction.
+func (d *TraceStatusErrData) Error() string {
+	return "transaction status " + d.Status
+}
+
+// CompilationErrData is the data of ErrCompilationError.
+type CompilationErrData struct {
+	CompilationError string `json:"compilation_error"`
+}
+
+// Error returns the error of the compiler.
+func (d *CompilationErrData) Error() string {
+	return d.CompilationError
+}
+
+// ContractExecutionError is the error of a contract execution. Up to v0.7
+// it is a plain message; since v0.8 it is either a message or the error of a
+// call, with the error of the inner call.
+type ContractExecutionError struct {
+	// Message the error message, empty if Call is set
+	Message string
+	// Call the failed call, nil for a plain message
+	Call *ContractExecutionErrorInner
+}
+
+// ContractExecutionErrorInner is a call failing with the error of its inner call.
+type ContractExecutionErrorInner struct {
+	ContractAddress *felt.Felt              `json:"contract_address"`
+	ClassHash       *felt.Felt              `json:"class_hash"`
+	Selector        *felt.Felt              `json:"selector"`
+	Error           *ContractExecutionError `json:"error"`
+}
+
+// UnmarshalJSON decodes either a message or a failed call.
+//
+// Parameters:
+// - data: The JSON string or object
+// Returns:
+// - error: an error if the decoding fails
+func (e *ContractExecutionError) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*e = ContractExecutionError{}
+		return json.Unmarshal(data, &e.Message)
+	}
+	var call ContractExecutionErrorInner
+	if err := json.Unmarshal(data, &call); err != nil {
+		return err
+	}
+	*e = ContractExecutionError{Call: &call}
+	return nil
+}
+
+// MarshalJSON encodes the message or the failed call.
+//
+// Returns:
+// - []byte: the JSON string or object
+// - error: an error if the encoding fails
+func (e ContractExecutionError) MarshalJSON() ([]byte, error) {
+	if e.Call != nil {
+		return json.Marshal(e.Call)
+	}
+	return json.Marshal(e.Message)
+}
+
+// String returns the calls leading to the error, outermost first, and the message.
+func (e ContractExecutionError) String() string {
+	var s strings.Builder
+	for current := &e; current != nil; {
+		if current.Call == nil {
+			s.WriteString(current.Message)
+			break
+		}
+		fmt.Fprintf(&s, "contract %s, selector %s: ", current.Call.ContractAddress, current.Call.Selector)
+		current = current.Call.Error
+	}
+	return s.String()
+}
+
+// RevertReason returns the message of the innermost failed call.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the message of the error
+func (e ContractExecutionError) RevertReason() string {
+	current := &e
+	for current.Call != nil && current.Call.Error != nil {
+		current = current.Call.Error
+	}
+	return current.Message
+}
@@ -2,8 +2,11 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"testing"
 
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,3 +24,59 @@ func TestRPCError(t *testing.T) {
 		require.NotNil(t, rpcErr.Data, "-ChuckSize error message-")
 	}
 }
+
+// nodeError is an error answered by a node, as returned by the ethrpc client.
+type nodeError struct {
+	code    int
+	message string
+	data    interface{}
+}
+
+// Error returns the message of the error.
+func (e *nodeError) Error() string { return e.message }
+
+// ErrorCode returns the code of the error.
+func (e *nodeError) ErrorCode() int { return e.code }
+
+// ErrorData returns the data of the error.
+func (e *nodeError) ErrorData() interface{} { return e.data }
+
+// TestTryUnwrapToRPCErr tests that the errors keep their code, typed data and transport cause.
+func TestTryUnwrapToRPCErr(t *testing.T) {
+	var nested map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"transaction_index": 1,
+		"execution_error": {
+			"contract_address": "0x1", "class_hash": "0x2", "selector": "0x3",
+			"error": {"contract_address": "0x4", "class_hash": "0x5", "selector": "0x6", "error": "u256_sub Overflow"}
+		}
+	}`), &nested))
+
+	err := tryUnwrapToRPCErr(&nodeError{code: 41, message: "Transaction execution error", data: nested}, ErrTxnExec)
+	require.ErrorIs(t, err, ErrTxnExec)
+	require.NotErrorIs(t, err, ErrContractError)
+	var txnErr *TxnExecErrData
+	require.ErrorAs(t, err, &txnErr)
+	require.Equal(t, 1, txnErr.TransactionIndex)
+	require.Equal(t, "u256_sub Overflow", txnErr.ExecutionError.RevertReason())
+	require.Equal(t, "0x4", txnErr.ExecutionError.Call.Error.Call.ContractAddress.String())
+	require.Equal(t, "transaction 1: contract 0x1, selector 0x3: contract 0x4, selector 0x6: u256_sub Overflow", txnErr.Error())
+
+	err = tryUnwrapToRPCErr(&nodeError{code: 40, message: "Contract error", data: map[string]interface{}{"revert_error": "Error in the called contract"}})
+	var contractErr *ContractErrData
+	require.ErrorAs(t, err, &contractErr)
+	require.Equal(t, "Error in the called contract", contractErr.RevertError.RevertReason())
+
+	// unknown errors keep their code and data
+	err = tryUnwrapToRPCErr(&nodeError{code: 1234, message: "custom", data: "details"}, ErrBlockNotFound)
+	require.Equal(t, &RPCError{Code: 1234, Message: "custom", Data: "details"}, err)
+
+	// transport errors stay wrapped
+	httpErr := ethrpc.HTTPError{StatusCode: 503, Status: "503 Service Unavailable"}
+	err = tryUnwrapToRPCErr(fmt.Errorf("post: %w", httpErr), ErrBlockNotFound)
+	require.Equal(t, InternalError, err.Code)
+	var gotHTTPErr ethrpc.HTTPError
+	require.ErrorAs(t, err, &gotHTTPErr)
+	require.Equal(t, 503, gotHTTPErr.StatusCode)
+	require.ErrorIs(t, tryUnwrapToRPCErr(context.DeadlineExceeded), context.DeadlineExceeded)
+}
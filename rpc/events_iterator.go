@@ -0,0 +1,350 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+const (
+	// defaultEventsChunkSize is the page size used when the EventsInput doesn't set one.
+	defaultEventsChunkSize = 100
+	// defaultBufferedPages is the number of pages a worker fetches ahead when the config doesn't set one.
+	defaultBufferedPages = 2
+)
+
+// EventIteratorConfig configures an EventIterator.
+type EventIteratorConfig struct {
+	// Workers the number of block ranges fetched concurrently. Values lower
+	// than 2 fetch the pages one after the other. The range is only split
+	// when FromBlock is a block number and ToBlock a block number or "latest".
+	Workers int
+	// SegmentSize the number of blocks of each range fetched by a worker.
+	// If zero, the range is split in Workers equal parts.
+	SegmentSize uint64
+	// BufferedPages the number of pages each worker fetches ahead of the
+	// iterator before waiting for them to be consumed. If zero, 2.
+	BufferedPages int
+}
+
+// EventIterator walks through every event matching an EventsInput, fetching
+// the pages as needed. The events are yielded in (block, transaction, event)
+// order, even when the block range is split across several workers.
+//
+//	it := rpc.NewEventIterator(ctx, provider, input, rpc.EventIteratorConfig{})
+//	defer it.Close()
+//	for it.Next() {
+//		event := it.Event()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type EventIterator struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	provider RpcProvider
+	input    EventsInput
+	config   EventIteratorConfig
+
+	// chunkSize the page size, shared by the workers so that it only shrinks once
+	chunkMu   sync.Mutex
+	chunkSize int
+
+	planned bool
+	page    []EmittedEvent
+	pos     int
+	event   *EmittedEvent
+	err     error
+
+	// sequential mode
+	exhausted bool
+
+	// parallel mode
+	segments []*eventSegment
+	started  int
+	current  int
+}
+
+// eventSegment is a block range fetched by a worker.
+type eventSegment struct {
+	from, to uint64
+	// pages the fetched pages, closed once the worker is done
+	pages chan []EmittedEvent
+	// err the error of the worker, set before pages is closed
+	err error
+}
+
+// NewEventIterator creates an iterator over the events matching input. The
+// iterator stops at the first error, which is then returned by Err. ctx
+// bounds every request made by the iterator.
+//
+// Parameters:
+// - ctx: The context.Context of the requests
+// - provider: The provider to fetch the events from
+// - input: The filter of the events, its ChunkSize is the page size
+// - config: The iterator configuration
+// Returns:
+// - *EventIterator: a new EventIterator
+func NewEventIterator(ctx context.Context, provider RpcProvider, input EventsInput, config EventIteratorConfig) *EventIterator {
+	ctx, cancel := context.WithCancel(ctx)
+	if input.ChunkSize <= 0 {
+		input.ChunkSize = defaultEventsChunkSize
+	}
+	if config.BufferedPages <= 0 {
+		config.BufferedPages = defaultBufferedPages
+	}
+	return &EventIterator{ctx: ctx, cancel: cancel, provider: provider, input: input, config: config, chunkSize: input.ChunkSize}
+}
+
+// ForEachEvent calls fn for every event matching input, in order, until fn
+// returns an error or every event has been visited.
+//
+// Parameters:
+// - ctx: The context.Context of the requests
+// - provider: The provider to fetch the events from
+// - input: The filter of the events, its ChunkSize is the page size
+// - config: The iterator configuration
+// - fn: The function called with each event
+// Returns:
+// - error: the error of fn or of a request, if any
+func ForEachEvent(ctx context.Context, provider RpcProvider, input EventsInput, config EventIteratorConfig, fn func(event *EmittedEvent) error) error {
+	it := NewEventIterator(ctx, provider, input, config)
+	defer it.Close()
+	for it.Next() {
+		if err := fn(it.Event()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// Next advances to the next event, fetching pages as needed.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: false once every event has been visited or an error occurred
+func (it *EventIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if !it.planned {
+		it.planned = true
+		if it.err = it.plan(); it.err != nil {
+			return false
+		}
+	}
+
+	for it.pos >= len(it.page) {
+		var more bool
+		if it.segments != nil {
+			more, it.err = it.nextSegment()
+		} else {
+			more, it.err = it.nextPage()
+		}
+		if it.err != nil || !more {
+			it.event = nil
+			return false
+		}
+	}
+	it.event = &it.page[it.pos]
+	it.pos++
+	return true
+}
+
+// Event returns the current event.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *EmittedEvent: the event the iterator is on, nil before the first call to Next
+func (it *EventIterator) Event() *EmittedEvent {
+	return it.event
+}
+
+// Err returns the error that stopped the iteration, if any.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: the error, nil if the iteration completed or is still running
+func (it *EventIterator) Err() error {
+	return it.err
+}
+
+// Close stops the workers of the iterator. It is safe to call Close more than once.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (it *EventIterator) Close() {
+	it.cancel()
+}
+
+// plan splits the block range in segments if the iterator runs in parallel.
+func (it *EventIterator) plan() error {
+	if it.config.Workers < 2 {
+		return nil
+	}
+	from, to := it.input.FromBlock, it.input.ToBlock
+	if from.Hash != nil || from.Tag != "" || to.Hash != nil || (to.Number == nil && to.Tag != "latest") {
+		return nil
+	}
+
+	var first, last uint64
+	if from.Number != nil {
+		first = *from.Number
+	}
+	if to.Number != nil {
+		last = *to.Number
+	} else {
+		latest, err := it.provider.BlockNumber(it.ctx)
+		if err != nil {
+			return err
+		}
+		last = latest
+	}
+	if last < first {
+		it.segments = []*eventSegment{}
+		return nil
+	}
+
+	size := it.config.SegmentSize
+	if size == 0 {
+		size = (last - first + uint64(it.config.Workers)) / uint64(it.config.Workers)
+	}
+	it.segments = []*eventSegment{}
+	for start := first; ; start += size {
+		end := start + size - 1
+		if end > last || end < start {
+			end = last
+		}
+		it.segments = append(it.segments, &eventSegment{from: start, to: end, pages: make(chan []EmittedEvent, it.config.BufferedPages)})
+		if end == last {
+			break
+		}
+	}
+	return nil
+}
+
+// nextPage fetches the next page in sequential mode.
+//
+// Returns:
+// - bool: false if there are no pages left
+// - error: the error of the request, if any
+func (it *EventIterator) nextPage() (bool, error) {
+	if it.exhausted {
+		return false, nil
+	}
+	chunk, err := it.fetchPage(it.input)
+	if err != nil {
+		return false, err
+	}
+	it.page, it.pos = chunk.Events, 0
+	it.input.ContinuationToken = chunk.ContinuationToken
+	it.exhausted = chunk.ContinuationToken == ""
+	return true, nil
+}
+
+// nextSegment waits for the next page of the current segment in parallel
+// mode, starting the following segments so that up to Workers segments are
+// fetched at once.
+//
+// Returns:
+// - bool: false if there are no pages left
+// - error: the error of the segment, if any
+func (it *EventIterator) nextSegment() (bool, error) {
+	for it.current < len(it.segments) {
+		for it.started < len(it.segments) && it.started < it.current+it.config.Workers {
+			go it.fetchSegment(it.segments[it.started])
+			it.started++
+		}
+
+		segment := it.segments[it.current]
+		select {
+		case page, ok := <-segment.pages:
+			if ok {
+				it.page, it.pos = page, 0
+				return true, nil
+			}
+		case <-it.ctx.Done():
+			return false, it.ctx.Err()
+		}
+		// the segment is done, the iterator moves to the next one
+		it.segments[it.current] = nil
+		it.current++
+		if segment.err != nil {
+			return false, segment.err
+		}
+	}
+	return false, nil
+}
+
+// fetchSegment fetches the pages of a segment, waiting while BufferedPages
+// of them are not consumed.
+func (it *EventIterator) fetchSegment(segment *eventSegment) {
+	defer close(segment.pages)
+	input := it.input
+	input.FromBlock = WithBlockNumber(segment.from)
+	input.ToBlock = WithBlockNumber(segment.to)
+	input.ContinuationToken = ""
+	for {
+		chunk, err := it.fetchPage(input)
+		if err != nil {
+			segment.err = err
+			return
+		}
+		if len(chunk.Events) > 0 {
+			select {
+			case segment.pages <- chunk.Events:
+			case <-it.ctx.Done():
+				segment.err = it.ctx.Err()
+				return
+			}
+		}
+		if chunk.ContinuationToken == "" {
+			return
+		}
+		input.ContinuationToken = chunk.ContinuationToken
+	}
+}
+
+// fetchPage fetches a page of events, halving the chunk size of the
+// iterator while the node rejects it with ErrPageSizeTooBig.
+//
+// Parameters:
+// - input: The filter and page to fetch, its ChunkSize is ignored
+// Returns:
+// - *EventChunk: the page of events
+// - error: the error of the request, if any
+func (it *EventIterator) fetchPage(input EventsInput) (*EventChunk, error) {
+	for {
+		it.chunkMu.Lock()
+		input.ChunkSize = it.chunkSize
+		it.chunkMu.Unlock()
+
+		chunk, err := it.provider.Events(it.ctx, input)
+		if err == nil {
+			return chunk, nil
+		}
+		var rpcErr *RPCError
+		if !errors.As(err, &rpcErr) || rpcErr.Code != ErrPageSizeTooBig.Code || input.ChunkSize <= 1 {
+			return nil, err
+		}
+		it.chunkMu.Lock()
+		if it.chunkSize >= input.ChunkSize {
+			it.chunkSize = input.ChunkSize / 2
+		}
+		it.chunkMu.Unlock()
+	}
+}
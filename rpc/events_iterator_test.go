@@ -0,0 +1,173 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/stretchr/testify/require"
+)
+
+// eventsNode is a callCloser serving eventsPerBlock events for each block up
+// to head, rejecting pages larger than maxChunkSize.
+type eventsNode struct {
+	head           uint64
+	eventsPerBlock uint64
+	maxChunkSize   int
+
+	mu         sync.Mutex
+	chunkSizes []int
+}
+
+// CallContext answers starknet_blockNumber and starknet_getEvents.
+func (n *eventsNode) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	var answer interface{}
+	switch method {
+	case "starknet_blockNumber":
+		answer = n.head
+	case "starknet_getEvents":
+		input := args[0].(EventsInput)
+		n.mu.Lock()
+		n.chunkSizes = append(n.chunkSizes, input.ChunkSize)
+		n.mu.Unlock()
+		if input.ChunkSize > n.maxChunkSize {
+			return ErrPageSizeTooBig
+		}
+
+		from, to := uint64(0), n.head
+		if input.FromBlock.Number != nil {
+			from = *input.FromBlock.Number
+		}
+		if input.ToBlock.Number != nil {
+			to = *input.ToBlock.Number
+		}
+		var events []EmittedEvent
+		for block := from; block <= to; block++ {
+			for i := uint64(0); i < n.eventsPerBlock; i++ {
+				events = append(events, EmittedEvent{
+					Event:           Event{FromAddress: new(felt.Felt).SetUint64(i)},
+					BlockNumber:     block,
+					TransactionHash: new(felt.Felt).SetUint64(block*100 + i),
+				})
+			}
+		}
+
+		offset, _ := strconv.Atoi(input.ContinuationToken)
+		end := offset + input.ChunkSize
+		chunk := EventChunk{Events: []EmittedEvent{}}
+		if end < len(events) {
+			chunk.ContinuationToken = strconv.Itoa(end)
+		} else {
+			end = len(events)
+		}
+		chunk.Events = append(chunk.Events, events[offset:end]...)
+		answer = chunk
+	default:
+		return errNotFound
+	}
+	raw, err := json.Marshal(answer)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// Close does nothing.
+func (n *eventsNode) Close() {}
+
+// TestEventIterator tests that every event is yielded in order, sequentially and in parallel.
+func TestEventIterator(t *testing.T) {
+	type testSetType struct {
+		Input  EventsInput
+		Config EventIteratorConfig
+	}
+	testSet := []testSetType{
+		{
+			Input:  EventsInput{EventFilter: EventFilter{FromBlock: WithBlockNumber(3), ToBlock: WithBlockTag("latest")}, ResultPageRequest: ResultPageRequest{ChunkSize: 10}},
+			Config: EventIteratorConfig{},
+		},
+		{
+			Input:  EventsInput{EventFilter: EventFilter{FromBlock: WithBlockNumber(3), ToBlock: WithBlockTag("latest")}, ResultPageRequest: ResultPageRequest{ChunkSize: 10}},
+			Config: EventIteratorConfig{Workers: 3},
+		},
+		{
+			Input:  EventsInput{EventFilter: EventFilter{FromBlock: WithBlockNumber(3), ToBlock: WithBlockNumber(40)}, ResultPageRequest: ResultPageRequest{ChunkSize: 3}},
+			Config: EventIteratorConfig{Workers: 4, SegmentSize: 5},
+		},
+	}
+
+	for _, test := range testSet {
+		node := &eventsNode{head: 40, eventsPerBlock: 3, maxChunkSize: 4}
+		provider := &Provider{c: node}
+
+		var blocks []uint64
+		var txHashes []*felt.Felt
+		err := ForEachEvent(context.Background(), provider, test.Input, test.Config, func(event *EmittedEvent) error {
+			blocks = append(blocks, event.BlockNumber)
+			txHashes = append(txHashes, event.TransactionHash)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, blocks, 38*3)
+		for i := range blocks {
+			block := 3 + uint64(i/3)
+			require.Equal(t, block, blocks[i])
+			require.Equal(t, new(felt.Felt).SetUint64(block*100+uint64(i%3)), txHashes[i])
+		}
+		for _, size := range node.chunkSizes[1:] {
+			require.LessOrEqual(t, size, 5, "the chunk size is shrunk once")
+		}
+	}
+}
+
+// TestEventIteratorBufferedPages tests that the workers stop fetching while their pages are not consumed.
+func TestEventIteratorBufferedPages(t *testing.T) {
+	node := &eventsNode{head: 39, eventsPerBlock: 1, maxChunkSize: 1}
+	input := EventsInput{EventFilter: EventFilter{FromBlock: WithBlockNumber(0), ToBlock: WithBlockNumber(39)}, ResultPageRequest: ResultPageRequest{ChunkSize: 1}}
+	it := NewEventIterator(context.Background(), &Provider{c: node}, input, EventIteratorConfig{Workers: 2, SegmentSize: 20, BufferedPages: 1})
+	defer it.Close()
+
+	require.True(t, it.Next())
+	time.Sleep(50 * time.Millisecond)
+	node.mu.Lock()
+	requests := len(node.chunkSizes)
+	node.mu.Unlock()
+	// per segment, the consumed page, the buffered one and the one waiting to be buffered
+	require.LessOrEqual(t, requests, 2*3)
+
+	count := 1
+	for it.Next() {
+		require.Equal(t, uint64(count), it.Event().BlockNumber)
+		count++
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, 40, count)
+}
+
+// TestEventIteratorError tests that iteration stops at the first error.
+func TestEventIteratorError(t *testing.T) {
+	provider := &Provider{c: &eventsNode{head: 10, eventsPerBlock: 1, maxChunkSize: 0}}
+	it := NewEventIterator(context.Background(), provider, EventsInput{ResultPageRequest: ResultPageRequest{ChunkSize: 1}}, EventIteratorConfig{Workers: 2})
+	defer it.Close()
+	require.False(t, it.Next())
+	require.Nil(t, it.Event())
+	require.Equal(t, ErrPageSizeTooBig, it.Err())
+
+	stop := errors.New("stop")
+	provider = &Provider{c: &eventsNode{head: 10, eventsPerBlock: 1, maxChunkSize: 10}}
+	count := 0
+	err := ForEachEvent(context.Background(), provider, EventsInput{}, EventIteratorConfig{}, func(*EmittedEvent) error {
+		count++
+		if count == 2 {
+			return stop
+		}
+		return nil
+	})
+	require.Equal(t, stop, err)
+	require.Equal(t, 2, count)
+}
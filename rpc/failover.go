@@ -0,0 +1,644 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// ErrNoEndpointAvailable is returned by a FailoverProvider when no endpoint can serve a call.
+var ErrNoEndpointAvailable = errors.New("no endpoint available")
+
+// defaultHealthCheckInterval is the health check period used when FailoverConfig doesn't set one.
+const defaultHealthCheckInterval = 10 * time.Second
+
+// FailoverStrategy selects the order in which a FailoverProvider tries its endpoints.
+type FailoverStrategy int
+
+const (
+	// FailoverPriority sends every call to the first healthy endpoint, in the
+	// order the endpoints were given, and falls back to the next ones on failure.
+	FailoverPriority FailoverStrategy = iota
+	// FailoverRoundRobin spreads the calls over the healthy endpoints in turn.
+	FailoverRoundRobin
+)
+
+// FailoverConfig configures a FailoverProvider.
+type FailoverConfig struct {
+	// Strategy the order in which the endpoints are tried
+	Strategy FailoverStrategy
+	// MaxBlockLag the number of blocks an endpoint may fall behind the most
+	// advanced endpoint before it is ejected. Zero disables the check.
+	MaxBlockLag uint64
+	// HealthCheckInterval the period of the health checks started by Start, 10s if not set
+	HealthCheckInterval time.Duration
+}
+
+// failoverEndpoint is an endpoint of a FailoverProvider with its last known state.
+type failoverEndpoint struct {
+	provider *Provider
+	healthy  bool
+	head     uint64
+	err      error
+}
+
+// EndpointStatus is the state of an endpoint as seen by the last health check.
+type EndpointStatus struct {
+	// Healthy whether the endpoint receives calls
+	Healthy bool
+	// Head the last known block number of the endpoint
+	Head uint64
+	// Err the error of the last health check, if any
+	Err error
+}
+
+// FailoverProvider implements RpcProvider on top of several providers. Calls
+// failing with a transport or transient error are retried on the next
+// endpoint, and health checks eject the endpoints that fail, or that lag
+// behind the chain head by more than FailoverConfig.MaxBlockLag blocks.
+//
+// Reads of the "latest" and "pending" blocks are sent to endpoints that have
+// reached the highest block already served, so that the chain never appears
+// to move backwards between two calls, and only fall back to the endpoints
+// behind it when those fail. The block numbers returned by BlockNumber and by
+// the block reads record how far each endpoint is. Transactions are only
+// sent again to another endpoint when the first one certainly did not
+// receive them.
+type FailoverProvider struct {
+	strategy    FailoverStrategy
+	maxBlockLag uint64
+	interval    time.Duration
+
+	mu        sync.Mutex
+	endpoints []*failoverEndpoint
+	next      int
+	highWater uint64
+}
+
+var _ RpcProvider = &FailoverProvider{}
+
+// NewFailoverProvider creates a FailoverProvider over the given providers.
+// Every endpoint is considered healthy until the first health check.
+//
+// Parameters:
+// - providers: The providers of the endpoints, in priority order
+// - config: The failover configuration
+// Returns:
+// - *FailoverProvider: a new FailoverProvider
+// - error: an error if no provider is given
+func NewFailoverProvider(providers []*Provider, config FailoverConfig) (*FailoverProvider, error) {
+	if len(providers) == 0 {
+		return nil, ErrNoEndpointAvailable
+	}
+	f := &FailoverProvider{
+		strategy:    config.Strategy,
+		maxBlockLag: config.MaxBlockLag,
+		interval:    config.HealthCheckInterval,
+	}
+	if f.interval <= 0 {
+		f.interval = defaultHealthCheckInterval
+	}
+	for _, provider := range providers {
+		f.endpoints = append(f.endpoints, &failoverEndpoint{provider: provider, healthy: true})
+	}
+	return f, nil
+}
+
+// Start runs a health check right away and then periodically, until ctx is done.
+//
+// Parameters:
+// - ctx: The context bounding the health checks
+// Returns:
+//
+//	none
+func (f *FailoverProvider) Start(ctx context.Context) {
+	f.CheckHealth(ctx)
+	go func() {
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				f.CheckHealth(ctx)
+			}
+		}
+	}()
+}
+
+// CheckHealth queries Syncing and BlockNumber on every endpoint. An endpoint
+// is healthy if both calls succeed and it is within MaxBlockLag blocks of the
+// chain head, which is the highest block reported by any endpoint, including
+// the highest block known by the syncing ones.
+//
+// Parameters:
+// - ctx: The context.Context object for the requests
+// Returns:
+//
+//	none
+func (f *FailoverProvider) CheckHealth(ctx context.Context) {
+	type result struct {
+		head, highest uint64
+		err           error
+	}
+	results := make([]result, len(f.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range f.endpoints {
+		wg.Add(1)
+		go func(i int, provider *Provider) {
+			defer wg.Done()
+			status, err := provider.Syncing(ctx)
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			head, err := provider.BlockNumber(ctx)
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			results[i].head = head
+			results[i].highest = head
+			if status.SyncStatus && status.HighestBlockNum != "" {
+				if highest := utils.HexToBN(string(status.HighestBlockNum)); highest.IsUint64() && highest.Uint64() > head {
+					results[i].highest = highest.Uint64()
+				}
+			}
+		}(i, e.provider)
+	}
+	wg.Wait()
+
+	var chainHead uint64
+	for _, r := range results {
+		if r.err == nil && r.highest > chainHead {
+			chainHead = r.highest
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, e := range f.endpoints {
+		r := results[i]
+		e.err = r.err
+		if r.err != nil {
+			e.healthy = false
+			continue
+		}
+		e.head = r.head
+		e.healthy = f.maxBlockLag == 0 || chainHead-r.head <= f.maxBlockLag
+	}
+}
+
+// Status returns the state of every endpoint, in the order they were given.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []EndpointStatus: the state of the endpoints
+func (f *FailoverProvider) Status() []EndpointStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	status := make([]EndpointStatus, len(f.endpoints))
+	for i, e := range f.endpoints {
+		status[i] = EndpointStatus{Healthy: e.healthy, Head: e.head, Err: e.err}
+	}
+	return status
+}
+
+// candidates returns the endpoints to try for a call, in order: the healthy
+// ones first, according to the strategy, then the others. For reads of a
+// moving block, endpoints behind the highest block already served are only
+// tried after the others.
+//
+// Parameters:
+// - moving: Whether the call reads the latest or pending block
+// Returns:
+// - []*failoverEndpoint: the endpoints to try
+func (f *FailoverProvider) candidates(moving bool) []*failoverEndpoint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := len(f.endpoints)
+	start := 0
+	if f.strategy == FailoverRoundRobin {
+		start = f.next % n
+		f.next++
+	}
+	var healthy, unhealthy, behind []*failoverEndpoint
+	for i := 0; i < n; i++ {
+		e := f.endpoints[(start+i)%n]
+		switch {
+		case moving && e.head < f.highWater:
+			behind = append(behind, e)
+		case e.healthy:
+			healthy = append(healthy, e)
+		default:
+			unhealthy = append(unhealthy, e)
+		}
+	}
+	candidates := append(healthy, unhealthy...)
+	// an endpoint behind is better than none when the others fail
+	for _, e := range behind {
+		if e.healthy {
+			candidates = append(candidates, e)
+		}
+	}
+	for _, e := range behind {
+		if !e.healthy {
+			candidates = append(candidates, e)
+		}
+	}
+	return candidates
+}
+
+// served records that e answered a call, head being the block number it reported if known.
+//
+// Parameters:
+// - e: The endpoint that answered
+// - moving: Whether the call read the latest or pending block
+// - head: The block number returned by the endpoint, zero if none
+// Returns:
+//
+//	none
+func (f *FailoverProvider) served(e *failoverEndpoint, moving bool, head uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if head > e.head {
+		e.head = head
+	}
+	if moving && e.head > f.highWater {
+		f.highWater = e.head
+	}
+}
+
+// markFailed ejects e until the next health check.
+//
+// Parameters:
+// - e: The endpoint that failed
+// - err: The error of the call
+// Returns:
+//
+//	none
+func (f *FailoverProvider) markFailed(e *failoverEndpoint, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e.healthy = false
+	e.err = err
+}
+
+// failoverCall runs call on the candidate endpoints until one succeeds or
+// fails with an error that another endpoint would return as well.
+//
+// Parameters:
+// - ctx: The context of the call
+// - f: The FailoverProvider
+// - blockID: The block read by the call, nil if none
+// - write: Whether the call submits a transaction
+// - call: The call to run on an endpoint
+// Returns:
+// - T: the result of the call
+// - error: the error of the last endpoint tried, if any
+func failoverCall[T any](ctx context.Context, f *FailoverProvider, blockID *BlockID, write bool, call func(*Provider) (T, error)) (T, error) {
+	return failoverHeadCall(ctx, f, blockID, write, call, nil)
+}
+
+// failoverHeadCall is failoverCall for the calls returning the head of the
+// chain, head extracting the block number from the result.
+func failoverHeadCall[T any](ctx context.Context, f *FailoverProvider, blockID *BlockID, write bool, call func(*Provider) (T, error), head func(T) uint64) (T, error) {
+	moving := blockID != nil && (blockID.Tag == "latest" || blockID.Tag == "pending")
+	var zero T
+	var notFound error
+	err := ErrNoEndpointAvailable
+	for _, e := range f.candidates(moving) {
+		var result T
+		result, err = call(e.provider)
+		if err == nil {
+			var number uint64
+			if head != nil {
+				number = head(result)
+			}
+			f.served(e, moving, number)
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return zero, err
+		}
+		if isTransientCallError(err) {
+			if write && !isNotReceived(rpcErrCause(err)) {
+				return zero, err
+			}
+			f.markFailed(e, err)
+			continue
+		}
+		if write || !isNotFoundError(err) {
+			return zero, err
+		}
+		// the endpoint may not have caught up with the requested item yet
+		notFound = err
+	}
+	if notFound != nil {
+		// the item is unknown, whatever the endpoints failing after
+		return zero, notFound
+	}
+	return zero, err
+}
+
+// isTransientCallError reports whether a Provider method failed because of the endpoint.
+func isTransientCallError(err error) bool {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == InternalError {
+		if cause, ok := rpcErr.Data.(error); ok {
+			return IsRetryable(cause)
+		}
+		return true
+	}
+	return IsRetryable(err)
+}
+
+// isNotFoundError reports whether err says that the requested block or transaction is unknown.
+func isNotFoundError(err error) bool {
+	var rpcErr *RPCError
+	return errors.As(err, &rpcErr) && (rpcErr.Code == ErrBlockNotFound.Code || rpcErr.Code == ErrHashNotFound.Code)
+}
+
+// rpcErrCause returns the transport error wrapped in an InternalError by the Provider methods.
+func rpcErrCause(err error) error {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == InternalError {
+		if cause, ok := rpcErr.Data.(error); ok {
+			return cause
+		}
+	}
+	return err
+}
+
+// blockHead returns the number of a block returned by a block read, zero for
+// the pending block, which has none.
+func blockHead(block interface{}) uint64 {
+	switch b := block.(type) {
+	case *Block:
+		return b.BlockNumber
+	case *BlockTxHashes:
+		return b.BlockNumber
+	case *BlockWithReceipts:
+		return b.BlockNumber
+	}
+	return 0
+}
+
+// movingBlock returns a pointer to blockID for failoverCall.
+func movingBlock(blockID BlockID) *BlockID {
+	return &blockID
+}
+
+// AddInvokeTransaction sends an invoke transaction through the first available endpoint.
+func (f *FailoverProvider) AddInvokeTransaction(ctx context.Context, invokeTxn BroadcastInvokeTxnType) (*AddInvokeTransactionResponse, error) {
+	return failoverCall(ctx, f, nil, true, func(p *Provider) (*AddInvokeTransactionResponse, error) {
+		return p.AddInvokeTransaction(ctx, invokeTxn)
+	})
+}
+
+// AddDeclareTransaction sends a declare transaction through the first available endpoint.
+func (f *FailoverProvider) AddDeclareTransaction(ctx context.Context, declareTransaction BroadcastDeclareTxnType) (*AddDeclareTransactionResponse, error) {
+	return failoverCall(ctx, f, nil, true, func(p *Provider) (*AddDeclareTransactionResponse, error) {
+		return p.AddDeclareTransaction(ctx, declareTransaction)
+	})
+}
+
+// AddDeployAccountTransaction sends a deploy account transaction through the first available endpoint.
+func (f *FailoverProvider) AddDeployAccountTransaction(ctx context.Context, deployAccountTransaction BroadcastAddDeployTxnType) (*AddDeployAccountTransactionResponse, error) {
+	return failoverCall(ctx, f, nil, true, func(p *Provider) (*AddDeployAccountTransactionResponse, error) {
+		return p.AddDeployAccountTransaction(ctx, deployAccountTransaction)
+	})
+}
+
+// BlockHashAndNumber retrieves the hash and number of the latest block, never older than a block already served.
+func (f *FailoverProvider) BlockHashAndNumber(ctx context.Context) (*BlockHashAndNumberOutput, error) {
+	return failoverHeadCall(ctx, f, movingBlock(WithBlockTag("latest")), false, func(p *Provider) (*BlockHashAndNumberOutput, error) {
+		return p.BlockHashAndNumber(ctx)
+	}, func(output *BlockHashAndNumberOutput) uint64 { return output.BlockNumber })
+}
+
+// BlockNumber retrieves the number of the latest block, never lower than a block already served.
+func (f *FailoverProvider) BlockNumber(ctx context.Context) (uint64, error) {
+	return failoverHeadCall(ctx, f, movingBlock(WithBlockTag("latest")), false, func(p *Provider) (uint64, error) {
+		return p.BlockNumber(ctx)
+	}, func(blockNumber uint64) uint64 { return blockNumber })
+}
+
+// BlockTransactionCount retrieves the number of transactions in a block.
+func (f *FailoverProvider) BlockTransactionCount(ctx context.Context, blockID BlockID) (uint64, error) {
+	return failoverCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (uint64, error) {
+		return p.BlockTransactionCount(ctx, blockID)
+	})
+}
+
+// BlockWithTxHashes retrieves a block with its transaction hashes.
+func (f *FailoverProvider) BlockWithTxHashes(ctx context.Context, blockID BlockID) (interface{}, error) {
+	return failoverHeadCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (interface{}, error) {
+		return p.BlockWithTxHashes(ctx, blockID)
+	}, blockHead)
+}
+
+// BlockWithTxs retrieves a block with its transactions.
+func (f *FailoverProvider) BlockWithTxs(ctx context.Context, blockID BlockID) (interface{}, error) {
+	return failoverHeadCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (interface{}, error) {
+		return p.BlockWithTxs(ctx, blockID)
+	}, blockHead)
+}
+
+// BlockWithReceipts retrieves a block with its transactions and receipts.
+func (f *FailoverProvider) BlockWithReceipts(ctx context.Context, blockID BlockID) (interface{}, error) {
+	return failoverHeadCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (interface{}, error) {
+		return p.BlockWithReceipts(ctx, blockID)
+	}, blockHead)
+}
+
+// Call calls a contract function without creating a transaction.
+func (f *FailoverProvider) Call(ctx context.Context, call FunctionCall, block BlockID) ([]*felt.Felt, error) {
+	return failoverCall(ctx, f, movingBlock(block), false, func(p *Provider) ([]*felt.Felt, error) {
+		return p.Call(ctx, call, block)
+	})
+}
+
+// ChainID retrieves the chain id of the endpoints.
+func (f *FailoverProvider) ChainID(ctx context.Context) (string, error) {
+	return failoverCall(ctx, f, nil, false, func(p *Provider) (string, error) {
+		return p.ChainID(ctx)
+	})
+}
+
+// Class retrieves a contract class by its hash.
+func (f *FailoverProvider) Class(ctx context.Context, blockID BlockID, classHash *felt.Felt) (ClassOutput, error) {
+	return failoverCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (ClassOutput, error) {
+		return p.Class(ctx, blockID, classHash)
+	})
+}
+
+// ClassAt retrieves the contract class of a contract.
+func (f *FailoverProvider) ClassAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (ClassOutput, error) {
+	return failoverCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (ClassOutput, error) {
+		return p.ClassAt(ctx, blockID, contractAddress)
+	})
+}
+
+// ClassHashAt retrieves the class hash of a contract.
+func (f *FailoverProvider) ClassHashAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	return failoverCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (*felt.Felt, error) {
+		return p.ClassHashAt(ctx, blockID, contractAddress)
+	})
+}
+
+// CompiledCasm retrieves the CASM compiled from a Sierra class.
+func (f *FailoverProvider) CompiledCasm(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error) {
+	return failoverCall(ctx, f, nil, false, func(p *Provider) (*contracts.CasmClass, error) {
+		return p.CompiledCasm(ctx, classHash)
+	})
+}
+
+// EstimateFee estimates the fee of transactions.
+func (f *FailoverProvider) EstimateFee(ctx context.Context, requests []BroadcastTxn, simulationFlags []SimulationFlag, blockID BlockID) ([]FeeEstimate, error) {
+	return failoverCall(ctx, f, movingBlock(blockID), false, func(p *Provider) ([]FeeEstimate, error) {
+		return p.EstimateFee(ctx, requests, simulationFlags, blockID)
+	})
+}
+
+// EstimateMessageFee estimates the L2 fee of a message sent from L1.
+func (f *FailoverProvider) EstimateMessageFee(ctx context.Context, msg MsgFromL1, blockID BlockID) (*FeeEstimate, error) {
+	return failoverCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (*FeeEstimate, error) {
+		return p.EstimateMessageFee(ctx, msg, blockID)
+	})
+}
+
+// Events retrieves the events matching the filter.
+func (f *FailoverProvider) Events(ctx context.Context, input EventsInput) (*EventChunk, error) {
+	return failoverCall(ctx, f, movingBlock(input.ToBlock), false, func(p *Provider) (*EventChunk, error) {
+		return p.Events(ctx, input)
+	})
+}
+
+// GetTransactionStatus retrieves the status of a transaction.
+func (f *FailoverProvider) GetTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*TxnStatusResp, error) {
+	return failoverCall(ctx, f, nil, false, func(p *Provider) (*TxnStatusResp, error) {
+		return p.GetTransactionStatus(ctx, transactionHash)
+	})
+}
+
+// MessagesStatus retrieves the status of the messages sent by an L1 transaction.
+func (f *FailoverProvider) MessagesStatus(ctx context.Context, transactionHash NumAsHex) ([]MessageStatusResp, error) {
+	return failoverCall(ctx, f, nil, false, func(p *Provider) ([]MessageStatusResp, error) {
+		return p.MessagesStatus(ctx, transactionHash)
+	})
+}
+
+// Nonce retrieves the nonce of a contract.
+func (f *FailoverProvider) Nonce(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	return failoverCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (*felt.Felt, error) {
+		return p.Nonce(ctx, blockID, contractAddress)
+	})
+}
+
+// SimulateTransactions simulates transactions on a block.
+func (f *FailoverProvider) SimulateTransactions(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag) ([]SimulatedTransaction, error) {
+	return failoverCall(ctx, f, movingBlock(blockID), false, func(p *Provider) ([]SimulatedTransaction, error) {
+		return p.SimulateTransactions(ctx, blockID, txns, simulationFlags)
+	})
+}
+
+// StateUpdate retrieves the state update of a block.
+func (f *FailoverProvider) StateUpdate(ctx context.Context, blockID BlockID) (*StateUpdateOutput, error) {
+	return failoverCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (*StateUpdateOutput, error) {
+		return p.StateUpdate(ctx, blockID)
+	})
+}
+
+// StorageAt retrieves a storage value of a contract.
+func (f *FailoverProvider) StorageAt(ctx context.Context, contractAddress *felt.Felt, key string, blockID BlockID) (string, error) {
+	return failoverCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (string, error) {
+		return p.StorageAt(ctx, contractAddress, key, blockID)
+	})
+}
+
+// StorageProof retrieves the proofs of classes, contracts and storage slots of a block.
+func (f *FailoverProvider) StorageProof(ctx context.Context, input StorageProofInput) (*StorageProof, error) {
+	return failoverCall(ctx, f, movingBlock(input.BlockID), false, func(p *Provider) (*StorageProof, error) {
+		return p.StorageProof(ctx, input)
+	})
+}
+
+// SpecVersion retrieves the spec version of an endpoint.
+func (f *FailoverProvider) SpecVersion(ctx context.Context) (string, error) {
+	return failoverCall(ctx, f, nil, false, func(p *Provider) (string, error) {
+		return p.SpecVersion(ctx)
+	})
+}
+
+// Syncing retrieves the synchronisation status of an endpoint.
+func (f *FailoverProvider) Syncing(ctx context.Context) (*SyncStatus, error) {
+	return failoverCall(ctx, f, nil, false, func(p *Provider) (*SyncStatus, error) {
+		return p.Syncing(ctx)
+	})
+}
+
+// TraceBlockTransactions retrieves the traces of the transactions of a block.
+func (f *FailoverProvider) TraceBlockTransactions(ctx context.Context, blockID BlockID) ([]Trace, error) {
+	return failoverCall(ctx, f, movingBlock(blockID), false, func(p *Provider) ([]Trace, error) {
+		return p.TraceBlockTransactions(ctx, blockID)
+	})
+}
+
+// TransactionByBlockIdAndIndex retrieves a transaction by its block and index.
+func (f *FailoverProvider) TransactionByBlockIdAndIndex(ctx context.Context, blockID BlockID, index uint64) (Transaction, error) {
+	return failoverCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (Transaction, error) {
+		return p.TransactionByBlockIdAndIndex(ctx, blockID, index)
+	})
+}
+
+// TransactionByHash retrieves a transaction by its hash.
+func (f *FailoverProvider) TransactionByHash(ctx context.Context, hash *felt.Felt) (Transaction, error) {
+	return failoverCall(ctx, f, nil, false, func(p *Provider) (Transaction, error) {
+		return p.TransactionByHash(ctx, hash)
+	})
+}
+
+// TransactionReceipt retrieves the receipt of a transaction.
+func (f *FailoverProvider) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*TransactionReceiptWithBlockInfo, error) {
+	return failoverCall(ctx, f, nil, false, func(p *Provider) (*TransactionReceiptWithBlockInfo, error) {
+		return p.TransactionReceipt(ctx, transactionHash)
+	})
+}
+
+// TraceTransaction retrieves the trace of a transaction.
+func (f *FailoverProvider) TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (TxnTrace, error) {
+	return failoverCall(ctx, f, nil, false, func(p *Provider) (TxnTrace, error) {
+		return p.TraceTransaction(ctx, transactionHash)
+	})
+}
+
+// TypedBlockWithReceipts retrieves a block with its transactions and receipts.
+func (f *FailoverProvider) TypedBlockWithReceipts(ctx context.Context, blockID BlockID) (*BlockWithReceiptsResult, error) {
+	return failoverHeadCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (*BlockWithReceiptsResult, error) {
+		return p.TypedBlockWithReceipts(ctx, blockID)
+	}, func(block *BlockWithReceiptsResult) uint64 { return blockHead(block.Value()) })
+}
+
+// TypedBlockWithTxHashes retrieves a block with its transaction hashes.
+func (f *FailoverProvider) TypedBlockWithTxHashes(ctx context.Context, blockID BlockID) (*BlockTxHashesResult, error) {
+	return failoverHeadCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (*BlockTxHashesResult, error) {
+		return p.TypedBlockWithTxHashes(ctx, blockID)
+	}, func(block *BlockTxHashesResult) uint64 { return blockHead(block.Value()) })
+}
+
+// TypedBlockWithTxs retrieves a block with its transactions.
+func (f *FailoverProvider) TypedBlockWithTxs(ctx context.Context, blockID BlockID) (*BlockResult, error) {
+	return failoverHeadCall(ctx, f, movingBlock(blockID), false, func(p *Provider) (*BlockResult, error) {
+		return p.TypedBlockWithTxs(ctx, blockID)
+	}, func(block *BlockResult) uint64 { return blockHead(block.Value()) })
+}
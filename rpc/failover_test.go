@@ -0,0 +1,132 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// downClient is a callCloser whose calls all fail with err, counting them.
+type downClient struct {
+	err   error
+	calls int
+}
+
+// CallContext fails with the configured error.
+func (d *downClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	d.calls++
+	return d.err
+}
+
+// Close does nothing.
+func (d *downClient) Close() {}
+
+// nodeAt returns a provider of a synced node whose head is the given block number.
+func nodeAt(head string) *Provider {
+	return &Provider{c: rawAnswers{"starknet_syncing": `false`, "starknet_blockNumber": head}}
+}
+
+// TestFailoverPriority tests that calls fail over to the next endpoint and that failed endpoints are ejected.
+func TestFailoverPriority(t *testing.T) {
+	down := &downClient{err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+	f, err := NewFailoverProvider([]*Provider{{c: down}, nodeAt("10")}, FailoverConfig{Strategy: FailoverPriority})
+	require.NoError(t, err)
+
+	blockNumber, err := f.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), blockNumber)
+	require.Equal(t, 1, down.calls)
+	require.False(t, f.Status()[0].Healthy)
+
+	_, err = f.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, down.calls, "the ejected endpoint is tried last")
+
+	// Starknet errors are returned as is
+	_, err = f.BlockTransactionCount(context.Background(), WithBlockTag("latest"))
+	require.Equal(t, ErrBlockNotFound, err)
+}
+
+// TestFailoverHealthCheck tests that lagging and failing endpoints are ejected.
+func TestFailoverHealthCheck(t *testing.T) {
+	f, err := NewFailoverProvider([]*Provider{
+		nodeAt("100"),
+		nodeAt("95"),
+		nodeAt("99"),
+		{c: &downClient{err: syscall.ECONNRESET}},
+	}, FailoverConfig{MaxBlockLag: 2})
+	require.NoError(t, err)
+
+	f.CheckHealth(context.Background())
+	status := f.Status()
+	require.Equal(t, EndpointStatus{Healthy: true, Head: 100}, status[0])
+	require.Equal(t, EndpointStatus{Healthy: false, Head: 95}, status[1])
+	require.Equal(t, EndpointStatus{Healthy: true, Head: 99}, status[2])
+	require.False(t, status[3].Healthy)
+	require.Error(t, status[3].Err)
+}
+
+// TestFailoverConsistentLatest tests that round robin never serves an older head than one already served.
+func TestFailoverConsistentLatest(t *testing.T) {
+	f, err := NewFailoverProvider([]*Provider{nodeAt("100"), nodeAt("90")}, FailoverConfig{Strategy: FailoverRoundRobin})
+	require.NoError(t, err)
+	f.CheckHealth(context.Background())
+
+	for i := 0; i < 4; i++ {
+		blockNumber, err := f.BlockNumber(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), blockNumber)
+	}
+}
+
+// TestFailoverBehindHighWater tests that the reads of the latest block fail
+// over to the endpoints not known to have reached the highest block served,
+// and that the block reads record the head of the endpoints.
+func TestFailoverBehindHighWater(t *testing.T) {
+	first := nodeAt("10")
+	second := &Provider{c: rawAnswers{
+		"starknet_getNonce":             `"0x2"`,
+		"starknet_getBlockWithTxHashes": `{"status": "ACCEPTED_ON_L2", "block_hash": "0x1", "parent_hash": "0x0", "block_number": 12, "new_root": "0x0", "timestamp": 1, "sequencer_address": "0x0", "transactions": []}`,
+	}}
+	f, err := NewFailoverProvider([]*Provider{first, second}, FailoverConfig{Strategy: FailoverRoundRobin})
+	require.NoError(t, err)
+
+	blockNumber, err := f.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), blockNumber)
+
+	first.c = &downClient{err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+	nonce, err := f.Nonce(context.Background(), WithBlockTag("latest"), utils.TestHexToFelt(t, "0x1"))
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0x2"), nonce)
+	require.False(t, f.Status()[0].Healthy)
+
+	_, err = f.BlockWithTxHashes(context.Background(), WithBlockTag("latest"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), f.Status()[1].Head)
+}
+
+// TestFailoverWrite tests that a transaction is not sent again once the node may have received it.
+func TestFailoverWrite(t *testing.T) {
+	reset := &downClient{err: &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}}
+	other := &downClient{err: syscall.ECONNREFUSED}
+	f, err := NewFailoverProvider([]*Provider{{c: reset}, {c: other}}, FailoverConfig{})
+	require.NoError(t, err)
+
+	_, err = f.AddInvokeTransaction(context.Background(), BroadcastInvokev1Txn{})
+	require.Error(t, err)
+	require.Equal(t, 1, reset.calls)
+	require.Equal(t, 0, other.calls)
+
+	refused := &downClient{err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+	f, err = NewFailoverProvider([]*Provider{{c: refused}, {c: other}}, FailoverConfig{})
+	require.NoError(t, err)
+	_, err = f.AddInvokeTransaction(context.Background(), BroadcastInvokev1Txn{})
+	require.Error(t, err)
+	require.Equal(t, 1, refused.calls)
+	require.Equal(t, 1, other.calls)
+}
@@ -0,0 +1,307 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// ErrReorgTooDeep is returned by a BlockFollower when a reorganization goes
+// deeper than the blocks it remembers.
+var ErrReorgTooDeep = errors.New("chain reorganization deeper than the follower history")
+
+const (
+	defaultFollowerPollInterval  = 2 * time.Second
+	defaultFollowerMaxReorgDepth = 64
+)
+
+// FollowerEventType is the kind of a FollowerEvent.
+type FollowerEventType int
+
+const (
+	// BlockApplied reports a new block on the followed chain.
+	BlockApplied FollowerEventType = iota
+	// BlockReverted reports a block removed from the followed chain by a
+	// reorganization. Blocks are reverted from the highest down.
+	BlockReverted
+	// PendingBlockUpdated reports a new version of the pending block.
+	PendingBlockUpdated
+)
+
+// String returns the name of the event type.
+func (t FollowerEventType) String() string {
+	switch t {
+	case BlockApplied:
+		return "applied"
+	case BlockReverted:
+		return "reverted"
+	case PendingBlockUpdated:
+		return "pending"
+	}
+	return "unknown"
+}
+
+// FollowerEvent is a change of the chain followed by a BlockFollower.
+type FollowerEvent struct {
+	Type FollowerEventType
+	// BlockNumber the number of the block, the number the pending block will
+	// have for PendingBlockUpdated.
+	BlockNumber uint64
+	// BlockHash the hash of the block, nil for PendingBlockUpdated.
+	BlockHash *felt.Felt
+	// Block the block, nil for BlockReverted.
+	Block *BlockTxHashesResult
+}
+
+// FollowerCheckpoint persists the blocks a BlockFollower has applied, so that
+// it resumes where it stopped and still detects the reorganizations of the
+// blocks it applied before a restart.
+type FollowerCheckpoint interface {
+	// Load returns the blocks saved by the last Save, lowest first, or none
+	// to start from FollowerConfig.Start.
+	Load(ctx context.Context) ([]BlockHashAndNumberOutput, error)
+	// Save persists the most recent blocks of the followed chain, lowest first.
+	Save(ctx context.Context, blocks []BlockHashAndNumberOutput) error
+}
+
+// FollowerConfig configures a BlockFollower.
+type FollowerConfig struct {
+	// Start the number of the first block to apply. If nil, the follower
+	// starts at the current head minus Confirmations. Ignored when the
+	// checkpoint has saved blocks.
+	Start *uint64
+	// Confirmations the number of blocks a block must have on top of it
+	// before it is applied.
+	Confirmations uint64
+	// IncludePending reports the pending block once the follower has caught
+	// up with the head. Only used when Confirmations is zero.
+	IncludePending bool
+	// PollInterval the delay between two polls of Run, 2 seconds if zero.
+	PollInterval time.Duration
+	// MaxReorgDepth the number of blocks remembered to detect the
+	// reorganizations, 64 if zero.
+	MaxReorgDepth int
+	// Checkpoint persists the applied blocks, if not nil.
+	Checkpoint FollowerCheckpoint
+}
+
+// BlockFollower follows the head of the chain and reports the blocks applied
+// and reverted in order. A reorganization is detected when the parent hash of
+// a new block doesn't match the hash of the block applied at the previous
+// height; the blocks are then reverted until the new chain connects.
+//
+// The events are delivered at least once: if the callback or the checkpoint
+// fail, the event is delivered again by the next poll.
+type BlockFollower struct {
+	provider RpcProvider
+	config   FollowerConfig
+
+	loaded bool
+	// history the most recent applied blocks, lowest first
+	history []BlockHashAndNumberOutput
+	// truncated is true once blocks have been dropped from the history
+	truncated bool
+	next      uint64
+	started   bool
+
+	pendingParent *felt.Felt
+	pendingTxs    int
+}
+
+// NewBlockFollower creates a BlockFollower.
+//
+// Parameters:
+// - provider: The provider to follow the chain with
+// - config: The follower configuration
+// Returns:
+// - *BlockFollower: a new BlockFollower
+func NewBlockFollower(provider RpcProvider, config FollowerConfig) *BlockFollower {
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultFollowerPollInterval
+	}
+	if config.MaxReorgDepth <= 0 {
+		config.MaxReorgDepth = defaultFollowerMaxReorgDepth
+	}
+	return &BlockFollower{provider: provider, config: config}
+}
+
+// Run polls the chain every PollInterval and calls fn with every event, until
+// ctx is done or an error occurs.
+//
+// Parameters:
+// - ctx: The context.Context of the requests
+// - fn: The function called with each event
+// Returns:
+// - error: the error of fn, of the checkpoint or of a request, or the error of ctx
+func (f *BlockFollower) Run(ctx context.Context, fn func(event FollowerEvent) error) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if err := f.Poll(ctx, fn); err != nil {
+			return err
+		}
+		timer.Reset(f.config.PollInterval)
+	}
+}
+
+// Poll catches up with the head of the chain once, calling fn with every event.
+//
+// Parameters:
+// - ctx: The context.Context of the requests
+// - fn: The function called with each event
+// Returns:
+// - error: the error of fn, of the checkpoint or of a request, if any
+func (f *BlockFollower) Poll(ctx context.Context, fn func(event FollowerEvent) error) error {
+	if !f.loaded {
+		if err := f.load(ctx); err != nil {
+			return err
+		}
+	}
+
+	head, err := f.provider.BlockHashAndNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if head.BlockNumber < f.config.Confirmations {
+		return nil
+	}
+	target := head.BlockNumber - f.config.Confirmations
+	if !f.started {
+		f.started = true
+		f.next = target
+		if f.config.Start != nil {
+			f.next = *f.config.Start
+		}
+	}
+
+	for f.next <= target {
+		block, err := f.provider.TypedBlockWithTxHashes(ctx, WithBlockNumber(f.next))
+		if err != nil {
+			if isNotFoundError(err) {
+				// the head moved back, wait for the chain to grow again
+				break
+			}
+			return err
+		}
+		if block.Pending() {
+			break
+		}
+		if last := len(f.history) - 1; last >= 0 && !f.history[last].BlockHash.Equal(block.ParentHash()) {
+			if err := f.revert(ctx, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := f.apply(ctx, fn, block); err != nil {
+			return err
+		}
+	}
+
+	if f.config.IncludePending && f.config.Confirmations == 0 && f.next > head.BlockNumber {
+		return f.pending(ctx, fn)
+	}
+	return nil
+}
+
+// load restores the history saved by the checkpoint.
+func (f *BlockFollower) load(ctx context.Context) error {
+	if f.config.Checkpoint != nil {
+		blocks, err := f.config.Checkpoint.Load(ctx)
+		if err != nil {
+			return err
+		}
+		if len(blocks) > 0 {
+			f.history = blocks
+			f.truncated = len(blocks) >= f.config.MaxReorgDepth
+			f.next = blocks[len(blocks)-1].BlockNumber + 1
+			f.started = true
+		}
+	}
+	f.loaded = true
+	return nil
+}
+
+// apply reports a new block and adds it to the history. The history is
+// only updated once saved, so that a failed save delivers the block again.
+func (f *BlockFollower) apply(ctx context.Context, fn func(event FollowerEvent) error, block *BlockTxHashesResult) error {
+	hash := block.Block.BlockHash
+	if err := fn(FollowerEvent{Type: BlockApplied, BlockNumber: f.next, BlockHash: hash, Block: block}); err != nil {
+		return err
+	}
+	// the full slice expression keeps the append from writing to f.history
+	history := append(f.history[:len(f.history):len(f.history)], BlockHashAndNumberOutput{BlockNumber: f.next, BlockHash: hash})
+	truncated := f.truncated
+	if len(history) > f.config.MaxReorgDepth {
+		history = history[len(history)-f.config.MaxReorgDepth:]
+		truncated = true
+	}
+	if err := f.save(ctx, history); err != nil {
+		return err
+	}
+	f.history, f.truncated = history, truncated
+	f.next++
+	f.pendingParent = nil
+	return nil
+}
+
+// revert reports the highest block of the history as reverted and removes
+// it, once the shorter history is saved.
+func (f *BlockFollower) revert(ctx context.Context, fn func(event FollowerEvent) error) error {
+	last := f.history[len(f.history)-1]
+	if len(f.history) == 1 && f.truncated {
+		return ErrReorgTooDeep
+	}
+	if err := fn(FollowerEvent{Type: BlockReverted, BlockNumber: last.BlockNumber, BlockHash: last.BlockHash}); err != nil {
+		return err
+	}
+	history := f.history[:len(f.history)-1]
+	if err := f.save(ctx, history); err != nil {
+		return err
+	}
+	f.history = history
+	f.next = last.BlockNumber
+	f.pendingParent = nil
+	return nil
+}
+
+// pending reports the pending block if it changed since the last report.
+func (f *BlockFollower) pending(ctx context.Context, fn func(event FollowerEvent) error) error {
+	block, err := f.provider.TypedBlockWithTxHashes(ctx, WithBlockTag("pending"))
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+	if !block.Pending() {
+		return nil
+	}
+	// a pending block built on another chain will be reported once its parent is applied
+	if last := len(f.history) - 1; last >= 0 && !f.history[last].BlockHash.Equal(block.ParentHash()) {
+		return nil
+	}
+	txs := len(block.Transactions())
+	if f.pendingParent != nil && f.pendingParent.Equal(block.ParentHash()) && f.pendingTxs == txs {
+		return nil
+	}
+	if err := fn(FollowerEvent{Type: PendingBlockUpdated, BlockNumber: f.next, Block: block}); err != nil {
+		return err
+	}
+	f.pendingParent, f.pendingTxs = block.ParentHash(), txs
+	return nil
+}
+
+// save persists the history to the checkpoint, if any.
+func (f *BlockFollower) save(ctx context.Context, history []BlockHashAndNumberOutput) error {
+	if f.config.Checkpoint == nil {
+		return nil
+	}
+	return f.config.Checkpoint.Save(ctx, append([]BlockHashAndNumberOutput(nil), history...))
+}
@@ -0,0 +1,182 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/stretchr/testify/require"
+)
+
+// chainNode is a callCloser serving a chain of blocks whose hashes are
+// derived from their number and fork, and an optional pending block.
+type chainNode struct {
+	// forks the fork of each block, a block hash is fork*1000 + number
+	forks      []uint64
+	pendingTxs int
+}
+
+// hash returns the hash of the block at height n.
+func (c *chainNode) hash(n uint64) *felt.Felt {
+	return new(felt.Felt).SetUint64(c.forks[n]*1000 + n)
+}
+
+// CallContext answers starknet_blockHashAndNumber and starknet_getBlockWithTxHashes.
+func (c *chainNode) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	var answer interface{}
+	head := uint64(len(c.forks) - 1)
+	switch method {
+	case "starknet_blockHashAndNumber":
+		answer = BlockHashAndNumberOutput{BlockNumber: head, BlockHash: c.hash(head)}
+	case "starknet_getBlockWithTxHashes":
+		blockID := args[0].(BlockID)
+		if blockID.Tag == "pending" {
+			txs := make([]*felt.Felt, c.pendingTxs)
+			for i := range txs {
+				txs[i] = new(felt.Felt).SetUint64(uint64(i))
+			}
+			answer = PendingBlockTxHashes{PendingBlockHeader: PendingBlockHeader{ParentHash: c.hash(head)}, Transactions: txs}
+			break
+		}
+		n := *blockID.Number
+		if n > head {
+			return ErrBlockNotFound
+		}
+		parent := new(felt.Felt)
+		if n > 0 {
+			parent = c.hash(n - 1)
+		}
+		answer = BlockTxHashes{BlockHeader: BlockHeader{BlockHash: c.hash(n), ParentHash: parent, BlockNumber: n}, Status: BlockStatus_AcceptedOnL2, Transactions: []*felt.Felt{}}
+	default:
+		return errNotFound
+	}
+	raw, err := json.Marshal(answer)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// Close does nothing.
+func (c *chainNode) Close() {}
+
+// memoryCheckpoint keeps the saved blocks in memory.
+type memoryCheckpoint struct {
+	blocks []BlockHashAndNumberOutput
+	// err is returned by Save instead of saving, if not nil
+	err error
+}
+
+// Load returns the saved blocks.
+func (m *memoryCheckpoint) Load(ctx context.Context) ([]BlockHashAndNumberOutput, error) {
+	return m.blocks, nil
+}
+
+// Save saves the blocks.
+func (m *memoryCheckpoint) Save(ctx context.Context, blocks []BlockHashAndNumberOutput) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.blocks = blocks
+	return nil
+}
+
+// TestBlockFollower tests that the follower applies the new blocks and reverts the reorganized ones.
+func TestBlockFollower(t *testing.T) {
+	node := &chainNode{forks: make([]uint64, 6)}
+	checkpoint := &memoryCheckpoint{}
+	start := uint64(2)
+	follower := NewBlockFollower(&Provider{c: node}, FollowerConfig{Start: &start, Confirmations: 1, Checkpoint: checkpoint})
+
+	var events []string
+	record := func(event FollowerEvent) error {
+		events = append(events, event.Type.String()+" "+event.BlockHash.String())
+		return nil
+	}
+
+	require.NoError(t, follower.Poll(context.Background(), record))
+	require.Equal(t, []string{"applied 0x2", "applied 0x3", "applied 0x4"}, events)
+
+	// blocks 3 to 5 are replaced and the chain grows to 7
+	events = nil
+	node.forks = []uint64{0, 0, 0, 1, 1, 1, 1, 1}
+	require.NoError(t, follower.Poll(context.Background(), record))
+	require.Equal(t, []string{"reverted 0x4", "reverted 0x3", "applied 0x3eb", "applied 0x3ec", "applied 0x3ed", "applied 0x3ee"}, events)
+	require.Len(t, checkpoint.blocks, 5)
+	require.Equal(t, uint64(6), checkpoint.blocks[4].BlockNumber)
+
+	// a new follower resumes from the checkpoint
+	events = nil
+	node.forks = append(node.forks, 1)
+	follower = NewBlockFollower(&Provider{c: node}, FollowerConfig{Confirmations: 1, Checkpoint: checkpoint})
+	require.NoError(t, follower.Poll(context.Background(), record))
+	require.Equal(t, []string{"applied 0x3ef"}, events)
+}
+
+// TestBlockFollowerPending tests that the pending block is reported when it changes.
+func TestBlockFollowerPending(t *testing.T) {
+	node := &chainNode{forks: make([]uint64, 3), pendingTxs: 1}
+	follower := NewBlockFollower(&Provider{c: node}, FollowerConfig{IncludePending: true})
+
+	var events []FollowerEvent
+	record := func(event FollowerEvent) error {
+		events = append(events, event)
+		return nil
+	}
+	require.NoError(t, follower.Poll(context.Background(), record))
+	require.NoError(t, follower.Poll(context.Background(), record))
+	require.Len(t, events, 2)
+	require.Equal(t, BlockApplied, events[0].Type)
+	require.Equal(t, uint64(2), events[0].BlockNumber)
+	require.Equal(t, PendingBlockUpdated, events[1].Type)
+	require.Equal(t, uint64(3), events[1].BlockNumber)
+	require.True(t, events[1].Block.Pending())
+
+	node.pendingTxs = 2
+	require.NoError(t, follower.Poll(context.Background(), record))
+	require.Len(t, events, 3)
+	require.Len(t, events[2].Block.Transactions(), 2)
+}
+
+// TestBlockFollowerErrors tests that a failed event or save is delivered again and that deep reorganizations are reported.
+func TestBlockFollowerErrors(t *testing.T) {
+	node := &chainNode{forks: make([]uint64, 5)}
+	start := uint64(0)
+	follower := NewBlockFollower(&Provider{c: node}, FollowerConfig{Start: &start, MaxReorgDepth: 2})
+
+	fail := errors.New("fail")
+	var numbers []uint64
+	err := follower.Poll(context.Background(), func(event FollowerEvent) error {
+		if event.BlockNumber == 3 && len(numbers) == 3 {
+			return fail
+		}
+		numbers = append(numbers, event.BlockNumber)
+		return nil
+	})
+	require.Equal(t, fail, err)
+	require.NoError(t, follower.Poll(context.Background(), func(event FollowerEvent) error {
+		numbers = append(numbers, event.BlockNumber)
+		return nil
+	}))
+	require.Equal(t, []uint64{0, 1, 2, 3, 4}, numbers)
+
+	node.forks = []uint64{0, 0, 1, 1, 1, 1}
+	err = follower.Poll(context.Background(), func(event FollowerEvent) error { return nil })
+	require.Equal(t, ErrReorgTooDeep, err)
+
+	// a block whose save failed is delivered again
+	checkpoint := &memoryCheckpoint{err: fail}
+	follower = NewBlockFollower(&Provider{c: &chainNode{forks: make([]uint64, 3)}}, FollowerConfig{Start: &start, Checkpoint: checkpoint})
+	numbers = nil
+	record := func(event FollowerEvent) error {
+		numbers = append(numbers, event.BlockNumber)
+		return nil
+	}
+	require.Equal(t, fail, follower.Poll(context.Background(), record))
+	checkpoint.err = nil
+	require.NoError(t, follower.Poll(context.Background(), record))
+	require.Equal(t, []uint64{0, 0, 1, 2}, numbers)
+	require.Len(t, checkpoint.blocks, 3)
+}
@@ -0,0 +1,345 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// BatchMethod is the method name middlewares see for a batch sent with
+// Batch.Send. The single argument of such a call is the []ethrpc.BatchElem
+// of the batch and the result is nil.
+const BatchMethod = "batch"
+
+// DefaultRequestIDHeader is the HTTP header used by RequestIDMiddleware when
+// no header name is given.
+const DefaultRequestIDHeader = "X-Request-Id"
+
+// CallFunc performs a single JSON-RPC call. It has the signature of the
+// CallContext method of the underlying client.
+type CallFunc func(ctx context.Context, result interface{}, method string, args ...interface{}) error
+
+// Middleware wraps a CallFunc to observe or alter the calls made by a
+// Provider. A middleware must call next to perform the call.
+type Middleware func(next CallFunc) CallFunc
+
+// Chain composes middlewares into a single Middleware. The first middleware
+// is the outermost one: it sees the call first and the result last.
+//
+// Parameters:
+// - middlewares: The middlewares to compose
+// Returns:
+// - Middleware: the composed middleware
+func Chain(middlewares ...Middleware) Middleware {
+	return func(next CallFunc) CallFunc {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+// WithMiddleware is a NewProvider option that routes every call of the
+// Provider, including batches, through the given middlewares. The first
+// middleware is the outermost one. The option can be given more than once,
+// the middlewares are appended in order.
+//
+// Parameters:
+// - middlewares: The middlewares to install
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithMiddleware(middlewares ...Middleware) ethrpc.ClientOption {
+	return newProviderOption(func(cfg *providerConfig) {
+		cfg.middlewares = append(cfg.middlewares, middlewares...)
+	})
+}
+
+// middlewareClient is a callCloser sending its calls through a middleware chain.
+type middlewareClient struct {
+	c    callCloser
+	call CallFunc
+}
+
+// newMiddlewareClient wraps c with the given middlewares.
+//
+// Parameters:
+// - c: The client performing the calls
+// - middlewares: The middlewares wrapping the calls
+// Returns:
+// - *middlewareClient: the wrapped client
+func newMiddlewareClient(c callCloser, middlewares ...Middleware) *middlewareClient {
+	m := &middlewareClient{c: c}
+	m.call = Chain(middlewares...)(m.send)
+	return m
+}
+
+// CallContext performs the call through the middleware chain.
+func (m *middlewareClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return m.call(ctx, result, method, args...)
+}
+
+// BatchCallContext sends the batch through the middleware chain as a single
+// call to BatchMethod.
+func (m *middlewareClient) BatchCallContext(ctx context.Context, b []ethrpc.BatchElem) error {
+	return m.call(ctx, nil, BatchMethod, b)
+}
+
+// Close closes the underlying client.
+func (m *middlewareClient) Close() {
+	m.c.Close()
+}
+
+// send is the innermost CallFunc of the chain, it performs the call with the underlying client.
+func (m *middlewareClient) send(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method == BatchMethod && len(args) == 1 {
+		if b, ok := args[0].([]ethrpc.BatchElem); ok {
+			if bc, ok := m.c.(batchCallCloser); ok {
+				return bc.BatchCallContext(ctx, b)
+			}
+			// the client can't batch, fall back to one request per call
+			for i := range b {
+				b[i].Error = m.c.CallContext(ctx, b[i].Result, b[i].Method, b[i].Args...)
+			}
+			return nil
+		}
+	}
+	return m.c.CallContext(ctx, result, method, args...)
+}
+
+// LoggingMiddleware logs every call with its method, duration, request id
+// and error. Successful calls are logged at the debug level and failed calls
+// at the warn level.
+//
+// Parameters:
+// - logger: The logger to write to, slog.Default() if nil
+// Returns:
+// - Middleware: the logging middleware
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			start := time.Now()
+			err := next(ctx, result, method, args...)
+
+			attrs := []slog.Attr{
+				slog.String("method", method),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if id, ok := RequestIDFromContext(ctx); ok {
+				attrs = append(attrs, slog.String("request_id", id))
+			}
+			level := slog.LevelDebug
+			if err != nil {
+				level = slog.LevelWarn
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+			logger.LogAttrs(ctx, level, "rpc call", attrs...)
+			return err
+		}
+	}
+}
+
+// MetricsRecorder receives the outcome of every call made through
+// MetricsMiddleware. Implementations must be safe for concurrent use; they
+// are typically adapters to a metrics library.
+type MetricsRecorder interface {
+	ObserveCall(method string, latency time.Duration, err error)
+}
+
+// MetricsMiddleware reports the latency and the error of every call to recorder.
+//
+// Parameters:
+// - recorder: The recorder receiving the measurements
+// Returns:
+// - Middleware: the metrics middleware
+func MetricsMiddleware(recorder MetricsRecorder) Middleware {
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			start := time.Now()
+			err := next(ctx, result, method, args...)
+			recorder.ObserveCall(method, time.Since(start), err)
+			return err
+		}
+	}
+}
+
+// MethodStats are the statistics collected by CallMetrics for a method.
+type MethodStats struct {
+	// Calls the number of calls made
+	Calls uint64
+	// Errors the number of calls that failed
+	Errors uint64
+	// TotalLatency the sum of the latencies of the calls
+	TotalLatency time.Duration
+	// MaxLatency the latency of the slowest call
+	MaxLatency time.Duration
+}
+
+// AverageLatency returns the mean latency of the calls, zero if no call was made.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - time.Duration: the mean latency
+func (s MethodStats) AverageLatency() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Calls)
+}
+
+// CallMetrics is an in-memory MetricsRecorder keeping per-method counters.
+// The zero value is ready to use.
+type CallMetrics struct {
+	mu    sync.Mutex
+	stats map[string]MethodStats
+}
+
+// ObserveCall records a call of method.
+//
+// Parameters:
+// - method: The method called
+// - latency: The duration of the call
+// - err: The error of the call, if any
+// Returns:
+//
+//	none
+func (m *CallMetrics) ObserveCall(method string, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stats == nil {
+		m.stats = map[string]MethodStats{}
+	}
+	s := m.stats[method]
+	s.Calls++
+	if err != nil {
+		s.Errors++
+	}
+	s.TotalLatency += latency
+	if latency > s.MaxLatency {
+		s.MaxLatency = latency
+	}
+	m.stats[method] = s
+}
+
+// Stats returns a snapshot of the statistics of every method called so far.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - map[string]MethodStats: the statistics keyed by method
+func (m *CallMetrics) Stats() map[string]MethodStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := make(map[string]MethodStats, len(m.stats))
+	for method, s := range m.stats {
+		stats[method] = s
+	}
+	return stats
+}
+
+// Reset clears the statistics.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (m *CallMetrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats = nil
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the given request id. The id
+// is sent by RequestIDMiddleware and logged by LoggingMiddleware.
+//
+// Parameters:
+// - ctx: The parent context
+// - id: The request id
+// Returns:
+// - context.Context: the context carrying the id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request id carried by ctx.
+//
+// Parameters:
+// - ctx: The context
+// Returns:
+// - string: the request id
+// - bool: whether ctx carries a request id
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// RequestIDMiddleware sends the request id set with WithRequestID as an HTTP
+// header, so that calls can be correlated with the logs of the node or of a
+// proxy. Calls without a request id are left untouched.
+//
+// Parameters:
+// - header: The name of the header, DefaultRequestIDHeader if empty
+// Returns:
+// - Middleware: the request id middleware
+func RequestIDMiddleware(header string) Middleware {
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	return HeaderFuncMiddleware(func(ctx context.Context, method string) (http.Header, error) {
+		id, ok := RequestIDFromContext(ctx)
+		if !ok {
+			return nil, nil
+		}
+		return http.Header{header: []string{id}}, nil
+	})
+}
+
+// HeadersMiddleware adds static HTTP headers, such as an API key, to every call.
+// Headers are only sent by HTTP providers.
+//
+// Parameters:
+// - headers: The headers to add
+// Returns:
+// - Middleware: the headers middleware
+func HeadersMiddleware(headers http.Header) Middleware {
+	headers = headers.Clone()
+	return HeaderFuncMiddleware(func(context.Context, string) (http.Header, error) {
+		return headers, nil
+	})
+}
+
+// HeaderFuncMiddleware adds the HTTP headers returned by fn to each call, for
+// instance a short-lived bearer token. A call fails without being sent if fn
+// returns an error. Headers are only sent by HTTP providers.
+//
+// Parameters:
+// - fn: The function returning the headers of a call
+// Returns:
+// - Middleware: the headers middleware
+func HeaderFuncMiddleware(fn func(ctx context.Context, method string) (http.Header, error)) Middleware {
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			headers, err := fn(ctx, method)
+			if err != nil {
+				return err
+			}
+			return next(ethrpc.NewContextWithHeaders(ctx, headers), result, method, args...)
+		}
+	}
+}
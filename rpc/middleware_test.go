@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMiddleware tests that the middlewares wrap single and batch calls in
+// order and that the headers and request ids reach the node.
+func TestMiddleware(t *testing.T) {
+	var received []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Clone())
+		var body json.RawMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		if body[0] == '[' {
+			var batch []struct {
+				ID json.RawMessage `json:"id"`
+			}
+			require.NoError(t, json.Unmarshal(body, &batch))
+			_, _ = w.Write([]byte(`[{"jsonrpc":"2.0","id":` + string(batch[0].ID) + `,"result":"0x534e5f4d41494e"},` +
+				`{"jsonrpc":"2.0","id":` + string(batch[1].ID) + `,"result":7}]`))
+			return
+		}
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		require.NoError(t, json.Unmarshal(body, &req))
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"error":{"code":24,"message":"Block not found"}}`))
+	}))
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next CallFunc) CallFunc {
+			return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+				order = append(order, name+":"+method)
+				return next(ctx, result, method, args...)
+			}
+		}
+	}
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	metrics := &CallMetrics{}
+
+	provider, err := NewProvider(server.URL,
+		WithMiddleware(Chain(trace("a"), trace("b")), LoggingMiddleware(logger)),
+		WithMiddleware(MetricsMiddleware(metrics), RequestIDMiddleware(""), HeadersMiddleware(http.Header{"X-Api-Key": {"secret"}})),
+	)
+	require.NoError(t, err)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	_, err = provider.ClassHashAt(ctx, WithBlockNumber(1), new(felt.Felt))
+	require.Equal(t, ErrBlockNotFound, err)
+	require.Equal(t, []string{"a:starknet_getClassHashAt", "b:starknet_getClassHashAt"}, order)
+	require.Equal(t, "req-1", received[0].Get(DefaultRequestIDHeader))
+	require.Equal(t, "secret", received[0].Get("X-Api-Key"))
+	require.Contains(t, logs.String(), `"level":"WARN"`)
+	require.Contains(t, logs.String(), `"request_id":"req-1"`)
+
+	var chainID string
+	var count uint64
+	batch := provider.NewBatch()
+	batch.ChainID(&chainID)
+	batch.BlockNumber(&count)
+	require.NoError(t, batch.Send(context.Background()))
+	require.Equal(t, "SN_MAIN", chainID)
+	require.Equal(t, uint64(7), count)
+	require.Len(t, received, 2)
+	require.Empty(t, received[1].Get(DefaultRequestIDHeader))
+	require.Equal(t, "secret", received[1].Get("X-Api-Key"))
+
+	stats := metrics.Stats()
+	require.Equal(t, MethodStats{Calls: 1, Errors: 1}, withoutLatency(stats["starknet_getClassHashAt"]))
+	require.Equal(t, MethodStats{Calls: 1}, withoutLatency(stats[BatchMethod]))
+}
+
+// withoutLatency clears the latencies of s for comparisons.
+func withoutLatency(s MethodStats) MethodStats {
+	s.TotalLatency, s.MaxLatency = 0, 0
+	return s
+}
+
+// TestHeaderFuncMiddlewareError tests that a call is not sent when the headers can't be built.
+func TestHeaderFuncMiddlewareError(t *testing.T) {
+	errNoToken := errors.New("no token")
+	sent := false
+	c := newMiddlewareClient(rawAnswers{}, HeaderFuncMiddleware(func(context.Context, string) (http.Header, error) {
+		return nil, errNoToken
+	}), func(next CallFunc) CallFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			sent = true
+			return next(ctx, result, method, args...)
+		}
+	})
+	provider := &Provider{c: c}
+
+	_, err := provider.BlockNumber(context.Background())
+	require.Equal(t, errNoToken, err.(*RPCError).Data)
+	require.False(t, sent)
+}
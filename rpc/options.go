@@ -0,0 +1,73 @@
+package rpc
+
+import (
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// providerConfig holds the settings of a Provider given as NewProvider options.
+type providerConfig struct {
+	middlewares     []Middleware
+	specVersion     string
+	specVersionMode SpecVersionMode
+	cassette        *Cassette
+}
+
+// providerOption is a NewProvider option that configures the Provider rather
+// than the underlying ethrpc client. It satisfies ethrpc.ClientOption so that
+// it can be mixed with the ethrpc options; NewProvider removes it before
+// dialing, so the embedded ClientOption is never applied.
+type providerOption struct {
+	ethrpc.ClientOption
+	apply func(*providerConfig)
+}
+
+// newProviderOption returns a NewProvider option applying f to the provider config.
+//
+// Parameters:
+// - f: The function modifying the config
+// Returns:
+// - *providerOption: the option
+func newProviderOption(f func(*providerConfig)) *providerOption {
+	return &providerOption{apply: f}
+}
+
+// splitOptions separates the provider options from the ethrpc client options.
+//
+// Parameters:
+// - options: The options given to NewProvider
+// Returns:
+// - providerConfig: the config built from the provider options
+// - []ethrpc.ClientOption: the remaining client options
+func splitOptions(options []ethrpc.ClientOption) (providerConfig, []ethrpc.ClientOption) {
+	var cfg providerConfig
+	clientOptions := make([]ethrpc.ClientOption, 0, len(options))
+	for _, option := range options {
+		if po, ok := option.(*providerOption); ok {
+			po.apply(&cfg)
+			continue
+		}
+		clientOptions = append(clientOptions, option)
+	}
+	return cfg, clientOptions
+}
+
+// newProviderClient wraps c according to cfg.
+//
+// Parameters:
+// - c: The client connected to the node
+// - cfg: The provider config
+// Returns:
+// - callCloser: the client used by the Provider
+func newProviderClient(c callCloser, cfg providerConfig) callCloser {
+	if cfg.cassette != nil {
+		c = &cassetteClient{c: c, cassette: cfg.cassette}
+	}
+	if cfg.specVersionMode != SpecVersionUnchecked {
+		// innermost, so that the other middlewares see the adapted results
+		c = newMiddlewareClient(c, specVersionMiddleware(cfg.specVersionMode, cfg.specVersion))
+	}
+	if len(cfg.middlewares) > 0 {
+		c = newMiddlewareClient(c, cfg.middlewares...)
+	}
+	return c
+}
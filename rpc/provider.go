@@ -7,6 +7,7 @@ import (
 	"net/http/cookiejar"
 
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
 	ethrpc "github.com/ethereum/go-ethereum/rpc"
 	"golang.org/x/net/publicsuffix"
 )
@@ -23,11 +24,22 @@ type Provider struct {
 }
 
 // NewProvider creates a new rpc Provider instance.
+//
+// The options are either ethrpc client options, such as ethrpc.WithHeader for
+// a static API key, or provider options such as WithMiddleware.
+//
+// The spec version of the node is checked on the first call depending on
+// it, see WithSpecVersionMode. It is read from the URL instead when the URL
+// ends with a versioned path such as /rpc/v0_7.
 func NewProvider(url string, options ...ethrpc.ClientOption) (*Provider, error) {
 	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
 	if err != nil {
 		return nil, err
 	}
+	cfg, options := splitOptions(options)
+	if cfg.specVersion == "" {
+		cfg.specVersion = specVersionFromURL(url)
+	}
 	client := &http.Client{Jar: jar}
 	// prepend the custom client to allow users to override
 	options = append([]ethrpc.ClientOption{ethrpc.WithHTTPClient(client)}, options...)
@@ -37,7 +49,7 @@ func NewProvider(url string, options ...ethrpc.ClientOption) (*Provider, error)
 		return nil, err
 	}
 
-	return &Provider{c: c}, nil
+	return &Provider{c: newProviderClient(c, cfg)}, nil
 }
 
 //go:generate mockgen -destination=../mocks/mock_rpc_provider.go -package=mocks -source=provider.go api
@@ -55,15 +67,18 @@ type RpcProvider interface {
 	Class(ctx context.Context, blockID BlockID, classHash *felt.Felt) (ClassOutput, error)
 	ClassAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (ClassOutput, error)
 	ClassHashAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error)
+	CompiledCasm(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error)
 	EstimateFee(ctx context.Context, requests []BroadcastTxn, simulationFlags []SimulationFlag, blockID BlockID) ([]FeeEstimate, error)
 	EstimateMessageFee(ctx context.Context, msg MsgFromL1, blockID BlockID) (*FeeEstimate, error)
 	Events(ctx context.Context, input EventsInput) (*EventChunk, error)
 	BlockWithReceipts(ctx context.Context, blockID BlockID) (interface{}, error)
 	GetTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*TxnStatusResp, error)
+	MessagesStatus(ctx context.Context, transactionHash NumAsHex) ([]MessageStatusResp, error)
 	Nonce(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error)
 	SimulateTransactions(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag) ([]SimulatedTransaction, error)
 	StateUpdate(ctx context.Context, blockID BlockID) (*StateUpdateOutput, error)
 	StorageAt(ctx context.Context, contractAddress *felt.Felt, key string, blockID BlockID) (string, error)
+	StorageProof(ctx context.Context, input StorageProofInput) (*StorageProof, error)
 	SpecVersion(ctx context.Context) (string, error)
 	Syncing(ctx context.Context) (*SyncStatus, error)
 	TraceBlockTransactions(ctx context.Context, blockID BlockID) ([]Trace, error)
@@ -71,6 +86,9 @@ type RpcProvider interface {
 	TransactionByHash(ctx context.Context, hash *felt.Felt) (Transaction, error)
 	TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*TransactionReceiptWithBlockInfo, error)
 	TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (TxnTrace, error)
+	TypedBlockWithReceipts(ctx context.Context, blockID BlockID) (*BlockWithReceiptsResult, error)
+	TypedBlockWithTxHashes(ctx context.Context, blockID BlockID) (*BlockTxHashesResult, error)
+	TypedBlockWithTxs(ctx context.Context, blockID BlockID) (*BlockResult, error)
 }
 
 var _ RpcProvider = &Provider{}
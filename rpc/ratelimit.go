@@ -0,0 +1,173 @@
+package rpc
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// RateLimitConfig configures the client-side limits of a Provider.
+type RateLimitConfig struct {
+	// RequestsPerSecond the rate at which the token bucket refills. Zero
+	// disables the rate limit.
+	RequestsPerSecond float64
+	// Burst the capacity of the token bucket, RequestsPerSecond rounded up
+	// if not set
+	Burst int
+	// MaxInFlight the maximum number of concurrent requests. Zero means no limit.
+	MaxInFlight int
+	// Weights the number of tokens taken by a call of each method, for
+	// instance {"starknet_traceBlockTransactions": 10, "starknet_getEvents": 5}.
+	// Methods not listed take one token. A batch takes the sum of the weights
+	// of its calls. Weights above Burst are lowered to Burst.
+	Weights map[string]int
+}
+
+// WithRateLimit is a NewProvider option limiting the calls of the Provider
+// according to config. A call waits until it is allowed to proceed or its
+// context is done, in which case the error of the context is returned.
+//
+// Parameters:
+// - config: The limits to enforce
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithRateLimit(config RateLimitConfig) ethrpc.ClientOption {
+	return WithMiddleware(RateLimitMiddleware(config))
+}
+
+// RateLimitMiddleware enforces a token bucket rate limit and a maximum number
+// of in-flight requests. The limits are shared by every call going through
+// the returned middleware.
+//
+// Parameters:
+// - config: The limits to enforce
+// Returns:
+// - Middleware: the rate limit middleware
+func RateLimitMiddleware(config RateLimitConfig) Middleware {
+	var bucket *tokenBucket
+	if config.RequestsPerSecond > 0 {
+		burst := config.Burst
+		if burst <= 0 {
+			burst = int(math.Ceil(config.RequestsPerSecond))
+		}
+		bucket = newTokenBucket(config.RequestsPerSecond, burst)
+	}
+	var inFlight chan struct{}
+	if config.MaxInFlight > 0 {
+		inFlight = make(chan struct{}, config.MaxInFlight)
+	}
+	weights := make(map[string]int, len(config.Weights))
+	for method, weight := range config.Weights {
+		weights[method] = weight
+	}
+
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			if bucket != nil {
+				if err := bucket.wait(ctx, callWeight(weights, method, args)); err != nil {
+					return err
+				}
+			}
+			if inFlight != nil {
+				select {
+				case inFlight <- struct{}{}:
+					defer func() { <-inFlight }()
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return next(ctx, result, method, args...)
+		}
+	}
+}
+
+// callWeight returns the number of tokens taken by a call.
+//
+// Parameters:
+// - weights: The weights of the methods
+// - method: The method of the call
+// - args: The arguments of the call, the elements of a batch
+// Returns:
+// - int: the weight of the call
+func callWeight(weights map[string]int, method string, args []interface{}) int {
+	if method == BatchMethod && len(args) == 1 {
+		if b, ok := args[0].([]ethrpc.BatchElem); ok {
+			total := 0
+			for _, elem := range b {
+				total += callWeight(weights, elem.Method, nil)
+			}
+			return total
+		}
+	}
+	if weight, ok := weights[method]; ok && weight > 0 {
+		return weight
+	}
+	return 1
+}
+
+// tokenBucket is a token bucket granting tokens in the order they are requested.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full token bucket.
+//
+// Parameters:
+// - rate: The number of tokens added per second
+// - burst: The capacity of the bucket
+// Returns:
+// - *tokenBucket: the token bucket
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// refill adds the tokens accumulated since the last refill. The caller must hold mu.
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// wait takes n tokens, blocking until they are available or ctx is done.
+// The tokens are reserved right away, so that callers are served in order.
+//
+// Parameters:
+// - ctx: The context bounding the wait
+// - n: The number of tokens to take
+// Returns:
+// - error: the error of ctx if it ended before the tokens were available
+func (b *tokenBucket) wait(ctx context.Context, n int) error {
+	tokens := math.Min(float64(n), b.burst)
+
+	b.mu.Lock()
+	b.refill(time.Now())
+	b.tokens -= tokens
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// give the reservation back to the callers waiting behind
+		b.mu.Lock()
+		b.refill(time.Now())
+		b.tokens = math.Min(b.burst, b.tokens+tokens)
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRateLimitMiddleware tests that calls wait for their tokens and that weights are applied.
+func TestRateLimitMiddleware(t *testing.T) {
+	c := newMiddlewareClient(rawAnswers{"starknet_blockNumber": `1`, "starknet_getEvents": `{}`}, RateLimitMiddleware(RateLimitConfig{
+		RequestsPerSecond: 20,
+		Burst:             2,
+		Weights:           map[string]int{"starknet_getEvents": 2},
+	}))
+	var result json.RawMessage
+
+	start := time.Now()
+	require.NoError(t, c.CallContext(context.Background(), &result, "starknet_blockNumber"))
+	require.NoError(t, c.CallContext(context.Background(), &result, "starknet_blockNumber"))
+	require.Less(t, time.Since(start), 40*time.Millisecond, "the burst is served right away")
+
+	start = time.Now()
+	require.NoError(t, c.CallContext(context.Background(), &result, "starknet_getEvents"))
+	require.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond, "a weight of 2 waits for 2 tokens")
+
+	// the caller blocks until its deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.NoError(t, c.CallContext(context.Background(), &result, "starknet_getEvents"))
+	err := c.CallContext(ctx, &result, "starknet_getEvents")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.Equal(t, 3, callWeight(map[string]int{"starknet_getEvents": 2}, BatchMethod, []interface{}{
+		[]ethrpc.BatchElem{{Method: "starknet_getEvents"}, {Method: "starknet_chainId"}},
+	}))
+}
+
+// blockingClient is a callCloser whose calls block until release is closed, tracking the concurrency.
+type blockingClient struct {
+	release  chan struct{}
+	inFlight int32
+	max      int32
+}
+
+// CallContext blocks until release is closed.
+func (b *blockingClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	n := atomic.AddInt32(&b.inFlight, 1)
+	for {
+		max := atomic.LoadInt32(&b.max)
+		if n <= max || atomic.CompareAndSwapInt32(&b.max, max, n) {
+			break
+		}
+	}
+	<-b.release
+	atomic.AddInt32(&b.inFlight, -1)
+	return nil
+}
+
+// Close does nothing.
+func (b *blockingClient) Close() {}
+
+// TestMaxInFlight tests that no more than MaxInFlight calls run at once.
+func TestMaxInFlight(t *testing.T) {
+	stub := &blockingClient{release: make(chan struct{})}
+	c := newMiddlewareClient(stub, RateLimitMiddleware(RateLimitConfig{MaxInFlight: 2}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, c.CallContext(context.Background(), nil, "starknet_blockNumber"))
+		}()
+	}
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&stub.inFlight) == 2 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, c.CallContext(ctx, nil, "starknet_blockNumber"), context.DeadlineExceeded)
+
+	close(stub.release)
+	wg.Wait()
+	require.Equal(t, int32(2), atomic.LoadInt32(&stub.max))
+}
@@ -0,0 +1,234 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// writeMethods are the methods submitting a transaction. They are only
+// retried when the node has certainly not received the transaction.
+var writeMethods = map[string]bool{
+	"starknet_addInvokeTransaction":        true,
+	"starknet_addDeclareTransaction":       true,
+	"starknet_addDeployAccountTransaction": true,
+}
+
+// RetryPolicy configures how a Provider retries failed calls.
+type RetryPolicy struct {
+	// MaxAttempts the maximum number of attempts of a call, including the
+	// first one. A value lower than 2 disables retries.
+	MaxAttempts int
+	// InitialBackoff the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff the upper bound of the delay between two attempts
+	MaxBackoff time.Duration
+	// Multiplier the factor applied to the delay after each retry, 2 if not set
+	Multiplier float64
+	// Jitter the fraction of the delay that is randomised, between 0 and 1.
+	// A delay d becomes a random value in [d*(1-Jitter), d].
+	Jitter float64
+	// Retryable decides whether an error is transient, IsRetryable if nil.
+	// Write methods are never retried once the node may have accepted the
+	// transaction, whatever Retryable returns.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy returns a policy making up to 4 attempts with an
+// exponential backoff starting at 200ms and capped at 5s, with full jitter.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - RetryPolicy: the default policy
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         1,
+	}
+}
+
+// WithRetry is a NewProvider option retrying the failed calls of the
+// Provider according to policy. It is equivalent to
+// WithMiddleware(RetryMiddleware(policy)).
+//
+// Parameters:
+// - policy: The retry policy
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithRetry(policy RetryPolicy) ethrpc.ClientOption {
+	return WithMiddleware(RetryMiddleware(policy))
+}
+
+// RetryMiddleware retries the calls failing with a transient error according
+// to policy. The backoff never outlasts the deadline of the call context: if
+// the next attempt can't start before the deadline, the last error is returned.
+//
+// Parameters:
+// - policy: The retry policy
+// Returns:
+// - Middleware: the retry middleware
+func RetryMiddleware(policy RetryPolicy) Middleware {
+	if policy.Multiplier < 1 {
+		policy.Multiplier = 2
+	}
+	if policy.Retryable == nil {
+		policy.Retryable = IsRetryable
+	}
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			write := isWriteCall(method, args)
+			for attempt := 1; ; attempt++ {
+				err := next(ctx, result, method, args...)
+				if err == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+					return err
+				}
+				if !policy.Retryable(err) || (write && !isNotReceived(err)) {
+					return err
+				}
+				if !sleepContext(ctx, policy.backoff(attempt)) {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// backoff returns the delay to wait after the given failed attempt.
+//
+// Parameters:
+// - attempt: The number of the failed attempt, starting at 1
+// Returns:
+// - time.Duration: the delay before the next attempt
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(policy.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		delay *= policy.Multiplier
+		if policy.MaxBackoff > 0 && delay >= float64(policy.MaxBackoff) {
+			break
+		}
+	}
+	if policy.MaxBackoff > 0 && delay > float64(policy.MaxBackoff) {
+		delay = float64(policy.MaxBackoff)
+	}
+	jitter := policy.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+	if jitter > 0 {
+		delay -= delay * jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// sleepContext waits for d unless ctx ends first or its deadline is closer than d.
+//
+// Parameters:
+// - ctx: The context bounding the wait
+// - d: The duration to wait
+// Returns:
+// - bool: true if the whole duration elapsed
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return false
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// isWriteCall reports whether the call submits a transaction, including
+// batches containing a transaction.
+func isWriteCall(method string, args []interface{}) bool {
+	if method == BatchMethod && len(args) == 1 {
+		if b, ok := args[0].([]ethrpc.BatchElem); ok {
+			for _, elem := range b {
+				if writeMethods[elem.Method] {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return writeMethods[method]
+}
+
+// IsRetryable reports whether err is a transient failure worth retrying:
+// connection errors and timeouts, HTTP 408, 429 and 5xx responses, and the
+// Starknet "unexpected error" and JSON-RPC internal error codes. Every other
+// Starknet error, such as ErrDuplicateTx or ErrInvalidTransactionNonce, is
+// terminal, as are the errors of a cancelled or expired context.
+//
+// Parameters:
+// - err: The error of a call
+// Returns:
+// - bool: whether the call may succeed if retried
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if code, ok := rpcErrorCode(err); ok {
+		return code == ErrUnexpectedError.Code || code == InternalError
+	}
+
+	var httpErr ethrpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusRequestTimeout ||
+			httpErr.StatusCode == http.StatusTooManyRequests ||
+			httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// isNotReceived reports whether err proves that the request never reached
+// the node, so that a transaction can safely be sent again: the connection
+// could not be established or the request was rate limited.
+func isNotReceived(err error) bool {
+	var httpErr ethrpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// rpcErrorCode returns the code of a JSON-RPC error answered by the node.
+func rpcErrorCode(err error) (int, bool) {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code, true
+	}
+	var ethErr ethrpc.Error
+	if errors.As(err, &ethErr) {
+		return ethErr.ErrorCode(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,115 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedErrors is a callCloser failing with the scripted errors before answering `"0x1"`.
+type scriptedErrors struct {
+	errs  []error
+	calls int
+}
+
+// CallContext returns the next scripted error, or writes `"0x1"` to result once they are exhausted.
+func (s *scriptedErrors) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	s.calls++
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+		return err
+	}
+	return json.Unmarshal([]byte(`"0x1"`), result)
+}
+
+// Close does nothing.
+func (s *scriptedErrors) Close() {}
+
+// TestRetryMiddleware tests which errors are retried for read and write methods.
+func TestRetryMiddleware(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond, Jitter: 0.5}
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	resetErr := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+
+	type testSetType struct {
+		Method        string
+		Errs          []error
+		ExpectedCalls int
+		ExpectedErr   bool
+	}
+	testSet := []testSetType{
+		{Method: "starknet_getNonce", Errs: []error{ethrpc.HTTPError{StatusCode: http.StatusTooManyRequests}, resetErr}, ExpectedCalls: 3},
+		{Method: "starknet_getNonce", Errs: []error{ErrUnexpectedError, ErrUnexpectedError, ErrUnexpectedError}, ExpectedCalls: 3, ExpectedErr: true},
+		{Method: "starknet_getNonce", Errs: []error{ethrpc.HTTPError{StatusCode: http.StatusBadRequest}}, ExpectedCalls: 1, ExpectedErr: true},
+		{Method: "starknet_getNonce", Errs: []error{ErrBlockNotFound}, ExpectedCalls: 1, ExpectedErr: true},
+		{Method: "starknet_addInvokeTransaction", Errs: []error{dialErr, ethrpc.HTTPError{StatusCode: http.StatusTooManyRequests}}, ExpectedCalls: 3},
+		{Method: "starknet_addInvokeTransaction", Errs: []error{resetErr}, ExpectedCalls: 1, ExpectedErr: true},
+		{Method: "starknet_addInvokeTransaction", Errs: []error{ethrpc.HTTPError{StatusCode: http.StatusBadGateway}}, ExpectedCalls: 1, ExpectedErr: true},
+		{Method: "starknet_addInvokeTransaction", Errs: []error{ErrDuplicateTx}, ExpectedCalls: 1, ExpectedErr: true},
+		{Method: "starknet_addInvokeTransaction", Errs: []error{ErrInvalidTransactionNonce}, ExpectedCalls: 1, ExpectedErr: true},
+	}
+
+	for _, test := range testSet {
+		stub := &scriptedErrors{errs: test.Errs}
+		c := newMiddlewareClient(stub, RetryMiddleware(policy))
+		var result json.RawMessage
+		err := c.CallContext(context.Background(), &result, test.Method)
+		require.Equal(t, test.ExpectedCalls, stub.calls, test.Method)
+		if test.ExpectedErr {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, `"0x1"`, string(result))
+	}
+}
+
+// TestRetryRespectsDeadline tests that no attempt is made when the backoff would outlast the context deadline.
+func TestRetryRespectsDeadline(t *testing.T) {
+	stub := &scriptedErrors{errs: []error{ErrUnexpectedError, ErrUnexpectedError}}
+	c := newMiddlewareClient(stub, RetryMiddleware(RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	var result json.RawMessage
+	err := c.CallContext(ctx, &result, "starknet_blockNumber")
+	require.Equal(t, ErrUnexpectedError, err)
+	require.Equal(t, 1, stub.calls)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+// TestWithRetry tests that a Provider retries HTTP errors answered by the node.
+func TestWithRetry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":42}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(server.URL, WithRetry(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+	require.NoError(t, err)
+	blockNumber, err := provider.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), blockNumber)
+	require.Equal(t, 2, requests)
+}
@@ -0,0 +1,249 @@
+package rpc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// byteArrayMagic is the first felt of a Cairo panic carrying a ByteArray message.
+var byteArrayMagic = parseFelt("0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3")
+
+var (
+	// calledContractPattern matches the frames of the revert reasons, with the
+	// address, class hash and selector since Starknet v0.13.1 or the address only before.
+	calledContractPattern = regexp.MustCompile(`Error in the called contract \((?:contract address: (0x[0-9a-fA-F]+), class hash: (0x[0-9a-fA-F]+), selector: (0x[0-9a-fA-F]+)|(0x[0-9a-fA-F]+))\)`)
+	pcPattern             = regexp.MustCompile(`Error at pc=(\d+:\d+)`)
+	failurePattern        = regexp.MustCompile(`(?i)failure reason:\s*(.*)`)
+	errorMessagePattern   = regexp.MustCompile(`^Error message:\s*(.*)`)
+	quotedPattern         = regexp.MustCompile(`\('(?:[^'\\]|\\.)*'\)`)
+	hexPattern            = regexp.MustCompile(`0x[0-9a-fA-F]+`)
+	// ignoredRevertLines are the lines of the revert reasons carrying no information
+	ignoredRevertLines = regexp.MustCompile(`^(Cairo traceback \(most recent call last\):|Unknown location \(pc=\d+:\d+\)|Transaction execution has failed:?|\d+:)$`)
+)
+
+// RevertTrace is a decoded revert reason: the calls leading to the failure,
+// outermost first, and the failure message.
+type RevertTrace struct {
+	Frames []RevertFrame
+	// Reason the failure message, with the felts decoded to strings
+	Reason string
+	// Messages the lines of the revert reason outside of any call
+	Messages []string
+}
+
+// RevertFrame is a call in a RevertTrace. The fields the revert reason
+// doesn't give are left empty.
+type RevertFrame struct {
+	ContractAddress *felt.Felt
+	ClassHash       *felt.Felt
+	Selector        *felt.Felt
+	// PC the program counter of the failure in the call, such as "0:4835"
+	PC string
+	// Messages the other lines of the call, such as Cairo 0 error messages or hint errors
+	Messages []string
+}
+
+// ParseRevertReason decodes a revert reason, as found in
+// TransactionReceipt.RevertReason, ExecInvocation.RevertReason or the data
+// of ErrContractError and ErrTxnExec.
+//
+// Parameters:
+// - reason: The revert reason
+// Returns:
+// - *RevertTrace: the decoded revert reason
+func ParseRevertReason(reason string) *RevertTrace {
+	trace := &RevertTrace{}
+	var frame *RevertFrame
+	for _, line := range strings.Split(reason, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || ignoredRevertLines.MatchString(line) {
+			continue
+		}
+
+		if m := calledContractPattern.FindStringSubmatch(line); m != nil {
+			trace.Frames = append(trace.Frames, RevertFrame{})
+			frame = &trace.Frames[len(trace.Frames)-1]
+			if m[4] != "" {
+				frame.ContractAddress = parseFelt(m[4])
+			} else {
+				frame.ContractAddress, frame.ClassHash, frame.Selector = parseFelt(m[1]), parseFelt(m[2]), parseFelt(m[3])
+			}
+			continue
+		}
+		if m := pcPattern.FindStringSubmatch(line); m != nil && frame != nil {
+			frame.PC = m[1]
+			continue
+		}
+		if m := failurePattern.FindStringSubmatch(line); m != nil {
+			trace.Reason = decodeFailurePayload(m[1])
+			continue
+		}
+
+		message := line
+		if m := errorMessagePattern.FindStringSubmatch(line); m != nil {
+			message = m[1]
+		}
+		if frame != nil {
+			frame.Messages = append(frame.Messages, message)
+		} else {
+			trace.Messages = append(trace.Messages, message)
+		}
+	}
+
+	if trace.Reason == "" {
+		// Cairo 0 contracts fail with error messages
+		for i := len(trace.Frames) - 1; i >= 0 && trace.Reason == ""; i-- {
+			if messages := trace.Frames[i].Messages; len(messages) > 0 {
+				trace.Reason = messages[len(messages)-1]
+			}
+		}
+		if trace.Reason == "" && len(trace.Messages) > 0 {
+			trace.Reason = trace.Messages[len(trace.Messages)-1]
+		}
+	}
+	return trace
+}
+
+// Trace decodes the error into a RevertTrace, the calls of the v0.8
+// structure followed by the calls of the message.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *RevertTrace: the decoded error
+func (e ContractExecutionError) Trace() *RevertTrace {
+	var frames []RevertFrame
+	current := &e
+	for current.Call != nil {
+		frames = append(frames, RevertFrame{
+			ContractAddress: current.Call.ContractAddress,
+			ClassHash:       current.Call.ClassHash,
+			Selector:        current.Call.Selector,
+		})
+		if current.Call.Error == nil {
+			return &RevertTrace{Frames: frames}
+		}
+		current = current.Call.Error
+	}
+	trace := ParseRevertReason(current.Message)
+	if message := strings.TrimSpace(current.Message); strings.HasPrefix(message, "0x") || strings.HasPrefix(message, "(0x") {
+		// the innermost error is the bare panic data
+		trace.Reason, trace.Messages = decodeFailurePayload(message), nil
+	}
+	trace.Frames = append(frames, trace.Frames...)
+	return trace
+}
+
+// String returns the calls of the trace, one per line, followed by the reason.
+func (t *RevertTrace) String() string {
+	var s strings.Builder
+	for _, message := range t.Messages {
+		if message != t.Reason {
+			fmt.Fprintf(&s, "%s\n", message)
+		}
+	}
+	for i, frame := range t.Frames {
+		fmt.Fprintf(&s, "%d: contract %s", i, feltOrUnknown(frame.ContractAddress))
+		if frame.ClassHash != nil {
+			fmt.Fprintf(&s, ", class hash %s", frame.ClassHash)
+		}
+		if frame.Selector != nil {
+			fmt.Fprintf(&s, ", selector %s", frame.Selector)
+		}
+		if frame.PC != "" {
+			fmt.Fprintf(&s, ", pc=%s", frame.PC)
+		}
+		s.WriteString("\n")
+		for _, message := range frame.Messages {
+			if message != t.Reason {
+				fmt.Fprintf(&s, "   %s\n", message)
+			}
+		}
+	}
+	fmt.Fprintf(&s, "reason: %s", t.Reason)
+	return s.String()
+}
+
+// DecodeFailureReason decodes the felts of a Cairo panic: a ByteArray
+// message if they start with the ByteArray magic value, otherwise one short
+// string per felt, the felts that aren't printable being kept in hex.
+//
+// Parameters:
+// - felts: The panic data
+// Returns:
+// - string: the failure message
+func DecodeFailureReason(felts []*felt.Felt) string {
+	if len(felts) >= 4 && felts[0].Equal(byteArrayMagic) {
+		// magic, words count, words, pending word, pending word length
+		count := utils.FeltToBigInt(felts[1])
+		if count.IsUint64() && count.Uint64() <= uint64(len(felts)-4) {
+			if s, err := utils.ByteArrFeltToStringExact(felts[1 : count.Uint64()+4]); err == nil {
+				return s
+			}
+		}
+	}
+	parts := make([]string, len(felts))
+	for i, f := range felts {
+		parts[i] = f.String()
+		if s := utils.HexToShortStr(parts[i]); isPrintable(s) {
+			parts[i] = s
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// decodeFailurePayload decodes the failure reason of a revert reason, such as
+// "0x4e6f7420656e6f756768 ('Not enough')." or "(0x46a6..., 0x0, ...).".
+func decodeFailurePayload(payload string) string {
+	// the decoding done by the node, if any, is done again
+	stripped := quotedPattern.ReplaceAllString(payload, "")
+	hexes := hexPattern.FindAllString(stripped, -1)
+	if len(hexes) == 0 {
+		return strings.TrimSuffix(strings.TrimSpace(payload), ".")
+	}
+	felts := make([]*felt.Felt, 0, len(hexes))
+	for _, h := range hexes {
+		f := parseFelt(h)
+		if f == nil {
+			return strings.TrimSuffix(strings.TrimSpace(payload), ".")
+		}
+		felts = append(felts, f)
+	}
+	return DecodeFailureReason(felts)
+}
+
+// parseFelt parses a hex felt, nil if it isn't one.
+func parseFelt(s string) *felt.Felt {
+	f, err := new(felt.Felt).SetString(s)
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+// feltOrUnknown returns the hex form of f, "unknown" if nil.
+func feltOrUnknown(f *felt.Felt) string {
+	if f == nil {
+		return "unknown"
+	}
+	return f.String()
+}
+
+// isPrintable reports whether s is a non-empty ASCII printable string.
+func isPrintable(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < 0x20 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseRevertReason tests the decoding of the revert reasons of the different Starknet versions.
+func TestParseRevertReason(t *testing.T) {
+	type testSetType struct {
+		Reason         string
+		ExpectedFrames []RevertFrame
+		ExpectedReason string
+	}
+	testSet := []testSetType{
+		{
+			Reason: "Error in the called contract (0x03b1b7a7ae9a136a327b01b89ddfee24a474c74bf76032876b5754e44ca2b0d4):\n" +
+				"Error at pc=0:4835:\n" +
+				"Got an exception while executing a hint: Execution was reverted; failure reason: [0x496e73756666696369656e742062616c616e6365].\n",
+			ExpectedFrames: []RevertFrame{{
+				ContractAddress: utils.TestHexToFelt(t, "0x03b1b7a7ae9a136a327b01b89ddfee24a474c74bf76032876b5754e44ca2b0d4"),
+				PC:              "0:4835",
+			}},
+			ExpectedReason: "Insufficient balance",
+		},
+		{
+			Reason: "Error in the called contract (0x1):\n" +
+				"Error at pc=0:18:\n" +
+				"Cairo traceback (most recent call last):\n" +
+				"Unknown location (pc=0:67)\n" +
+				"Error message: ERC20: transfer amount exceeds balance\n",
+			ExpectedFrames: []RevertFrame{{
+				ContractAddress: utils.TestHexToFelt(t, "0x1"),
+				PC:              "0:18",
+				Messages:        []string{"ERC20: transfer amount exceeds balance"},
+			}},
+			ExpectedReason: "ERC20: transfer amount exceeds balance",
+		},
+		{
+			Reason: "Transaction execution has failed:\n" +
+				"0: Error in the called contract (contract address: 0x1, class hash: 0x2, selector: 0x3):\n" +
+				"Error at pc=0:4573:\n" +
+				"1: Error in the called contract (contract address: 0x4, class hash: 0x5, selector: 0x6):\n" +
+				"Execution failed. Failure reason: 0x753235365f737562204f766572666c6f77 ('u256_sub Overflow').\n",
+			ExpectedFrames: []RevertFrame{
+				{
+					ContractAddress: utils.TestHexToFelt(t, "0x1"),
+					ClassHash:       utils.TestHexToFelt(t, "0x2"),
+					Selector:        utils.TestHexToFelt(t, "0x3"),
+					PC:              "0:4573",
+				},
+				{
+					ContractAddress: utils.TestHexToFelt(t, "0x4"),
+					ClassHash:       utils.TestHexToFelt(t, "0x5"),
+					Selector:        utils.TestHexToFelt(t, "0x6"),
+				},
+			},
+			ExpectedReason: "u256_sub Overflow",
+		},
+		{
+			Reason: "Execution failed. Failure reason: (0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3, 0x1, " +
+				"0x45524332303a20696e73756666696369656e742062616c616e636520746f20, 0x7472616e73666572, 0x8) ('ERC20: insufficient balance to transfer').",
+			ExpectedReason: "ERC20: insufficient balance to transfer",
+		},
+		{
+			Reason:         "Execution failed. Failure reason: (0x4e6f74206f776e6572, 0x1, 0x2).",
+			ExpectedReason: "Not owner, 0x1, 0x2",
+		},
+	}
+
+	for _, test := range testSet {
+		trace := ParseRevertReason(test.Reason)
+		require.Equal(t, test.ExpectedFrames, trace.Frames)
+		require.Equal(t, test.ExpectedReason, trace.Reason)
+	}
+}
+
+// TestDecodeFailureReason tests the decoding of the ByteArray and short string panics.
+func TestDecodeFailureReason(t *testing.T) {
+	message := "a message longer than a single felt, spanning two words"
+	byteArray, err := utils.StringToByteArrFelt(message)
+	require.NoError(t, err)
+	require.Equal(t, message, DecodeFailureReason(append([]*felt.Felt{byteArrayMagic}, byteArray...)))
+
+	require.Equal(t, "ENTRYPOINT_FAILED", DecodeFailureReason([]*felt.Felt{utils.TestHexToFelt(t, "0x454e545259504f494e545f4641494c4544")}))
+	// a truncated ByteArray is decoded as short strings
+	require.Equal(t, byteArrayMagic.String()+", 0x5", DecodeFailureReason([]*felt.Felt{byteArrayMagic, utils.TestHexToFelt(t, "0x5")}))
+}
+
+// TestContractExecutionErrorTrace tests the decoding of the structured errors of the v0.8 spec.
+func TestContractExecutionErrorTrace(t *testing.T) {
+	var executionErr ContractExecutionError
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"contract_address": "0x1",
+		"class_hash": "0x2",
+		"selector": "0x3",
+		"error": {
+			"contract_address": "0x4",
+			"class_hash": "0x5",
+			"selector": "0x6",
+			"error": "0x4e6f74206f776e6572 ('Not owner')"
+		}
+	}`), &executionErr))
+
+	trace := executionErr.Trace()
+	require.Len(t, trace.Frames, 2)
+	require.Equal(t, utils.TestHexToFelt(t, "0x4"), trace.Frames[1].ContractAddress)
+	require.Equal(t, utils.TestHexToFelt(t, "0x6"), trace.Frames[1].Selector)
+	require.Equal(t, "Not owner", trace.Reason)
+	require.Equal(t, "0: contract 0x1, class hash 0x2, selector 0x3\n1: contract 0x4, class hash 0x5, selector 0x6\nreason: Not owner", trace.String())
+}
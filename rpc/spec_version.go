@@ -0,0 +1,514 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// RPCSpecVersion is the version of the Starknet JSON-RPC specification the
+// types of this package are written for.
+const RPCSpecVersion = "0.8"
+
+// SupportedSpecVersions are the spec versions a Provider can talk to in
+// SpecVersionCompatible mode.
+var SupportedSpecVersions = []string{"0.6", "0.7", "0.8"}
+
+// ErrUnsupportedSpecVersion is matched with errors.Is by the
+// SpecVersionError returned when the node implements a spec version the
+// Provider can't talk to.
+var ErrUnsupportedSpecVersion = errors.New("unsupported Starknet JSON-RPC spec version")
+
+// SpecVersionError reports a node implementing an unsupported spec version.
+type SpecVersionError struct {
+	// Version the spec version of the node
+	Version string
+	// Supported the versions accepted by the Provider
+	Supported []string
+}
+
+// Error returns the versions of the node and of the Provider.
+func (e *SpecVersionError) Error() string {
+	return fmt.Sprintf("the node implements Starknet JSON-RPC spec %s, supported versions are %s", e.Version, strings.Join(e.Supported, ", "))
+}
+
+// Unwrap returns ErrUnsupportedSpecVersion.
+func (e *SpecVersionError) Unwrap() error {
+	return ErrUnsupportedSpecVersion
+}
+
+// ErrUnsupportedByNode is matched with errors.Is by the SpecFeatureError
+// returned when a call relies on a spec version newer than the one of the
+// node.
+var ErrUnsupportedByNode = errors.New("unsupported by the spec version of the node")
+
+// SpecFeatureError reports a call relying on a method or a field that the
+// spec version of the node doesn't have, such as starknet_getStorageProof
+// sent to a v0.7 node.
+type SpecFeatureError struct {
+	// Feature the method or the field
+	Feature string
+	// Version the spec version of the node
+	Version string
+	// Since the spec version introducing the feature
+	Since string
+}
+
+// Error returns the feature and the versions.
+func (e *SpecFeatureError) Error() string {
+	return fmt.Sprintf("%s needs Starknet JSON-RPC spec %s, the node implements %s", e.Feature, e.Since, e.Version)
+}
+
+// Unwrap returns ErrUnsupportedByNode.
+func (e *SpecFeatureError) Unwrap() error {
+	return ErrUnsupportedByNode
+}
+
+// SpecVersionMode selects how a Provider created by NewProvider checks the
+// spec version of the node.
+type SpecVersionMode int
+
+const (
+	// SpecVersionCompatible queries the spec version of the node on the first
+	// call that depends on it, fails the calls if it isn't one of
+	// SupportedSpecVersions, and adapts the requests and results of older
+	// versions to the types of this package: the fee estimates and the
+	// l1_data_gas resource bounds of the fee estimates and the simulations.
+	// The calls relying on v0.8 methods, l2_gas resource bounds, or on
+	// l1_data_gas resource bounds to send a transaction fail with a
+	// SpecFeatureError on older nodes. The calls with the same shape in every
+	// version are sent without the query. This is the default.
+	SpecVersionCompatible SpecVersionMode = iota
+	// SpecVersionStrict fails the calls if the node doesn't implement RPCSpecVersion.
+	SpecVersionStrict
+	// SpecVersionUnchecked never queries the spec version and sends the calls as they are.
+	SpecVersionUnchecked
+)
+
+// WithSpecVersionMode is a NewProvider option selecting how the spec version
+// of the node is checked.
+//
+// Parameters:
+// - mode: The check mode
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithSpecVersionMode(mode SpecVersionMode) ethrpc.ClientOption {
+	return newProviderOption(func(cfg *providerConfig) {
+		cfg.specVersionMode = mode
+	})
+}
+
+// WithSpecVersion is a NewProvider option giving the spec version of the
+// node, which is then not queried. The version is still checked against the
+// mode. NewProvider infers it from URLs ending with a versioned path such as
+// /rpc/v0_7.
+//
+// Parameters:
+// - version: The spec version of the node, such as "0.7" or "0.7.1"
+// Returns:
+// - ethrpc.ClientOption: the option to pass to NewProvider
+func WithSpecVersion(version string) ethrpc.ClientOption {
+	return newProviderOption(func(cfg *providerConfig) {
+		cfg.specVersion = version
+	})
+}
+
+// VersionedURL returns the URL of the versioned path of a node serving
+// several spec versions, such as https://node/rpc/v0_7.
+//
+// Parameters:
+// - baseURL: The URL of the node, without the /rpc path
+// - version: The spec version, such as "0.7"
+// Returns:
+// - string: the URL of the versioned path
+func VersionedURL(baseURL, version string) string {
+	return strings.TrimRight(baseURL, "/") + "/rpc/v" + strings.ReplaceAll(majorMinor(version), ".", "_")
+}
+
+// versionedPath matches the versioned path at the end of a node URL.
+var versionedPath = regexp.MustCompile(`/rpc/v(\d+)_(\d+)/?$`)
+
+// specVersionFromURL returns the spec version of a versioned node URL.
+//
+// Parameters:
+// - url: The URL of the node
+// Returns:
+// - string: the spec version, empty if the URL has no versioned path
+func specVersionFromURL(url string) string {
+	m := versionedPath.FindStringSubmatch(url)
+	if m == nil {
+		return ""
+	}
+	return m[1] + "." + m[2]
+}
+
+// majorMinor returns the major and minor parts of a spec version.
+func majorMinor(version string) string {
+	version = strings.TrimPrefix(version, "v")
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// specVersionChecker holds the spec version of the node, queried once.
+type specVersionChecker struct {
+	mode SpecVersionMode
+
+	mu      sync.Mutex
+	version string
+	err     error
+	// inFlight is closed when the query being sent completes, nil if none is
+	inFlight chan struct{}
+}
+
+// specVersionMiddleware checks the spec version of the node before the
+// first call depending on it and adapts the calls to it.
+//
+// Parameters:
+// - mode: The check mode, SpecVersionCompatible or SpecVersionStrict
+// - version: The known spec version of the node, empty to query it
+// Returns:
+// - Middleware: the version middleware
+func specVersionMiddleware(mode SpecVersionMode, version string) Middleware {
+	checker := &specVersionChecker{mode: mode}
+	if version != "" {
+		checker.set(version)
+	}
+	return func(next CallFunc) CallFunc {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			if versionIndependentMethods[method] {
+				return next(ctx, result, method, args...)
+			}
+			if mode == SpecVersionCompatible && !versionDependent(method, args) {
+				return next(ctx, result, method, args...)
+			}
+			version, err := checker.check(ctx, next)
+			if err != nil {
+				return err
+			}
+			if mode != SpecVersionCompatible {
+				return next(ctx, result, method, args...)
+			}
+			return adaptCall(ctx, next, version, result, method, args)
+		}
+	}
+}
+
+// check returns the spec version of the node, querying it the first time.
+// The concurrent calls wait for the query in flight instead of sending
+// their own, or until their context is done.
+func (c *specVersionChecker) check(ctx context.Context, next CallFunc) (string, error) {
+	for {
+		c.mu.Lock()
+		if c.version != "" || c.err != nil {
+			version, err := c.version, c.err
+			c.mu.Unlock()
+			return version, err
+		}
+		if inFlight := c.inFlight; inFlight != nil {
+			c.mu.Unlock()
+			select {
+			case <-inFlight:
+				// a failed query is sent again by one of the waiting calls
+				continue
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+		done := make(chan struct{})
+		c.inFlight = done
+		c.mu.Unlock()
+
+		var version string
+		err := next(ctx, &version, "starknet_specVersion")
+
+		c.mu.Lock()
+		c.inFlight = nil
+		if err == nil {
+			c.set(version)
+		}
+		version, versionErr := c.version, c.err
+		c.mu.Unlock()
+		close(done)
+		if err != nil {
+			// not cached, the next call queries the version again
+			return "", fmt.Errorf("querying the spec version of the node: %w", err)
+		}
+		return version, versionErr
+	}
+}
+
+// set records the spec version of the node and whether it is supported.
+func (c *specVersionChecker) set(version string) {
+	version = majorMinor(version)
+	supported := SupportedSpecVersions
+	if c.mode == SpecVersionStrict {
+		supported = []string{RPCSpecVersion}
+	}
+	for _, v := range supported {
+		if v == version {
+			c.version = version
+			return
+		}
+	}
+	c.err = &SpecVersionError{Version: version, Supported: supported}
+}
+
+// versionIndependentMethods are the methods with the same shape in every
+// spec version, they are sent without waiting for the version check.
+var versionIndependentMethods = map[string]bool{
+	"starknet_specVersion":        true,
+	"starknet_chainId":            true,
+	"starknet_blockNumber":        true,
+	"starknet_blockHashAndNumber": true,
+}
+
+// transactionMethods are the methods sending a transaction, whose resource
+// bounds depend on the spec version.
+var transactionMethods = map[string]bool{
+	"starknet_addInvokeTransaction":        true,
+	"starknet_addDeclareTransaction":       true,
+	"starknet_addDeployAccountTransaction": true,
+}
+
+// v08Methods are the methods introduced by the v0.8 of the spec.
+var v08Methods = map[string]bool{
+	"starknet_getStorageProof":   true,
+	"starknet_getMessagesStatus": true,
+	"starknet_getCompiledCasm":   true,
+}
+
+// versionDependent reports from the method whether a call is adapted to, or
+// rejected by, the spec version of the node in SpecVersionCompatible mode.
+func versionDependent(method string, args []interface{}) bool {
+	if method == BatchMethod && len(args) == 1 {
+		if b, ok := args[0].([]ethrpc.BatchElem); ok {
+			for _, elem := range b {
+				if versionDependent(elem.Method, elem.Args) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return feeMethods[method] || transactionMethods[method] || v08Methods[method]
+}
+
+// isZeroHex reports whether v is a hex string of zero, or absent.
+func isZeroHex(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	s, ok := v.(string)
+	return ok && strings.TrimLeft(strings.TrimPrefix(s, "0x"), "0") == ""
+}
+
+// feeMethods are the methods whose result holds fee estimates.
+var feeMethods = map[string]bool{
+	"starknet_estimateFee":          true,
+	"starknet_estimateMessageFee":   true,
+	"starknet_simulateTransactions": true,
+}
+
+// adaptCall performs a call against a node of an older spec version. The
+// arguments are adapted or rejected by adaptArgs and the fee estimates are
+// completed with both the v0.7 and the v0.8 fields, whatever the version of
+// the node.
+//
+// Parameters:
+// - ctx: The context of the call
+// - next: The next CallFunc of the chain
+// - version: The spec version of the node
+// - result: The result of the call
+// - method: The method of the call
+// - args: The arguments of the call
+// Returns:
+// - error: the error of the call, if any
+func adaptCall(ctx context.Context, next CallFunc, version string, result interface{}, method string, args []interface{}) error {
+	if method == BatchMethod && len(args) == 1 {
+		if b, ok := args[0].([]ethrpc.BatchElem); ok {
+			return adaptBatch(ctx, next, version, b)
+		}
+	}
+	args, err := adaptArgs(version, method, args)
+	if err != nil {
+		return err
+	}
+	if !feeMethods[method] {
+		return next(ctx, result, method, args...)
+	}
+
+	raw, inPlace := result.(*json.RawMessage)
+	if !inPlace {
+		raw = new(json.RawMessage)
+	}
+	if err := next(ctx, raw, method, args...); err != nil {
+		return err
+	}
+	*raw = adaptFeeEstimates(*raw)
+	if inPlace || len(*raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(*raw, result)
+}
+
+// adaptBatch adapts the calls of a batch, see adaptCall. The rejected calls
+// get their error and the others are sent.
+func adaptBatch(ctx context.Context, next CallFunc, version string, b []ethrpc.BatchElem) error {
+	sent := make([]ethrpc.BatchElem, 0, len(b))
+	indexes := make([]int, 0, len(b))
+	for i := range b {
+		args, err := adaptArgs(version, b[i].Method, b[i].Args)
+		if err != nil {
+			b[i].Error = err
+			continue
+		}
+		b[i].Args = args
+		sent = append(sent, b[i])
+		indexes = append(indexes, i)
+	}
+	if len(sent) > 0 {
+		if err := next(ctx, nil, BatchMethod, sent); err != nil {
+			return err
+		}
+	}
+	for j, i := range indexes {
+		b[i].Error = sent[j].Error
+		if raw, ok := b[i].Result.(*json.RawMessage); ok && b[i].Error == nil && feeMethods[b[i].Method] {
+			*raw = adaptFeeEstimates(*raw)
+		}
+	}
+	return nil
+}
+
+// adaptArgs adapts the arguments of a call to the spec version of the node.
+// On the nodes older than v0.8, the calls relying on a v0.8 method or on
+// l2_gas resource bounds are rejected with a SpecFeatureError, and so are the
+// transactions sent with l1_data_gas resource bounds since the bounds are
+// part of their hash. The l1_data_gas resource bounds of the fee estimates
+// and the simulations are removed instead. Only the arguments of the fee and
+// transaction methods are inspected.
+//
+// Parameters:
+// - version: The spec version of the node
+// - method: The method of the call
+// - args: The arguments of the call
+// Returns:
+// - []interface{}: the adapted arguments
+// - error: a SpecFeatureError if the node can't perform the call
+func adaptArgs(version, method string, args []interface{}) ([]interface{}, error) {
+	if version == RPCSpecVersion {
+		return args, nil
+	}
+	if v08Methods[method] {
+		return nil, &SpecFeatureError{Feature: method, Version: version, Since: RPCSpecVersion}
+	}
+	if !feeMethods[method] && !transactionMethods[method] {
+		return args, nil
+	}
+	raw, err := json.Marshal(args)
+	if err != nil || !bytes.Contains(raw, []byte(`"resource_bounds"`)) {
+		return args, nil
+	}
+	var decoded []interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return args, nil
+	}
+	feature := ""
+	walkJSON(decoded, func(object map[string]interface{}) {
+		bounds, ok := object["resource_bounds"].(map[string]interface{})
+		if !ok {
+			return
+		}
+		// the nodes older than v0.8 only accept zero l2_gas bounds
+		if l2, ok := bounds["l2_gas"].(map[string]interface{}); ok && (!isZeroHex(l2["max_amount"]) || !isZeroHex(l2["max_price_per_unit"])) {
+			feature = "the l2_gas resource bounds"
+		}
+		if _, ok := bounds["l1_data_gas"]; ok {
+			if transactionMethods[method] {
+				feature = "the l1_data_gas resource bounds"
+			}
+			delete(bounds, "l1_data_gas")
+		}
+	})
+	if feature != "" {
+		return nil, &SpecFeatureError{Feature: feature, Version: version, Since: RPCSpecVersion}
+	}
+	adapted := make([]interface{}, len(decoded))
+	for i, arg := range decoded {
+		rawArg, err := json.Marshal(arg)
+		if err != nil {
+			return args, nil
+		}
+		adapted[i] = json.RawMessage(rawArg)
+	}
+	return adapted, nil
+}
+
+// adaptFeeEstimates fills the fields of the fee estimates that the version of
+// the node doesn't return: l1_gas_consumed and the other v0.8 fields from
+// gas_consumed and the v0.7 fields, and the other way around.
+func adaptFeeEstimates(raw json.RawMessage) json.RawMessage {
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return raw
+	}
+	walkJSON(decoded, func(object map[string]interface{}) {
+		if _, ok := object["overall_fee"]; !ok {
+			return
+		}
+		pairs := [][2]string{
+			{"gas_consumed", "l1_gas_consumed"},
+			{"gas_price", "l1_gas_price"},
+			{"data_gas_consumed", "l1_data_gas_consumed"},
+			{"data_gas_price", "l1_data_gas_price"},
+		}
+		for _, pair := range pairs {
+			legacy, legacyOk := object[pair[0]]
+			current, currentOk := object[pair[1]]
+			switch {
+			case legacyOk && !currentOk:
+				object[pair[1]] = legacy
+			case currentOk && !legacyOk:
+				object[pair[0]] = current
+			case !legacyOk && !currentOk:
+				// v0.6 has no data gas
+				object[pair[0]], object[pair[1]] = "0x0", "0x0"
+			}
+		}
+		for _, field := range []string{"l2_gas_consumed", "l2_gas_price"} {
+			if _, ok := object[field]; !ok {
+				object[field] = "0x0"
+			}
+		}
+	})
+	adapted, err := json.Marshal(decoded)
+	if err != nil {
+		return raw
+	}
+	return adapted
+}
+
+// walkJSON calls fn with every object of a decoded JSON value.
+func walkJSON(value interface{}, fn func(object map[string]interface{})) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		fn(v)
+		for _, field := range v {
+			walkJSON(field, fn)
+		}
+	case []interface{}:
+		for _, item := range v {
+			walkJSON(item, fn)
+		}
+	}
+}
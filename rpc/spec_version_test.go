@@ -0,0 +1,271 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// versionedNode is a callCloser answering with fixed JSON results and
+// recording the calls and the arguments it receives.
+type versionedNode struct {
+	answers map[string]string
+	calls   map[string]int
+	args    map[string]string
+}
+
+// CallContext records the call and unmarshals the answer of method into result.
+func (n *versionedNode) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if n.calls == nil {
+		n.calls, n.args = map[string]int{}, map[string]string{}
+	}
+	n.calls[method]++
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	n.args[method] = string(raw)
+	answer, ok := n.answers[method]
+	if !ok {
+		return errNotFound
+	}
+	return json.Unmarshal([]byte(answer), result)
+}
+
+// Close does nothing.
+func (n *versionedNode) Close() {}
+
+// TestSpecVersionCheck tests that the spec version is queried once and checked against the mode.
+func TestSpecVersionCheck(t *testing.T) {
+	type testSetType struct {
+		Mode          SpecVersionMode
+		Version       string
+		KnownVersion  string
+		ExpectedErr   bool
+		ExpectedCalls int
+	}
+	testSet := []testSetType{
+		{Mode: SpecVersionCompatible, Version: `"0.7.1"`, ExpectedCalls: 1},
+		{Mode: SpecVersionCompatible, Version: `"0.5.1"`, ExpectedErr: true, ExpectedCalls: 1},
+		{Mode: SpecVersionStrict, Version: `"0.7.1"`, ExpectedErr: true, ExpectedCalls: 1},
+		{Mode: SpecVersionStrict, Version: `"0.8.0"`, ExpectedCalls: 1},
+		{Mode: SpecVersionCompatible, KnownVersion: "0.6", ExpectedCalls: 0},
+		{Mode: SpecVersionUnchecked, Version: `"0.1.0"`, ExpectedCalls: 0},
+	}
+
+	for _, test := range testSet {
+		node := &versionedNode{answers: map[string]string{
+			"starknet_specVersion": test.Version,
+			"starknet_chainId":     `"0x534e5f4d41494e"`,
+			"starknet_getNonce":    `"0x2"`,
+			"starknet_estimateFee": `[]`,
+		}}
+		provider := &Provider{c: newProviderClient(node, providerConfig{specVersionMode: test.Mode, specVersion: test.KnownVersion})}
+
+		chainID, err := provider.ChainID(context.Background())
+		require.NoError(t, err, "chainId doesn't depend on the version")
+		require.Equal(t, "SN_MAIN", chainID)
+
+		// the nonce only depends on the version in strict mode
+		nonce, err := provider.Nonce(context.Background(), WithBlockTag("latest"), utils.TestHexToFelt(t, "0x1"))
+		if test.ExpectedErr && test.Mode == SpecVersionStrict {
+			require.ErrorIs(t, err.(*RPCError).Data.(error), ErrUnsupportedSpecVersion)
+		} else {
+			require.NoError(t, err)
+			require.Equal(t, utils.TestHexToFelt(t, "0x2"), nonce)
+		}
+
+		for i := 0; i < 2; i++ {
+			_, err := provider.EstimateFee(context.Background(), []BroadcastTxn{}, []SimulationFlag{}, WithBlockTag("latest"))
+			if test.ExpectedErr {
+				var versionErr *SpecVersionError
+				rpcErr, ok := err.(*RPCError)
+				require.True(t, ok)
+				cause, _ := rpcErr.Data.(error)
+				require.True(t, errors.As(cause, &versionErr))
+				require.ErrorIs(t, cause, ErrUnsupportedSpecVersion)
+				continue
+			}
+			require.NoError(t, err)
+		}
+		require.Equal(t, test.ExpectedCalls, node.calls["starknet_specVersion"])
+	}
+}
+
+// TestSpecVersionQuery tests that the concurrent calls wait for the query in
+// flight, and stop waiting when their context is done.
+func TestSpecVersionQuery(t *testing.T) {
+	release := make(chan struct{})
+	queries := 0
+	next := func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+		queries++
+		<-release
+		*result.(*string) = "0.7.1"
+		return nil
+	}
+	checker := &specVersionChecker{mode: SpecVersionCompatible}
+
+	versions := make(chan string)
+	go func() {
+		version, _ := checker.check(context.Background(), next)
+		versions <- version
+	}()
+	for {
+		checker.mu.Lock()
+		inFlight := checker.inFlight != nil
+		checker.mu.Unlock()
+		if inFlight {
+			break
+		}
+		runtime.Gosched()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := checker.check(ctx, next)
+	require.ErrorIs(t, err, context.Canceled)
+
+	go func() {
+		version, _ := checker.check(context.Background(), next)
+		versions <- version
+	}()
+	close(release)
+	require.Equal(t, "0.7", <-versions)
+	require.Equal(t, "0.7", <-versions)
+	require.Equal(t, 1, queries)
+}
+
+// TestSpecVersionFeatures tests that the v0.8 methods and l2_gas resource bounds are rejected by older nodes.
+func TestSpecVersionFeatures(t *testing.T) {
+	bounds := ResourceBoundsMapping{
+		L1Gas: ResourceBounds{MaxAmount: "0x1", MaxPricePerUnit: "0x2"},
+		L2Gas: ResourceBounds{MaxAmount: "0x3", MaxPricePerUnit: "0x4"},
+	}
+	txn := BroadcastInvokev3Txn{InvokeTxnV3: InvokeTxnV3{Type: TransactionType_Invoke, Version: TransactionV3, ResourceBounds: bounds}}
+	cause := func(err error) error {
+		if rpcErr, ok := err.(*RPCError); ok {
+			err, _ = rpcErr.Data.(error)
+		}
+		return err
+	}
+
+	for _, version := range []string{"0.7", "0.8"} {
+		node := &versionedNode{answers: map[string]string{
+			"starknet_getMessagesStatus":    `[]`,
+			"starknet_addInvokeTransaction": `{"transaction_hash": "0x1"}`,
+			"starknet_getNonce":             `"0x2"`,
+		}}
+		provider := &Provider{c: newProviderClient(node, providerConfig{specVersion: version})}
+
+		_, statusErr := provider.MessagesStatus(context.Background(), NumAsHex("0x1"))
+		_, invokeErr := provider.AddInvokeTransaction(context.Background(), txn)
+		if version == RPCSpecVersion {
+			require.NoError(t, statusErr)
+			require.NoError(t, invokeErr)
+			continue
+		}
+		var featureErr *SpecFeatureError
+		require.True(t, errors.As(cause(statusErr), &featureErr))
+		require.Equal(t, "starknet_getMessagesStatus", featureErr.Feature)
+		require.ErrorIs(t, cause(invokeErr), ErrUnsupportedByNode)
+		require.Zero(t, node.calls["starknet_getMessagesStatus"])
+		require.Zero(t, node.calls["starknet_addInvokeTransaction"])
+
+		// the rejected calls of a batch get their error, the others are sent
+		var response AddInvokeTransactionResponse
+		var nonce *felt.Felt
+		batch := provider.NewBatch()
+		invokeCall := batch.AddInvokeTransaction(txn, &response)
+		nonceCall := batch.Nonce(WithBlockTag("latest"), utils.TestHexToFelt(t, "0x1"), &nonce)
+		require.NoError(t, batch.Send(context.Background()))
+		require.ErrorIs(t, cause(invokeCall.Err()), ErrUnsupportedByNode)
+		require.NoError(t, nonceCall.Err())
+		require.Equal(t, utils.TestHexToFelt(t, "0x2"), nonce)
+		require.Zero(t, node.calls["starknet_addInvokeTransaction"])
+	}
+}
+
+// TestSpecVersionAdaptation tests the adaptation of the transactions and of the fee estimates to the spec version of the node.
+func TestSpecVersionAdaptation(t *testing.T) {
+	bounds := ResourceBoundsMapping{
+		L1Gas:     ResourceBounds{MaxAmount: "0x1", MaxPricePerUnit: "0x2"},
+		L2Gas:     ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		L1DataGas: &ResourceBounds{MaxAmount: "0x3", MaxPricePerUnit: "0x4"},
+	}
+	txn := BroadcastInvokev3Txn{InvokeTxnV3: InvokeTxnV3{Type: TransactionType_Invoke, Version: TransactionV3, ResourceBounds: bounds}}
+
+	type testSetType struct {
+		Version         string
+		FeeAnswer       string
+		ExpectedDataGas bool
+	}
+	testSet := []testSetType{
+		{
+			Version:   "0.6",
+			FeeAnswer: `[{"gas_consumed": "0x5", "gas_price": "0x6", "overall_fee": "0x1e", "unit": "FRI"}]`,
+		},
+		{
+			Version:   "0.7",
+			FeeAnswer: `[{"gas_consumed": "0x5", "gas_price": "0x6", "data_gas_consumed": "0x0", "data_gas_price": "0x1", "overall_fee": "0x1e", "unit": "FRI"}]`,
+		},
+		{
+			Version:         "0.8",
+			FeeAnswer:       `[{"l1_gas_consumed": "0x5", "l1_gas_price": "0x6", "l2_gas_consumed": "0x0", "l2_gas_price": "0x1", "l1_data_gas_consumed": "0x0", "l1_data_gas_price": "0x1", "overall_fee": "0x1e", "unit": "FRI"}]`,
+			ExpectedDataGas: true,
+		},
+	}
+
+	for _, test := range testSet {
+		node := &versionedNode{answers: map[string]string{
+			"starknet_estimateFee":          test.FeeAnswer,
+			"starknet_addInvokeTransaction": `{"transaction_hash": "0x1"}`,
+		}}
+		provider := &Provider{c: newProviderClient(node, providerConfig{specVersion: test.Version})}
+
+		// the l1_data_gas bounds are removed from the estimated transactions
+		estimates, err := provider.EstimateFee(context.Background(), []BroadcastTxn{txn}, []SimulationFlag{}, WithBlockTag("latest"))
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedDataGas, strings.Contains(node.args["starknet_estimateFee"], "l1_data_gas"))
+
+		// but not from the sent ones, whose hash covers them
+		_, err = provider.AddInvokeTransaction(context.Background(), txn)
+		if test.ExpectedDataGas {
+			require.NoError(t, err)
+			require.Contains(t, node.args["starknet_addInvokeTransaction"], "l1_data_gas")
+		} else {
+			var featureErr *SpecFeatureError
+			require.True(t, errors.As(err.(*RPCError).Data.(error), &featureErr))
+			require.Equal(t, "the l1_data_gas resource bounds", featureErr.Feature)
+			require.Zero(t, node.calls["starknet_addInvokeTransaction"])
+		}
+		require.Equal(t, utils.TestHexToFelt(t, "0x5"), estimates[0].GasConsumed)
+		require.Equal(t, utils.TestHexToFelt(t, "0x5"), estimates[0].L1GasConsumed)
+		require.Equal(t, utils.TestHexToFelt(t, "0x6"), estimates[0].L1GasPrice)
+		require.NotNil(t, estimates[0].DataGasConsumed)
+		require.NotNil(t, estimates[0].L1DataGasPrice)
+		require.NotNil(t, estimates[0].L2GasConsumed)
+
+		// the same adaptation applies to batches
+		var batchEstimates []FeeEstimate
+		batch := provider.NewBatch()
+		call := batch.EstimateFee([]BroadcastTxn{txn}, []SimulationFlag{}, WithBlockTag("latest"), &batchEstimates)
+		require.NoError(t, batch.Send(context.Background()))
+		require.NoError(t, call.Err())
+		require.Equal(t, estimates, batchEstimates)
+	}
+}
+
+// TestVersionedURL tests the versioned paths of the nodes.
+func TestVersionedURL(t *testing.T) {
+	require.Equal(t, "https://node.example/rpc/v0_7", VersionedURL("https://node.example/", "0.7.1"))
+	require.Equal(t, "0.7", specVersionFromURL("https://node.example/rpc/v0_7"))
+	require.Equal(t, "0.6", specVersionFromURL("http://localhost:9545/rpc/v0_6/"))
+	require.Equal(t, "", specVersionFromURL("https://node.example/rpc"))
+}
@@ -21,7 +21,17 @@ func (provider *Provider) TraceTransaction(ctx context.Context, transactionHash
 	if err := do(ctx, provider.c, "starknet_traceTransaction", &rawTxnTrace, transactionHash); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrHashNotFound, ErrNoTraceAvailable)
 	}
+	return typecastTxnTrace(rawTxnTrace)
+}
 
+// typecastTxnTrace typecasts the raw trace to the TxnTrace type matching its transaction type.
+//
+// Parameters:
+// - rawTxnTrace: The raw trace as returned by starknet_traceTransaction
+// Returns:
+// - TxnTrace: the transaction trace
+// - error: an error if the trace has an unknown type
+func typecastTxnTrace(rawTxnTrace map[string]any) (TxnTrace, error) {
 	rawTraceByte, err := json.Marshal(rawTxnTrace)
 	if err != nil {
 		return nil, Err(InternalError, err)
@@ -143,3 +143,20 @@ func (provider *Provider) GetTransactionStatus(ctx context.Context, transactionH
 	}
 	return &receipt, nil
 }
+
+// MessagesStatus returns the status of the L1 handler transactions of the
+// messages sent by an L1 transaction.
+//
+// Parameters:
+// - ctx: the context.Context object for cancellation and timeouts.
+// - transactionHash: the hash of the L1 transaction sending the messages
+// Returns:
+// - []MessageStatusResp: the status of each message, in the order they were sent
+// - error, if one arose.
+func (provider *Provider) MessagesStatus(ctx context.Context, transactionHash NumAsHex) ([]MessageStatusResp, error) {
+	var statuses []MessageStatusResp
+	if err := do(ctx, provider.c, "starknet_getMessagesStatus", &statuses, transactionHash); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrHashNotFound)
+	}
+	return statuses, nil
+}
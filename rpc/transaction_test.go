@@ -361,3 +361,22 @@ func TestGetTransactionStatus(t *testing.T) {
 		require.Equal(t, *resp, test.ExpectedResp)
 	}
 }
+
+// TestMessagesStatus tests the decoding of the starknet_getMessagesStatus result.
+func TestMessagesStatus(t *testing.T) {
+	provider := &Provider{c: rawAnswers{"starknet_getMessagesStatus": `[
+		{"transaction_hash": "0x1", "finality_status": "ACCEPTED_ON_L2", "execution_status": "SUCCEEDED"},
+		{"transaction_hash": "0x2", "finality_status": "ACCEPTED_ON_L2", "execution_status": "REVERTED", "failure_reason": "out of gas"}
+	]`}}
+
+	statuses, err := provider.MessagesStatus(context.Background(), NumAsHex("0x4d3f6e2a"))
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	require.Equal(t, TxnStatus_Accepted_On_L2, statuses[0].FinalityStatus)
+	require.Equal(t, TxnExecutionStatusREVERTED, statuses[1].ExecutionStatus)
+	require.Equal(t, "out of gas", statuses[1].FailureReason)
+
+	provider = &Provider{c: &scriptedErrors{errs: []error{ErrHashNotFound}}}
+	_, err = provider.MessagesStatus(context.Background(), NumAsHex("0x1"))
+	require.Equal(t, ErrHashNotFound, err)
+}
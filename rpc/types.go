@@ -200,6 +200,24 @@ type FeeEstimate struct {
 	// The data gas price (in wei or fri, depending on the tx version) that was used in the cost estimation.
 	DataGasPrice *felt.Felt `json:"data_gas_price"`
 
+	// The L1 gas consumption of the transaction, since v0.8
+	L1GasConsumed *felt.Felt `json:"l1_gas_consumed,omitempty"`
+
+	// The L1 gas price (in wei or fri, depending on the tx version) that was used in the cost estimation, since v0.8
+	L1GasPrice *felt.Felt `json:"l1_gas_price,omitempty"`
+
+	// The L2 gas consumption of the transaction, since v0.8
+	L2GasConsumed *felt.Felt `json:"l2_gas_consumed,omitempty"`
+
+	// The L2 gas price (in wei or fri, depending on the tx version) that was used in the cost estimation, since v0.8
+	L2GasPrice *felt.Felt `json:"l2_gas_price,omitempty"`
+
+	// The L1 data gas consumption of the transaction, since v0.8
+	L1DataGasConsumed *felt.Felt `json:"l1_data_gas_consumed,omitempty"`
+
+	// The L1 data gas price (in wei or fri, depending on the tx version) that was used in the cost estimation, since v0.8
+	L1DataGasPrice *felt.Felt `json:"l1_data_gas_price,omitempty"`
+
 	// The estimated fee for the transaction (in wei or fri, depending on the tx version), equals to gas_consumed*gas_price + data_gas_consumed*data_gas_price.
 	OverallFee *felt.Felt `json:"overall_fee"`
 
@@ -176,6 +176,8 @@ type BlockHeader struct {
 	L1GasPrice ResourcePrice `json:"l1_gas_price"`
 	// The price of l1 data gas in the block
 	L1DataGasPrice ResourcePrice `json:"l1_data_gas_price"`
+	// The price of l2 gas in the block, since v0.8
+	L2GasPrice *ResourcePrice `json:"l2_gas_price,omitempty"`
 	// Specifies whether the data of this block is published via blob data or calldata
 	L1DAMode L1DAMode `json:"l1_da_mode"`
 	// Semver of the current Starknet protocol
@@ -229,6 +231,8 @@ type PendingBlockHeader struct {
 	StarknetVersion string `json:"starknet_version"`
 	// The price of l1 data gas in the block
 	L1DataGasPrice ResourcePrice `json:"l1_data_gas_price"`
+	// The price of l2 gas in the block, since v0.8
+	L2GasPrice *ResourcePrice `json:"l2_gas_price,omitempty"`
 	// Specifies whether the data of this block is published via blob data or calldata
 	L1DAMode L1DAMode `json:"l1_da_mode"`
 }
@@ -0,0 +1,193 @@
+package rpc
+
+import "github.com/NethermindEth/juno/core/felt"
+
+// AnyBlockHeader is the header of either an accepted block or the pending
+// block. Its accessors return the fields common to BlockHeader and
+// PendingBlockHeader whichever the block is.
+type AnyBlockHeader struct {
+	header  *BlockHeader
+	pending *PendingBlockHeader
+}
+
+// Pending reports whether the block is the pending block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true for the pending block
+func (h AnyBlockHeader) Pending() bool {
+	return h.pending != nil
+}
+
+// Header returns the header of an accepted block, nil for the pending block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *BlockHeader: the header of the accepted block
+func (h AnyBlockHeader) Header() *BlockHeader {
+	return h.header
+}
+
+// PendingHeader returns the header of the pending block, nil for an accepted block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *PendingBlockHeader: the header of the pending block
+func (h AnyBlockHeader) PendingHeader() *PendingBlockHeader {
+	return h.pending
+}
+
+// ParentHash returns the hash of the parent of the block.
+func (h AnyBlockHeader) ParentHash() *felt.Felt {
+	if h.pending != nil {
+		return h.pending.ParentHash
+	}
+	return h.header.ParentHash
+}
+
+// Timestamp returns the time at which the block was created, in Unix time.
+func (h AnyBlockHeader) Timestamp() uint64 {
+	if h.pending != nil {
+		return h.pending.Timestamp
+	}
+	return h.header.Timestamp
+}
+
+// SequencerAddress returns the address of the sequencer of the block.
+func (h AnyBlockHeader) SequencerAddress() *felt.Felt {
+	if h.pending != nil {
+		return h.pending.SequencerAddress
+	}
+	return h.header.SequencerAddress
+}
+
+// L1GasPrice returns the price of L1 gas in the block.
+func (h AnyBlockHeader) L1GasPrice() ResourcePrice {
+	if h.pending != nil {
+		return h.pending.L1GasPrice
+	}
+	return h.header.L1GasPrice
+}
+
+// L1DataGasPrice returns the price of L1 data gas in the block.
+func (h AnyBlockHeader) L1DataGasPrice() ResourcePrice {
+	if h.pending != nil {
+		return h.pending.L1DataGasPrice
+	}
+	return h.header.L1DataGasPrice
+}
+
+// L1DAMode returns how the data of the block is published on L1.
+func (h AnyBlockHeader) L1DAMode() L1DAMode {
+	if h.pending != nil {
+		return h.pending.L1DAMode
+	}
+	return h.header.L1DAMode
+}
+
+// StarknetVersion returns the Starknet protocol version of the block.
+func (h AnyBlockHeader) StarknetVersion() string {
+	if h.pending != nil {
+		return h.pending.StarknetVersion
+	}
+	return h.header.StarknetVersion
+}
+
+// BlockTxHashesResult is the result of starknet_getBlockWithTxHashes. Exactly
+// one of Block and PendingBlock is set, as reported by Pending.
+type BlockTxHashesResult struct {
+	AnyBlockHeader
+	Block        *BlockTxHashes
+	PendingBlock *PendingBlockTxHashes
+}
+
+// Transactions returns the hashes of the transactions of the block.
+func (r *BlockTxHashesResult) Transactions() []*felt.Felt {
+	if r.PendingBlock != nil {
+		return r.PendingBlock.Transactions
+	}
+	return r.Block.Transactions
+}
+
+// Value returns the block as BlockWithTxHashes does, a *BlockTxHashes or a *PendingBlockTxHashes.
+func (r *BlockTxHashesResult) Value() interface{} {
+	if r.PendingBlock != nil {
+		return r.PendingBlock
+	}
+	return r.Block
+}
+
+// BlockResult is the result of starknet_getBlockWithTxs. Exactly one of
+// Block and PendingBlock is set, as reported by Pending.
+type BlockResult struct {
+	AnyBlockHeader
+	Block        *Block
+	PendingBlock *PendingBlock
+}
+
+// Transactions returns the transactions of the block.
+func (r *BlockResult) Transactions() BlockTransactions {
+	if r.PendingBlock != nil {
+		return r.PendingBlock.BlockTransactions
+	}
+	return r.Block.Transactions
+}
+
+// Value returns the block as BlockWithTxs does, a *Block or a *PendingBlock.
+func (r *BlockResult) Value() interface{} {
+	if r.PendingBlock != nil {
+		return r.PendingBlock
+	}
+	return r.Block
+}
+
+// BlockWithReceiptsResult is the result of starknet_getBlockWithReceipts.
+// Exactly one of Block and PendingBlock is set, as reported by Pending.
+type BlockWithReceiptsResult struct {
+	AnyBlockHeader
+	Block        *BlockWithReceipts
+	PendingBlock *PendingBlockWithReceipts
+}
+
+// Transactions returns the transactions of the block with their receipts.
+func (r *BlockWithReceiptsResult) Transactions() []TransactionWithReceipt {
+	if r.PendingBlock != nil {
+		return r.PendingBlock.Transactions
+	}
+	return r.Block.Transactions
+}
+
+// Value returns the block as BlockWithReceipts does, a *BlockWithReceipts or a *PendingBlockWithReceipts.
+func (r *BlockWithReceiptsResult) Value() interface{} {
+	if r.PendingBlock != nil {
+		return r.PendingBlock
+	}
+	return r.Block
+}
+
+// pendingHeaderOf returns the pending form of a header decoded as a BlockHeader.
+//
+// Parameters:
+// - header: The header of the pending block
+// Returns:
+// - PendingBlockHeader: the pending block header
+func pendingHeaderOf(header BlockHeader) PendingBlockHeader {
+	return PendingBlockHeader{
+		ParentHash:       header.ParentHash,
+		Timestamp:        header.Timestamp,
+		SequencerAddress: header.SequencerAddress,
+		L1GasPrice:       header.L1GasPrice,
+		StarknetVersion:  header.StarknetVersion,
+		L1DataGasPrice:   header.L1DataGasPrice,
+		L1DAMode:         header.L1DAMode,
+	}
+}
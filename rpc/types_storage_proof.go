@@ -0,0 +1,81 @@
+package rpc
+
+import "github.com/NethermindEth/juno/core/felt"
+
+// StorageProofInput is the input of starknet_getStorageProof.
+type StorageProofInput struct {
+	// The block to prove the state of, a hash, a number or "latest"
+	BlockID BlockID `json:"block_id"`
+	// The classes to prove the membership of in the classes trie
+	ClassHashes []*felt.Felt `json:"class_hashes,omitempty"`
+	// The contracts to prove the membership of in the contracts trie
+	ContractAddresses []*felt.Felt `json:"contract_addresses,omitempty"`
+	// The storage slots to prove the value of, per contract
+	ContractsStorageKeys []ContractStorageKeys `json:"contracts_storage_keys,omitempty"`
+}
+
+// ContractStorageKeys is a contract and the storage keys to prove.
+type ContractStorageKeys struct {
+	ContractAddress *felt.Felt   `json:"contract_address"`
+	StorageKeys     []*felt.Felt `json:"storage_keys"`
+}
+
+// StorageProof is the result of starknet_getStorageProof.
+type StorageProof struct {
+	// The nodes of the proofs of the requested classes
+	ClassesProof []NodeHashToNode `json:"classes_proof"`
+	// The nodes of the proofs of the requested contracts and their leaves
+	ContractsProof ContractsProof `json:"contracts_proof"`
+	// The nodes of the proofs of the requested storage keys, one list per contract
+	ContractsStorageProofs [][]NodeHashToNode `json:"contracts_storage_proofs"`
+	// The roots the proofs are relative to
+	GlobalRoots GlobalRoots `json:"global_roots"`
+}
+
+// ContractsProof is the proof of the requested contracts in the contracts trie.
+type ContractsProof struct {
+	Nodes []NodeHashToNode `json:"nodes"`
+	// The leaves of the requested contracts, in the order of the request
+	ContractLeavesData []ContractLeafData `json:"contract_leaves_data"`
+}
+
+// ContractLeafData is the data hashed in the leaf of a contract.
+type ContractLeafData struct {
+	Nonce       *felt.Felt `json:"nonce"`
+	ClassHash   *felt.Felt `json:"class_hash"`
+	StorageRoot *felt.Felt `json:"storage_root,omitempty"`
+}
+
+// GlobalRoots are the roots of the tries of a block.
+type GlobalRoots struct {
+	ContractsTreeRoot *felt.Felt `json:"contracts_tree_root"`
+	ClassesTreeRoot   *felt.Felt `json:"classes_tree_root"`
+	// The hash of the block the roots belong to
+	BlockHash *felt.Felt `json:"block_hash"`
+}
+
+// NodeHashToNode is a node of a Merkle-Patricia proof and its hash.
+type NodeHashToNode struct {
+	NodeHash *felt.Felt `json:"node_hash"`
+	Node     MerkleNode `json:"node"`
+}
+
+// MerkleNode is a node of a Merkle-Patricia trie, either a binary node, with
+// Left and Right set, or an edge node, with Path, Length and Child set.
+type MerkleNode struct {
+	// The hash of the left child of a binary node
+	Left *felt.Felt `json:"left,omitempty"`
+	// The hash of the right child of a binary node
+	Right *felt.Felt `json:"right,omitempty"`
+	// The path of an edge node
+	Path *felt.Felt `json:"path,omitempty"`
+	// The length of the path of an edge node, in bits
+	Length uint `json:"length,omitempty"`
+	// The hash of the child of an edge node
+	Child *felt.Felt `json:"child,omitempty"`
+}
+
+// IsEdge reports whether the node is an edge node.
+func (n MerkleNode) IsEdge() bool {
+	return n.Child != nil
+}
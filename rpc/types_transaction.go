@@ -147,6 +147,8 @@ type ResourceBoundsMapping struct {
 	L1Gas ResourceBounds `json:"l1_gas"`
 	// The max amount and max price per unit of L2 gas used in this tx
 	L2Gas ResourceBounds `json:"l2_gas"`
+	// The max amount and max price per unit of L1 blob gas used in this tx, since v0.8
+	L1DataGas *ResourceBounds `json:"l1_data_gas,omitempty"`
 }
 
 type DataAvailabilityMode string
@@ -171,6 +173,8 @@ type Resource string
 const (
 	ResourceL1Gas Resource = "L1_GAS"
 	ResourceL2Gas Resource = "L2_GAS"
+	// ResourceL1DataGas is the L1 data gas resource, bounded since v0.8
+	ResourceL1DataGas Resource = "L1_DATA"
 )
 
 type ResourceBounds struct {
@@ -314,6 +318,11 @@ func unmarshalTxn(t interface{}) (Transaction, error) {
 			err := remarshal(casted, &txn)
 			return txn, err
 		case TransactionType_DeployAccount:
+			if version, ok := casted["version"].(string); ok && (version == string(TransactionV3) || version == string(TransactionV3WithQueryBit)) {
+				var txn DeployAccountTxnV3
+				err := remarshal(casted, &txn)
+				return txn, err
+			}
 			var txn DeployAccountTxn
 			err := remarshal(casted, &txn)
 			return txn, err
@@ -157,6 +157,12 @@ func (er *ComputationResources) Validate() bool {
 type ExecutionResources struct {
 	ComputationResources
 	DataAvailability `json:"data_availability"`
+	// The L1 gas consumed by the transaction, since v0.8
+	L1Gas uint `json:"l1_gas,omitempty"`
+	// The L1 data gas consumed by the transaction, since v0.8
+	L1DataGas uint `json:"l1_data_gas,omitempty"`
+	// The L2 gas consumed by the transaction, since v0.8
+	L2Gas uint `json:"l2_gas,omitempty"`
 }
 
 type DataAvailability struct {
@@ -178,6 +184,18 @@ const (
 type TxnStatusResp struct {
 	ExecutionStatus TxnExecutionStatus `json:"execution_status,omitempty"`
 	FinalityStatus  TxnStatus          `json:"finality_status"`
+	// The failure reason, only when the execution status is REVERTED, since v0.8
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
+// MessageStatusResp is the status of the L1 handler transaction of a message sent from L1.
+type MessageStatusResp struct {
+	// The hash of the L1 handler transaction consuming the message
+	TransactionHash *felt.Felt         `json:"transaction_hash"`
+	FinalityStatus  TxnStatus          `json:"finality_status"`
+	ExecutionStatus TxnExecutionStatus `json:"execution_status,omitempty"`
+	// The failure reason, only when the execution status is REVERTED
+	FailureReason string `json:"failure_reason,omitempty"`
 }
 
 type TransactionReceiptWithBlockInfo struct {
@@ -0,0 +1,84 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// EventSubscriptionInput is the filter of an events subscription. It has the
+// same shape as EventFilter, without the upper bound since a subscription is
+// open ended.
+type EventSubscriptionInput struct {
+	// FromAddress filters events by the contract that emitted them
+	FromAddress *felt.Felt `json:"from_address,omitempty"`
+	// Keys the values used to filter the events
+	Keys [][]*felt.Felt `json:"keys,omitempty"`
+	// BlockID the block to start streaming events from, the latest block if nil
+	BlockID *BlockID `json:"block_id,omitempty"`
+}
+
+// PendingTxnsSubscriptionInput is the filter of a pending transactions subscription.
+type PendingTxnsSubscriptionInput struct {
+	// TransactionDetails requests the full transactions instead of their hashes only
+	TransactionDetails bool `json:"transaction_details,omitempty"`
+	// SenderAddress filters transactions by their sender address
+	SenderAddress []*felt.Felt `json:"sender_address,omitempty"`
+}
+
+// SubPendingTxns is a pending transaction notification. Only TransactionHash is
+// set unless the subscription asked for the transaction details.
+type SubPendingTxns struct {
+	TransactionHash *felt.Felt
+	Transaction     Transaction
+}
+
+// UnmarshalJSON unmarshals either a transaction hash or a full transaction into a SubPendingTxns.
+//
+// Parameters:
+// - data: The JSON data to be unmarshalled
+// Returns:
+// - error: An error if the unmarshalling process fails
+func (s *SubPendingTxns) UnmarshalJSON(data []byte) error {
+	var hash felt.Felt
+	if err := json.Unmarshal(data, &hash); err == nil {
+		*s = SubPendingTxns{TransactionHash: &hash}
+		return nil
+	}
+
+	var dec map[string]interface{}
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	txn, err := unmarshalTxn(dec)
+	if err != nil {
+		return err
+	}
+	var withHash struct {
+		TransactionHash *felt.Felt `json:"transaction_hash"`
+	}
+	if err := json.Unmarshal(data, &withHash); err != nil {
+		return err
+	}
+	*s = SubPendingTxns{TransactionHash: withHash.TransactionHash, Transaction: txn}
+	return nil
+}
+
+// NewTxnStatus is a transaction status notification.
+type NewTxnStatus struct {
+	TransactionHash *felt.Felt    `json:"transaction_hash"`
+	Status          TxnStatusResp `json:"status"`
+}
+
+// ReorgEvent is sent on every subscription when the node detects a chain
+// reorganization. The range covers the blocks that were orphaned.
+type ReorgEvent struct {
+	// StartBlockHash the hash of the first known block of the orphaned chain
+	StartBlockHash *felt.Felt `json:"starting_block_hash"`
+	// StartBlockNum the number of the first known block of the orphaned chain
+	StartBlockNum uint64 `json:"starting_block_number"`
+	// EndBlockHash the hash of the last known block of the orphaned chain
+	EndBlockHash *felt.Felt `json:"ending_block_hash"`
+	// EndBlockNum the number of the last known block of the orphaned chain
+	EndBlockNum uint64 `json:"ending_block_number"`
+}
@@ -0,0 +1,332 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+const (
+	subscriptionNewHeads            = "starknet_subscriptionNewHeads"
+	subscriptionEvents              = "starknet_subscriptionEvents"
+	subscriptionTransactionStatus   = "starknet_subscriptionTransactionStatus"
+	subscriptionPendingTransactions = "starknet_subscriptionPendingTransactions"
+	subscriptionReorg               = "starknet_subscriptionReorg"
+
+	// unsubscribeTimeout bounds the starknet_unsubscribe call made by Unsubscribe.
+	unsubscribeTimeout = 5 * time.Second
+	// maxSubscriptionQueue bounds the notifications queued for a subscriber
+	// that doesn't drain its channels.
+	maxSubscriptionQueue = 20000
+)
+
+// ErrSubscriptionQueueOverflow ends a subscription whose subscriber falls
+// behind the notifications by more than 20000 of them.
+var ErrSubscriptionQueueOverflow = errors.New("subscription queue overflow")
+
+// WsProvider provides the subscription API of the Starknet JSON-RPC
+// specification over a websocket connection.
+type WsProvider struct {
+	c *wsClient
+}
+
+// NewWebsocketProvider connects to the websocket endpoint of a Starknet node.
+//
+// Parameters:
+// - url: the ws:// or wss:// url of the node
+// Returns:
+// - *WsProvider: a new WsProvider
+// - error: an error if the connection could not be established
+func NewWebsocketProvider(url string) (*WsProvider, error) {
+	c, err := dialWs(context.Background(), url)
+	if err != nil {
+		return nil, err
+	}
+	return &WsProvider{c: c}, nil
+}
+
+// Close closes the websocket connection. Every active subscription ends and
+// reports the closure on its error channel.
+func (provider *WsProvider) Close() {
+	provider.c.Close()
+}
+
+// SubscribeNewHeads creates a stream of the headers of new blocks.
+//
+// Parameters:
+// - ctx: The context.Context used for the subscription request
+// - blockID: The block to start streaming from, the latest block if nil
+// Returns:
+// - <-chan *BlockHeader: The new block headers, closed when the subscription ends
+// - *ClientSubscription: The subscription, used to read errors and to unsubscribe
+// - error: An error, if any
+func (provider *WsProvider) SubscribeNewHeads(ctx context.Context, blockID *BlockID) (<-chan *BlockHeader, *ClientSubscription, error) {
+	params := map[string]interface{}{}
+	if blockID != nil {
+		params["block_id"] = blockID
+	}
+	heads := make(chan *BlockHeader)
+	sub, err := subscribe(ctx, provider.c, "starknet_subscribeNewHeads", params, subscriptionNewHeads, heads)
+	if err != nil {
+		return nil, nil, tryUnwrapToRPCErr(err, ErrTooManyBlocksBack, ErrBlockNotFound)
+	}
+	return heads, sub, nil
+}
+
+// SubscribeEvents creates a stream of the events matching the given filter.
+//
+// Parameters:
+// - ctx: The context.Context used for the subscription request
+// - input: The filter of the events to stream
+// Returns:
+// - <-chan *EmittedEvent: The matching events, closed when the subscription ends
+// - *ClientSubscription: The subscription, used to read errors and to unsubscribe
+// - error: An error, if any
+func (provider *WsProvider) SubscribeEvents(ctx context.Context, input EventSubscriptionInput) (<-chan *EmittedEvent, *ClientSubscription, error) {
+	events := make(chan *EmittedEvent)
+	sub, err := subscribe(ctx, provider.c, "starknet_subscribeEvents", input, subscriptionEvents, events)
+	if err != nil {
+		return nil, nil, tryUnwrapToRPCErr(err, ErrTooManyKeysInFilter, ErrTooManyBlocksBack, ErrBlockNotFound)
+	}
+	return events, sub, nil
+}
+
+// SubscribeTransactionStatus creates a stream of the status updates of a transaction.
+//
+// Parameters:
+// - ctx: The context.Context used for the subscription request
+// - transactionHash: The hash of the transaction to follow
+// Returns:
+// - <-chan *NewTxnStatus: The status updates, closed when the subscription ends
+// - *ClientSubscription: The subscription, used to read errors and to unsubscribe
+// - error: An error, if any
+func (provider *WsProvider) SubscribeTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (<-chan *NewTxnStatus, *ClientSubscription, error) {
+	params := map[string]interface{}{"transaction_hash": transactionHash}
+	statuses := make(chan *NewTxnStatus)
+	sub, err := subscribe(ctx, provider.c, "starknet_subscribeTransactionStatus", params, subscriptionTransactionStatus, statuses)
+	if err != nil {
+		return nil, nil, tryUnwrapToRPCErr(err)
+	}
+	return statuses, sub, nil
+}
+
+// SubscribePendingTransactions creates a stream of the transactions added to the pending block.
+//
+// Parameters:
+// - ctx: The context.Context used for the subscription request
+// - input: The filter of the pending transactions to stream
+// Returns:
+// - <-chan *SubPendingTxns: The pending transactions, closed when the subscription ends
+// - *ClientSubscription: The subscription, used to read errors and to unsubscribe
+// - error: An error, if any
+func (provider *WsProvider) SubscribePendingTransactions(ctx context.Context, input PendingTxnsSubscriptionInput) (<-chan *SubPendingTxns, *ClientSubscription, error) {
+	txns := make(chan *SubPendingTxns)
+	sub, err := subscribe(ctx, provider.c, "starknet_subscribePendingTransactions", input, subscriptionPendingTransactions, txns)
+	if err != nil {
+		return nil, nil, tryUnwrapToRPCErr(err, ErrTooManyAddressesInFilter)
+	}
+	return txns, sub, nil
+}
+
+// subscribe sends the subscription request and starts forwarding the
+// notifications of the given method to out.
+func subscribe[T any](ctx context.Context, c *wsClient, method string, params interface{}, notification string, out chan *T) (*ClientSubscription, error) {
+	sub := newClientSubscription(c, notification, func(raw json.RawMessage, quit <-chan struct{}) error {
+		var v T
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		select {
+		case out <- &v:
+		case <-quit:
+		}
+		return nil
+	}, func() { close(out) })
+
+	var id json.RawMessage
+	if err := c.call(ctx, &id, method, params, sub); err != nil {
+		// the node may have answered after ctx expired
+		c.dropSubscription(sub)
+		return nil, err
+	}
+	go sub.forward()
+	return sub, nil
+}
+
+// ClientSubscription is an active subscription created through a WsProvider.
+type ClientSubscription struct {
+	client       *wsClient
+	id           string
+	rawID        json.RawMessage
+	notification string
+
+	mu     sync.Mutex
+	queue  []wsQueued
+	notify chan struct{}
+
+	deliver  func(raw json.RawMessage, quit <-chan struct{}) error
+	closeOut func()
+
+	reorg chan *ReorgEvent
+	err   chan error
+
+	// quit is closed by Unsubscribe, failed when the subscription breaks
+	quit     chan struct{}
+	quitOnce sync.Once
+	failed   chan struct{}
+	failOnce sync.Once
+	failErr  error
+}
+
+// wsQueued is a notification waiting to be delivered to the subscriber.
+type wsQueued struct {
+	method string
+	result json.RawMessage
+}
+
+// newClientSubscription creates a subscription that is not yet registered on the client.
+func newClientSubscription(c *wsClient, notification string, deliver func(json.RawMessage, <-chan struct{}) error, closeOut func()) *ClientSubscription {
+	return &ClientSubscription{
+		client:       c,
+		notification: notification,
+		notify:       make(chan struct{}, 1),
+		deliver:      deliver,
+		closeOut:     closeOut,
+		reorg:        make(chan *ReorgEvent),
+		err:          make(chan error, 1),
+		quit:         make(chan struct{}),
+		failed:       make(chan struct{}),
+	}
+}
+
+// ID returns the subscription id assigned by the node.
+func (sub *ClientSubscription) ID() string {
+	return sub.id
+}
+
+// Err returns the error channel of the subscription. It receives a value if
+// the subscription fails, for instance when the connection drops, and is
+// closed once the subscription has ended.
+func (sub *ClientSubscription) Err() <-chan error {
+	return sub.err
+}
+
+// Reorg returns the channel that receives the chain reorganizations reported
+// by the node for this subscription. It must be drained like the data channel.
+func (sub *ClientSubscription) Reorg() <-chan *ReorgEvent {
+	return sub.reorg
+}
+
+// Unsubscribe ends the subscription and tells the node to stop sending
+// notifications. The data and error channels are closed afterwards. It is safe
+// to call Unsubscribe more than once.
+func (sub *ClientSubscription) Unsubscribe() {
+	sub.quitOnce.Do(func() {
+		sub.client.removeSubscription(sub.id)
+		close(sub.quit)
+
+		select {
+		case <-sub.failed:
+			return
+		default:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), unsubscribeTimeout)
+		defer cancel()
+		var ok bool
+		_ = sub.client.call(ctx, &ok, "starknet_unsubscribe", map[string]interface{}{"subscription_id": sub.rawID}, nil)
+	})
+}
+
+// finish ends the subscription because of err without notifying the node.
+// The notifications received so far are still delivered.
+func (sub *ClientSubscription) finish(err error) {
+	sub.failOnce.Do(func() {
+		sub.failErr = err
+		close(sub.failed)
+	})
+}
+
+// enqueue stores a notification until the forwarding goroutine delivers it.
+// The subscription ends with ErrSubscriptionQueueOverflow when the queue is full.
+func (sub *ClientSubscription) enqueue(method string, result json.RawMessage) {
+	sub.mu.Lock()
+	if len(sub.queue) >= maxSubscriptionQueue {
+		sub.mu.Unlock()
+		sub.client.removeSubscription(sub.id)
+		sub.finish(ErrSubscriptionQueueOverflow)
+		return
+	}
+	sub.queue = append(sub.queue, wsQueued{method: method, result: result})
+	sub.mu.Unlock()
+	select {
+	case sub.notify <- struct{}{}:
+	default:
+	}
+}
+
+// forward delivers the queued notifications in order until the subscription ends.
+func (sub *ClientSubscription) forward() {
+	defer func() {
+		select {
+		case <-sub.failed:
+		default:
+			// settle failErr so that a late connection failure cannot race with the read below
+			sub.finish(nil)
+		}
+		if sub.failErr != nil {
+			sub.err <- sub.failErr
+		}
+		close(sub.err)
+		close(sub.reorg)
+		sub.closeOut()
+	}()
+
+	for {
+		failed := false
+		select {
+		case <-sub.quit:
+			return
+		case <-sub.notify:
+		case <-sub.failed:
+			failed = true
+		}
+
+		sub.mu.Lock()
+		queue := sub.queue
+		sub.queue = nil
+		sub.mu.Unlock()
+
+		for _, n := range queue {
+			if err := sub.dispatch(n); err != nil {
+				sub.client.removeSubscription(sub.id)
+				sub.finish(err)
+				return
+			}
+		}
+		if failed {
+			return
+		}
+	}
+}
+
+// dispatch hands a single notification to the subscriber.
+func (sub *ClientSubscription) dispatch(n wsQueued) error {
+	switch n.method {
+	case sub.notification:
+		return sub.deliver(n.result, sub.quit)
+	case subscriptionReorg:
+		var reorg ReorgEvent
+		if err := json.Unmarshal(n.result, &reorg); err != nil {
+			return err
+		}
+		select {
+		case sub.reorg <- &reorg:
+		case <-sub.quit:
+		}
+	}
+	return nil
+}
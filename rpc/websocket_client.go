@@ -0,0 +1,275 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	errWsClosed              = errors.New("websocket connection closed")
+	errInvalidNotificationID = errors.New("invalid subscription id in notification")
+)
+
+// wsMessage is a JSON-RPC 2.0 message exchanged over the websocket. It is
+// either a response to a call (ID set) or a notification (Method set).
+type wsMessage struct {
+	Version string          `json:"jsonrpc"`
+	ID      *uint64         `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// wsNotificationParams is the content of the params field of a subscription notification.
+type wsNotificationParams struct {
+	SubscriptionID json.RawMessage `json:"subscription_id"`
+	Result         json.RawMessage `json:"result"`
+}
+
+// wsCall is an in-flight request waiting for its response.
+type wsCall struct {
+	resp chan *wsMessage
+	// sub, when set, is registered by the read loop as soon as the
+	// subscription id is known, so that no notification can be lost.
+	sub *ClientSubscription
+}
+
+// wsClient is a minimal JSON-RPC 2.0 client over a websocket connection that
+// understands Starknet subscription notifications.
+type wsClient struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]*wsCall
+	subs    map[string]*ClientSubscription
+	err     error
+	closing bool
+
+	closed chan struct{}
+}
+
+// dialWs opens a websocket connection to the given url and starts reading from it.
+//
+// Parameters:
+// - ctx: the context used while dialing
+// - url: the ws:// or wss:// endpoint of the node
+// Returns:
+// - *wsClient: the connected client
+// - error: an error if the connection could not be established
+func dialWs(ctx context.Context, url string) (*wsClient, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	c := &wsClient{
+		conn:    conn,
+		pending: make(map[uint64]*wsCall),
+		subs:    make(map[string]*ClientSubscription),
+		closed:  make(chan struct{}),
+	}
+	go c.read()
+	return c, nil
+}
+
+// Close closes the underlying connection and ends every active subscription.
+func (c *wsClient) Close() {
+	c.mu.Lock()
+	c.closing = true
+	c.mu.Unlock()
+	c.conn.Close()
+	<-c.closed
+}
+
+// CallContext sends a request over the websocket and waits for its response.
+//
+// Parameters:
+// - ctx: the context of the call
+// - result: the destination the result is unmarshalled into
+// - method: the JSON-RPC method name
+// - args: the positional parameters of the call
+// Returns:
+// - error: an error if any occurred during the call
+func (c *wsClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if args == nil {
+		args = []interface{}{}
+	}
+	return c.call(ctx, result, method, args, nil)
+}
+
+// call sends a request with the given params and waits for the response. When
+// sub is not nil, it is registered under the id returned by the node.
+func (c *wsClient) call(ctx context.Context, result interface{}, method string, params interface{}, sub *ClientSubscription) error {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return c.err
+	}
+	c.nextID++
+	id := c.nextID
+	call := &wsCall{resp: make(chan *wsMessage, 1), sub: sub}
+	c.pending[id] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	msg := wsMessage{Version: "2.0", ID: &id, Method: method, Params: rawParams}
+	if err := c.write(ctx, &msg); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.closed:
+		return c.connErr()
+	case resp := <-call.resp:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, result)
+	}
+}
+
+// write sends a message over the websocket, honouring the context deadline.
+func (c *wsClient) write(ctx context.Context, msg *wsMessage) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := c.conn.SetWriteDeadline(deadline); err != nil {
+			return err
+		}
+		defer c.conn.SetWriteDeadline(time.Time{}) //nolint:errcheck
+	}
+	return c.conn.WriteJSON(msg)
+}
+
+// read is the receive loop of the client. It dispatches responses to the
+// waiting calls and notifications to their subscriptions.
+func (c *wsClient) read() {
+	var err error
+	for {
+		var msg wsMessage
+		if err = c.conn.ReadJSON(&msg); err != nil {
+			break
+		}
+		switch {
+		case msg.ID != nil:
+			c.handleResponse(&msg)
+		case msg.Method != "":
+			c.handleNotification(&msg)
+		}
+	}
+	c.fail(err)
+}
+
+// handleResponse hands a response to the matching pending call.
+func (c *wsClient) handleResponse(msg *wsMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	call, ok := c.pending[*msg.ID]
+	if !ok {
+		return
+	}
+	if call.sub != nil && msg.Error == nil {
+		if id, err := subscriptionID(msg.Result); err == nil {
+			call.sub.id = id
+			call.sub.rawID = msg.Result
+			c.subs[id] = call.sub
+		}
+	}
+	call.resp <- msg
+}
+
+// handleNotification queues a notification on the subscription it belongs to.
+func (c *wsClient) handleNotification(msg *wsMessage) {
+	var params wsNotificationParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return
+	}
+	id, err := subscriptionID(params.SubscriptionID)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	sub, ok := c.subs[id]
+	c.mu.Unlock()
+	if ok {
+		sub.enqueue(msg.Method, params.Result)
+	}
+}
+
+// fail records the error that ended the connection and terminates every subscription.
+func (c *wsClient) fail(err error) {
+	c.mu.Lock()
+	if c.closing || err == nil || websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		err = errWsClosed
+	}
+	c.err = err
+	subs := c.subs
+	c.subs = make(map[string]*ClientSubscription)
+	c.mu.Unlock()
+
+	close(c.closed)
+	for _, sub := range subs {
+		sub.finish(err)
+	}
+}
+
+// connErr returns the error that closed the connection.
+func (c *wsClient) connErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// removeSubscription forgets a subscription so that later notifications are dropped.
+func (c *wsClient) removeSubscription(id string) {
+	c.mu.Lock()
+	delete(c.subs, id)
+	c.mu.Unlock()
+}
+
+// dropSubscription forgets a subscription whose creation failed, if the read
+// loop registered it anyway.
+func (c *wsClient) dropSubscription(sub *ClientSubscription) {
+	c.mu.Lock()
+	if sub.id != "" && c.subs[sub.id] == sub {
+		delete(c.subs, sub.id)
+	}
+	c.mu.Unlock()
+}
+
+// subscriptionID normalises a subscription id, which nodes send either as a
+// JSON string or as a JSON number.
+func subscriptionID(raw json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	var n uint64
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return strconv.FormatUint(n, 10), nil
+	}
+	return "", errInvalidNotificationID
+}
@@ -0,0 +1,202 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// wsTestNode is a websocket Starknet node stub. handle is called for every
+// request and may write any number of messages to the connection.
+type wsTestNode struct {
+	server   *httptest.Server
+	requests chan wsMessage
+}
+
+// newWsTestNode starts a websocket node stub that calls handle for every request it receives.
+//
+// Parameters:
+// - t: The testing.T object for testing purposes
+// - handle: The function answering the requests
+// Returns:
+// - *wsTestNode: the running node stub
+func newWsTestNode(t *testing.T, handle func(conn *websocket.Conn, req wsMessage)) *wsTestNode {
+	t.Helper()
+	node := &wsTestNode{requests: make(chan wsMessage, 16)}
+	upgrader := websocket.Upgrader{}
+	node.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var req wsMessage
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			node.requests <- req
+			handle(conn, req)
+		}
+	}))
+	t.Cleanup(node.server.Close)
+	return node
+}
+
+// url returns the ws:// url of the node stub.
+func (node *wsTestNode) url() string {
+	return "ws" + strings.TrimPrefix(node.server.URL, "http")
+}
+
+// wsResult writes a successful response to req.
+func wsResult(t *testing.T, conn *websocket.Conn, req wsMessage, result string) {
+	require.NoError(t, conn.WriteJSON(wsMessage{Version: "2.0", ID: req.ID, Result: json.RawMessage(result)}))
+}
+
+// wsNotify writes a subscription notification.
+func wsNotify(t *testing.T, conn *websocket.Conn, method, subID, result string) {
+	params := `{"subscription_id":` + subID + `,"result":` + result + `}`
+	require.NoError(t, conn.WriteJSON(wsMessage{Version: "2.0", Method: method, Params: json.RawMessage(params)}))
+}
+
+// TestSubscribeNewHeads tests that headers are delivered in order, that reorgs
+// are reported and that Unsubscribe notifies the node and closes the channels.
+func TestSubscribeNewHeads(t *testing.T) {
+	node := newWsTestNode(t, func(conn *websocket.Conn, req wsMessage) {
+		switch req.Method {
+		case "starknet_subscribeNewHeads":
+			wsResult(t, conn, req, `"42"`)
+			wsNotify(t, conn, subscriptionNewHeads, `"42"`, `{"block_hash":"0x1","parent_hash":"0x0","block_number":1}`)
+			wsNotify(t, conn, subscriptionReorg, `"42"`, `{"starting_block_hash":"0x1","starting_block_number":1,"ending_block_hash":"0x1","ending_block_number":1}`)
+			wsNotify(t, conn, subscriptionNewHeads, `"42"`, `{"block_hash":"0x2","parent_hash":"0x0","block_number":1}`)
+		case "starknet_unsubscribe":
+			wsResult(t, conn, req, `true`)
+		}
+	})
+
+	provider, err := NewWebsocketProvider(node.url())
+	require.NoError(t, err)
+	defer provider.Close()
+
+	heads, sub, err := provider.SubscribeNewHeads(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, "42", sub.ID())
+
+	req := <-node.requests
+	require.Equal(t, "starknet_subscribeNewHeads", req.Method)
+	require.JSONEq(t, `{}`, string(req.Params))
+
+	head := <-heads
+	require.Equal(t, "0x1", head.BlockHash.String())
+	reorg := <-sub.Reorg()
+	require.Equal(t, uint64(1), reorg.StartBlockNum)
+	head = <-heads
+	require.Equal(t, "0x2", head.BlockHash.String())
+
+	sub.Unsubscribe()
+	req = <-node.requests
+	require.Equal(t, "starknet_unsubscribe", req.Method)
+	require.JSONEq(t, `{"subscription_id":"42"}`, string(req.Params))
+
+	_, ok := <-heads
+	require.False(t, ok)
+	_, ok = <-sub.Err()
+	require.False(t, ok)
+}
+
+// TestSubscribeEventsError tests that subscription errors are mapped to the Starknet RPC errors.
+func TestSubscribeEventsError(t *testing.T) {
+	node := newWsTestNode(t, func(conn *websocket.Conn, req wsMessage) {
+		require.NoError(t, conn.WriteJSON(wsMessage{Version: "2.0", ID: req.ID, Error: ErrTooManyBlocksBack}))
+	})
+
+	provider, err := NewWebsocketProvider(node.url())
+	require.NoError(t, err)
+	defer provider.Close()
+
+	blockID := WithBlockNumber(1)
+	_, _, err = provider.SubscribeEvents(context.Background(), EventSubscriptionInput{
+		FromAddress: utils.TestHexToFelt(t, "0x1"),
+		BlockID:     &blockID,
+	})
+	require.Error(t, err)
+	require.Equal(t, ErrTooManyBlocksBack.Code, err.(*RPCError).Code)
+
+	req := <-node.requests
+	require.JSONEq(t, `{"from_address":"0x1","block_id":{"block_number":1}}`, string(req.Params))
+}
+
+// TestSubscribeTransactionStatusConnectionLost tests that a dropped connection is reported on the error channel.
+func TestSubscribeTransactionStatusConnectionLost(t *testing.T) {
+	node := newWsTestNode(t, func(conn *websocket.Conn, req wsMessage) {
+		wsResult(t, conn, req, `1`)
+		wsNotify(t, conn, subscriptionTransactionStatus, `1`, `{"transaction_hash":"0xabc","status":{"finality_status":"RECEIVED"}}`)
+		conn.Close()
+	})
+
+	provider, err := NewWebsocketProvider(node.url())
+	require.NoError(t, err)
+	defer provider.Close()
+
+	statuses, sub, err := provider.SubscribeTransactionStatus(context.Background(), utils.TestHexToFelt(t, "0xabc"))
+	require.NoError(t, err)
+
+	status := <-statuses
+	require.Equal(t, TxnStatus_Received, status.Status.FinalityStatus)
+
+	select {
+	case err := <-sub.Err():
+		require.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the subscription to fail")
+	}
+	_, ok := <-statuses
+	require.False(t, ok)
+}
+
+// TestSubscriptionQueueOverflow tests that a subscriber falling too far behind
+// gets the queued notifications and then the overflow error.
+func TestSubscriptionQueueOverflow(t *testing.T) {
+	delivered := 0
+	sub := newClientSubscription(&wsClient{}, subscriptionNewHeads, func(json.RawMessage, <-chan struct{}) error {
+		delivered++
+		return nil
+	}, func() {})
+	for i := 0; i <= maxSubscriptionQueue; i++ {
+		sub.enqueue(subscriptionNewHeads, json.RawMessage(`{}`))
+	}
+	go sub.forward()
+
+	select {
+	case err := <-sub.Err():
+		require.ErrorIs(t, err, ErrSubscriptionQueueOverflow)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the subscription to overflow")
+	}
+	require.Equal(t, maxSubscriptionQueue, delivered)
+}
+
+// TestSubPendingTxnsUnmarshal tests that both notification shapes of the pending transactions subscription are decoded.
+func TestSubPendingTxnsUnmarshal(t *testing.T) {
+	var hashOnly SubPendingTxns
+	require.NoError(t, json.Unmarshal([]byte(`"0x123"`), &hashOnly))
+	require.Equal(t, "0x123", hashOnly.TransactionHash.String())
+	require.Nil(t, hashOnly.Transaction)
+
+	var detailed SubPendingTxns
+	txn := `{"transaction_hash":"0x123","type":"INVOKE","version":"0x1","max_fee":"0x1","nonce":"0x2","sender_address":"0x3","signature":[],"calldata":["0x4"]}`
+	require.NoError(t, json.Unmarshal([]byte(txn), &detailed))
+	require.Equal(t, "0x123", detailed.TransactionHash.String())
+	invoke, ok := detailed.Transaction.(InvokeTxnV1)
+	require.True(t, ok)
+	require.Equal(t, new(felt.Felt).SetUint64(3), invoke.SenderAddress)
+}
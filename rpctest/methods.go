@@ -0,0 +1,581 @@
+package rpctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// methodFunc performs a method against the seeded data, with the server locked.
+type methodFunc func(s *Server, params []json.RawMessage) (interface{}, error)
+
+// methods are the methods answered from the seeded data.
+var methods = map[string]methodFunc{
+	"starknet_specVersion":                     (*Server).specVersionMethod,
+	"starknet_chainId":                         (*Server).chainIDMethod,
+	"starknet_syncing":                         (*Server).syncing,
+	"starknet_blockNumber":                     (*Server).blockNumber,
+	"starknet_blockHashAndNumber":              (*Server).blockHashAndNumber,
+	"starknet_getBlockWithTxHashes":            (*Server).blockWithTxHashes,
+	"starknet_getBlockWithTxs":                 (*Server).blockWithTxs,
+	"starknet_getBlockWithReceipts":            (*Server).blockWithReceipts,
+	"starknet_getBlockTransactionCount":        (*Server).blockTransactionCount,
+	"starknet_getStateUpdate":                  (*Server).stateUpdate,
+	"starknet_getTransactionByHash":            (*Server).transactionByHash,
+	"starknet_getTransactionByBlockIdAndIndex": (*Server).transactionByBlockIDAndIndex,
+	"starknet_getTransactionReceipt":           (*Server).transactionReceipt,
+	"starknet_getTransactionStatus":            (*Server).transactionStatus,
+	"starknet_getStorageAt":                    (*Server).storageAt,
+	"starknet_getNonce":                        (*Server).nonce,
+	"starknet_getClassHashAt":                  (*Server).classHashAt,
+	"starknet_getClass":                        (*Server).class,
+	"starknet_getClassAt":                      (*Server).classAt,
+	"starknet_getCompiledCasm":                 (*Server).compiledCasm,
+	"starknet_getEvents":                       (*Server).events,
+}
+
+// invalidParams returns the error of a request with invalid parameters.
+func invalidParams(err error) *rpc.RPCError {
+	return &rpc.RPCError{Code: rpc.InvalidParams, Message: "Invalid params", Data: err.Error()}
+}
+
+// decodeParams decodes the positional parameters of a request into targets.
+func decodeParams(params []json.RawMessage, targets ...interface{}) error {
+	if len(params) < len(targets) {
+		return invalidParams(fmt.Errorf("expected %d parameters, got %d", len(targets), len(params)))
+	}
+	for i, target := range targets {
+		if err := json.Unmarshal(params[i], target); err != nil {
+			return invalidParams(fmt.Errorf("parameter %d: %w", i, err))
+		}
+	}
+	return nil
+}
+
+// blockID is a block id parameter, a tag or an object with a number or a hash.
+type blockID struct {
+	tag    string
+	number *uint64
+	hash   *felt.Felt
+}
+
+// UnmarshalJSON decodes a block id.
+func (b *blockID) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		return json.Unmarshal(data, &b.tag)
+	}
+	var id struct {
+		Number *uint64    `json:"block_number"`
+		Hash   *felt.Felt `json:"block_hash"`
+	}
+	if err := json.Unmarshal(data, &id); err != nil {
+		return err
+	}
+	b.number, b.hash = id.Number, id.Hash
+	return nil
+}
+
+// block returns the block of a block id, the pending block for the
+// "pending" tag.
+func (s *Server) block(id blockID) (*Block, error) {
+	switch {
+	case id.tag == "latest":
+		if len(s.blocks) == 0 {
+			return nil, rpc.ErrBlockNotFound
+		}
+		return s.blocks[len(s.blocks)-1], nil
+	case id.tag == "pending":
+		if s.pending == nil {
+			return nil, rpc.ErrBlockNotFound
+		}
+		return s.pending, nil
+	case id.number != nil:
+		if *id.number >= uint64(len(s.blocks)) {
+			return nil, rpc.ErrBlockNotFound
+		}
+		return s.blocks[*id.number], nil
+	case id.hash != nil:
+		for _, block := range s.blocks {
+			if block.Header.BlockHash.Equal(id.hash) {
+				return block, nil
+			}
+		}
+		return nil, rpc.ErrBlockNotFound
+	}
+	return nil, invalidParams(fmt.Errorf("invalid block id"))
+}
+
+// blockParam returns the block of the block id parameter at index.
+func (s *Server) blockParam(params []json.RawMessage, index int) (*Block, error) {
+	if len(params) <= index {
+		return nil, invalidParams(fmt.Errorf("missing block id"))
+	}
+	var id blockID
+	if err := json.Unmarshal(params[index], &id); err != nil {
+		return nil, invalidParams(err)
+	}
+	return s.block(id)
+}
+
+// pendingHeader returns the header of the pending block.
+func (s *Server) pendingHeader() rpc.PendingBlockHeader {
+	header := s.pending.Header
+	parent := new(felt.Felt)
+	if len(s.blocks) > 0 {
+		parent = s.blocks[len(s.blocks)-1].Header.BlockHash
+	}
+	return rpc.PendingBlockHeader{
+		ParentHash:       parent,
+		Timestamp:        header.Timestamp,
+		SequencerAddress: header.SequencerAddress,
+		L1GasPrice:       header.L1GasPrice,
+		StarknetVersion:  header.StarknetVersion,
+		L1DataGasPrice:   header.L1DataGasPrice,
+		L1DAMode:         header.L1DAMode,
+	}
+}
+
+// txnJSON returns the JSON of a transaction with its hash.
+func txnJSON(txn Txn) (json.RawMessage, error) {
+	raw, err := json.Marshal(txn.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		fields = map[string]json.RawMessage{}
+	}
+	if fields["transaction_hash"], err = json.Marshal(txn.Hash); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// findTxn returns the block and the index of a transaction.
+func (s *Server) findTxn(hash *felt.Felt) (*Block, int, bool) {
+	blocks := s.blocks
+	if s.pending != nil {
+		blocks = append(blocks[:len(blocks):len(blocks)], s.pending)
+	}
+	for _, block := range blocks {
+		for i, txn := range block.Transactions {
+			if txn.Hash.Equal(hash) {
+				return block, i, true
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+// specVersionMethod answers starknet_specVersion.
+func (s *Server) specVersionMethod(params []json.RawMessage) (interface{}, error) {
+	return s.specVersion, nil
+}
+
+// chainIDMethod answers starknet_chainId with the hex encoded chain id.
+func (s *Server) chainIDMethod(params []json.RawMessage) (interface{}, error) {
+	return encodeChainID(s.chainID), nil
+}
+
+// syncing answers starknet_syncing, the server is never syncing.
+func (s *Server) syncing(params []json.RawMessage) (interface{}, error) {
+	return false, nil
+}
+
+// blockNumber answers starknet_blockNumber.
+func (s *Server) blockNumber(params []json.RawMessage) (interface{}, error) {
+	if len(s.blocks) == 0 {
+		return nil, rpc.ErrNoBlocks
+	}
+	return len(s.blocks) - 1, nil
+}
+
+// blockHashAndNumber answers starknet_blockHashAndNumber.
+func (s *Server) blockHashAndNumber(params []json.RawMessage) (interface{}, error) {
+	if len(s.blocks) == 0 {
+		return nil, rpc.ErrNoBlocks
+	}
+	header := s.blocks[len(s.blocks)-1].Header
+	return rpc.BlockHashAndNumberOutput{BlockNumber: header.BlockNumber, BlockHash: header.BlockHash}, nil
+}
+
+// blockWithTxHashes answers starknet_getBlockWithTxHashes.
+func (s *Server) blockWithTxHashes(params []json.RawMessage) (interface{}, error) {
+	block, err := s.blockParam(params, 0)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]*felt.Felt, len(block.Transactions))
+	for i, txn := range block.Transactions {
+		hashes[i] = txn.Hash
+	}
+	if block == s.pending {
+		return rpc.PendingBlockTxHashes{PendingBlockHeader: s.pendingHeader(), Transactions: hashes}, nil
+	}
+	return rpc.BlockTxHashes{BlockHeader: block.Header, Status: block.Status, Transactions: hashes}, nil
+}
+
+// blockWithTxs answers starknet_getBlockWithTxs.
+func (s *Server) blockWithTxs(params []json.RawMessage) (interface{}, error) {
+	block, err := s.blockParam(params, 0)
+	if err != nil {
+		return nil, err
+	}
+	txns := make([]json.RawMessage, len(block.Transactions))
+	for i, txn := range block.Transactions {
+		if txns[i], err = txnJSON(txn); err != nil {
+			return nil, err
+		}
+	}
+	if block == s.pending {
+		return struct {
+			rpc.PendingBlockHeader
+			Transactions []json.RawMessage `json:"transactions"`
+		}{s.pendingHeader(), txns}, nil
+	}
+	return struct {
+		rpc.BlockHeader
+		Status       rpc.BlockStatus   `json:"status"`
+		Transactions []json.RawMessage `json:"transactions"`
+	}{block.Header, block.Status, txns}, nil
+}
+
+// blockWithReceipts answers starknet_getBlockWithReceipts.
+func (s *Server) blockWithReceipts(params []json.RawMessage) (interface{}, error) {
+	block, err := s.blockParam(params, 0)
+	if err != nil {
+		return nil, err
+	}
+	type txnWithReceipt struct {
+		Transaction json.RawMessage        `json:"transaction"`
+		Receipt     rpc.TransactionReceipt `json:"receipt"`
+	}
+	txns := make([]txnWithReceipt, len(block.Transactions))
+	for i, txn := range block.Transactions {
+		raw, err := txnJSON(txn)
+		if err != nil {
+			return nil, err
+		}
+		txns[i] = txnWithReceipt{Transaction: raw, Receipt: txn.Receipt}
+	}
+	if block == s.pending {
+		return struct {
+			rpc.PendingBlockHeader
+			Transactions []txnWithReceipt `json:"transactions"`
+		}{s.pendingHeader(), txns}, nil
+	}
+	return struct {
+		Status rpc.BlockStatus `json:"status"`
+		rpc.BlockHeader
+		Transactions []txnWithReceipt `json:"transactions"`
+	}{block.Status, block.Header, txns}, nil
+}
+
+// blockTransactionCount answers starknet_getBlockTransactionCount.
+func (s *Server) blockTransactionCount(params []json.RawMessage) (interface{}, error) {
+	block, err := s.blockParam(params, 0)
+	if err != nil {
+		return nil, err
+	}
+	return len(block.Transactions), nil
+}
+
+// stateUpdate answers starknet_getStateUpdate, the old root being the new root of the parent block.
+func (s *Server) stateUpdate(params []json.RawMessage) (interface{}, error) {
+	block, err := s.blockParam(params, 0)
+	if err != nil {
+		return nil, err
+	}
+	oldRoot := new(felt.Felt)
+	if block == s.pending {
+		if len(s.blocks) > 0 {
+			oldRoot = s.blocks[len(s.blocks)-1].Header.NewRoot
+		}
+		return rpc.PendingStateUpdate{OldRoot: oldRoot, StateDiff: block.StateDiff}, nil
+	}
+	if block.Header.BlockNumber > 0 {
+		oldRoot = s.blocks[block.Header.BlockNumber-1].Header.NewRoot
+	}
+	return rpc.StateUpdateOutput{
+		BlockHash:          block.Header.BlockHash,
+		NewRoot:            block.Header.NewRoot,
+		PendingStateUpdate: rpc.PendingStateUpdate{OldRoot: oldRoot, StateDiff: block.StateDiff},
+	}, nil
+}
+
+// transactionByHash answers starknet_getTransactionByHash.
+func (s *Server) transactionByHash(params []json.RawMessage) (interface{}, error) {
+	var hash felt.Felt
+	if err := decodeParams(params, &hash); err != nil {
+		return nil, err
+	}
+	block, index, ok := s.findTxn(&hash)
+	if !ok {
+		return nil, rpc.ErrHashNotFound
+	}
+	return txnJSON(block.Transactions[index])
+}
+
+// transactionByBlockIDAndIndex answers starknet_getTransactionByBlockIdAndIndex.
+func (s *Server) transactionByBlockIDAndIndex(params []json.RawMessage) (interface{}, error) {
+	block, err := s.blockParam(params, 0)
+	if err != nil {
+		return nil, err
+	}
+	var index int
+	if err := decodeParams(params[1:], &index); err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(block.Transactions) {
+		return nil, rpc.ErrInvalidTxnIndex
+	}
+	return txnJSON(block.Transactions[index])
+}
+
+// transactionReceipt answers starknet_getTransactionReceipt.
+func (s *Server) transactionReceipt(params []json.RawMessage) (interface{}, error) {
+	var hash felt.Felt
+	if err := decodeParams(params, &hash); err != nil {
+		return nil, err
+	}
+	block, index, ok := s.findTxn(&hash)
+	if !ok {
+		return nil, rpc.ErrHashNotFound
+	}
+	receipt := rpc.TransactionReceiptWithBlockInfo{TransactionReceipt: block.Transactions[index].Receipt}
+	if block == s.pending {
+		// pending receipts have no block
+		return receipt.TransactionReceipt, nil
+	}
+	receipt.BlockHash, receipt.BlockNumber = block.Header.BlockHash, uint(block.Header.BlockNumber)
+	return &receipt, nil
+}
+
+// transactionStatus answers starknet_getTransactionStatus from the statuses of the receipt.
+func (s *Server) transactionStatus(params []json.RawMessage) (interface{}, error) {
+	var hash felt.Felt
+	if err := decodeParams(params, &hash); err != nil {
+		return nil, err
+	}
+	block, index, ok := s.findTxn(&hash)
+	if !ok {
+		return nil, rpc.ErrHashNotFound
+	}
+	receipt := block.Transactions[index].Receipt
+	return rpc.TxnStatusResp{
+		ExecutionStatus: receipt.ExecutionStatus,
+		FinalityStatus:  rpc.TxnStatus(receipt.FinalityStatus),
+		FailureReason:   receipt.RevertReason,
+	}, nil
+}
+
+// contractParam returns the state of the contract whose address is the
+// parameter at index, after checking the block id parameter at blockIndex.
+func (s *Server) contractParam(params []json.RawMessage, index, blockIndex int) (*contractState, error) {
+	if _, err := s.blockParam(params, blockIndex); err != nil {
+		return nil, err
+	}
+	if len(params) <= index {
+		return nil, invalidParams(fmt.Errorf("missing contract address"))
+	}
+	var address felt.Felt
+	if err := json.Unmarshal(params[index], &address); err != nil {
+		return nil, invalidParams(err)
+	}
+	state, ok := s.states[address]
+	if !ok {
+		return nil, rpc.ErrContractNotFound
+	}
+	return state, nil
+}
+
+// storageAt answers starknet_getStorageAt, the unset slots being zero.
+func (s *Server) storageAt(params []json.RawMessage) (interface{}, error) {
+	state, err := s.contractParam(params, 0, 2)
+	if err != nil {
+		return nil, err
+	}
+	var key felt.Felt
+	if err := decodeParams(params[1:], &key); err != nil {
+		return nil, err
+	}
+	if value, ok := state.storage[key]; ok {
+		return value, nil
+	}
+	return new(felt.Felt), nil
+}
+
+// nonce answers starknet_getNonce.
+func (s *Server) nonce(params []json.RawMessage) (interface{}, error) {
+	state, err := s.contractParam(params, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	return state.nonce, nil
+}
+
+// classHashAt answers starknet_getClassHashAt.
+func (s *Server) classHashAt(params []json.RawMessage) (interface{}, error) {
+	state, err := s.contractParam(params, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	return state.classHash, nil
+}
+
+// class answers starknet_getClass.
+func (s *Server) class(params []json.RawMessage) (interface{}, error) {
+	if _, err := s.blockParam(params, 0); err != nil {
+		return nil, err
+	}
+	var classHash felt.Felt
+	if err := decodeParams(params[1:], &classHash); err != nil {
+		return nil, err
+	}
+	class, ok := s.classes[classHash]
+	if !ok {
+		return nil, rpc.ErrClassHashNotFound
+	}
+	return class, nil
+}
+
+// classAt answers starknet_getClassAt.
+func (s *Server) classAt(params []json.RawMessage) (interface{}, error) {
+	state, err := s.contractParam(params, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	class, ok := s.classes[*state.classHash]
+	if !ok {
+		return nil, rpc.ErrClassHashNotFound
+	}
+	return class, nil
+}
+
+// compiledCasm answers starknet_getCompiledCasm.
+func (s *Server) compiledCasm(params []json.RawMessage) (interface{}, error) {
+	var classHash felt.Felt
+	if err := decodeParams(params, &classHash); err != nil {
+		return nil, err
+	}
+	casm, ok := s.casms[classHash]
+	if !ok {
+		return nil, rpc.ErrClassHashNotFound
+	}
+	return casm, nil
+}
+
+// events answers starknet_getEvents from the events of the receipts. The
+// continuation token is the offset of the next event.
+func (s *Server) events(params []json.RawMessage) (interface{}, error) {
+	var filter struct {
+		FromBlock         *blockID       `json:"from_block"`
+		ToBlock           *blockID       `json:"to_block"`
+		Address           *felt.Felt     `json:"address"`
+		Keys              [][]*felt.Felt `json:"keys"`
+		ChunkSize         int            `json:"chunk_size"`
+		ContinuationToken string         `json:"continuation_token"`
+	}
+	if err := decodeParams(params, &filter); err != nil {
+		return nil, err
+	}
+
+	blocks := s.blocks
+	if s.pending != nil {
+		blocks = append(blocks[:len(blocks):len(blocks)], s.pending)
+	}
+	from, to := 0, len(s.blocks)-1
+	if filter.FromBlock != nil {
+		block, err := s.block(*filter.FromBlock)
+		if err != nil {
+			return nil, err
+		}
+		from = s.position(block)
+	}
+	if filter.ToBlock != nil {
+		block, err := s.block(*filter.ToBlock)
+		if err != nil {
+			return nil, err
+		}
+		to = s.position(block)
+	}
+	offset := 0
+	if filter.ContinuationToken != "" {
+		var err error
+		if offset, err = strconv.Atoi(filter.ContinuationToken); err != nil || offset < 0 {
+			return nil, rpc.ErrInvalidContinuationToken
+		}
+	}
+
+	var matching []rpc.EmittedEvent
+	for i := from; i <= to && i < len(blocks); i++ {
+		block := blocks[i]
+		for _, txn := range block.Transactions {
+			for _, event := range txn.Receipt.Events {
+				if !matchEvent(event, filter.Address, filter.Keys) {
+					continue
+				}
+				emitted := rpc.EmittedEvent{Event: event, TransactionHash: txn.Hash}
+				if block != s.pending {
+					emitted.BlockHash, emitted.BlockNumber = block.Header.BlockHash, block.Header.BlockNumber
+				}
+				matching = append(matching, emitted)
+			}
+		}
+	}
+
+	chunk := rpc.EventChunk{Events: []rpc.EmittedEvent{}}
+	if offset > len(matching) {
+		return nil, rpc.ErrInvalidContinuationToken
+	}
+	end := len(matching)
+	if filter.ChunkSize > 0 && offset+filter.ChunkSize < end {
+		end = offset + filter.ChunkSize
+		chunk.ContinuationToken = strconv.Itoa(end)
+	}
+	chunk.Events = append(chunk.Events, matching[offset:end]...)
+	return chunk, nil
+}
+
+// position returns the position of a block in the chain, the pending block
+// being after the latest one.
+func (s *Server) position(block *Block) int {
+	if block == s.pending {
+		return len(s.blocks)
+	}
+	return int(block.Header.BlockNumber)
+}
+
+// matchEvent reports whether an event matches the address and the keys of
+// a filter, an empty list of keys matching any key.
+func matchEvent(event rpc.Event, address *felt.Felt, keys [][]*felt.Felt) bool {
+	if address != nil && (event.FromAddress == nil || !event.FromAddress.Equal(address)) {
+		return false
+	}
+	for i, accepted := range keys {
+		if len(accepted) == 0 {
+			continue
+		}
+		if i >= len(event.Keys) {
+			return false
+		}
+		found := false
+		for _, key := range accepted {
+			if key.Equal(event.Keys[i]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,481 @@
+package rpctest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// Server is an in-process Starknet JSON-RPC node serving seeded blocks,
+// transactions, classes and contract states over HTTP. rpc.NewProvider can
+// be pointed at its URL.
+//
+// The methods reading the state ignore the block, the state is the one of
+// the latest block. The methods the Server can't compute, such as
+// starknet_call or starknet_addInvokeTransaction, answer with a "method not
+// found" error unless a result is set with Handle or SetResult.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	chainID     string
+	specVersion string
+	blocks      []*Block
+	pending     *Block
+	classes     map[felt.Felt]rpc.ClassOutput
+	casms       map[felt.Felt]*contracts.CasmClass
+	states      map[felt.Felt]*contractState
+	handlers    map[string]HandlerFunc
+	failures    map[string][]*rpc.RPCError
+	requests    []Request
+}
+
+// Block is a block seeded in a Server.
+type Block struct {
+	// Header the header of the block. AddBlock fills the number and the
+	// parent hash, and the hash when empty.
+	Header rpc.BlockHeader
+	// Status the status of the block, ACCEPTED_ON_L2 when empty
+	Status rpc.BlockStatus
+	// Transactions the transactions of the block and their receipts
+	Transactions []Txn
+	// StateDiff the state diff returned by starknet_getStateUpdate
+	StateDiff rpc.StateDiff
+}
+
+// Txn is a transaction of a seeded Block.
+type Txn struct {
+	// Hash the hash of the transaction, derived from its position when empty
+	Hash        *felt.Felt
+	Transaction rpc.Transaction
+	// Receipt the receipt of the transaction. The hash, the type and the
+	// statuses are filled when empty.
+	Receipt rpc.TransactionReceipt
+}
+
+// Request is a JSON-RPC request received by a Server.
+type Request struct {
+	Method string
+	// Params the positional parameters of the request, as a JSON array
+	Params json.RawMessage
+}
+
+// HandlerFunc answers the requests of a method. An *rpc.RPCError error is
+// returned to the client as is, the other errors as internal errors.
+type HandlerFunc func(params json.RawMessage) (interface{}, error)
+
+// contractState is the state of a deployed contract.
+type contractState struct {
+	classHash *felt.Felt
+	nonce     *felt.Felt
+	storage   map[felt.Felt]*felt.Felt
+}
+
+// NewServer starts a Server on a local port, with no blocks, the SN_SEPOLIA
+// chain id and the spec version of the rpc package. The caller must call
+// Close when done.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *Server: the started server
+func NewServer() *Server {
+	s := &Server{
+		chainID:     "SN_SEPOLIA",
+		specVersion: rpc.RPCSpecVersion + ".0",
+		classes:     map[felt.Felt]rpc.ClassOutput{},
+		casms:       map[felt.Felt]*contracts.CasmClass{},
+		states:      map[felt.Felt]*contractState{},
+		handlers:    map[string]HandlerFunc{},
+		failures:    map[string][]*rpc.RPCError{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// SetChainID sets the chain id returned by starknet_chainId.
+//
+// Parameters:
+// - chainID: The chain id, such as "SN_MAIN"
+// Returns:
+//
+//	none
+func (s *Server) SetChainID(chainID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chainID = chainID
+}
+
+// SetSpecVersion sets the version returned by starknet_specVersion.
+//
+// Parameters:
+// - version: The spec version, such as "0.7.1"
+// Returns:
+//
+//	none
+func (s *Server) SetSpecVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.specVersion = version
+}
+
+// AddBlock appends a block to the chain. The number and the parent hash of
+// the header are set from the previous block, the hash defaults to the
+// number plus one.
+//
+// Parameters:
+// - block: The block to append
+// Returns:
+// - rpc.BlockHeader: the completed header of the block
+func (s *Server) AddBlock(block Block) rpc.BlockHeader {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	number := uint64(len(s.blocks))
+	block.Header.BlockNumber = number
+	block.Header.ParentHash = new(felt.Felt)
+	if number > 0 {
+		block.Header.ParentHash = s.blocks[number-1].Header.BlockHash
+	}
+	if block.Header.BlockHash == nil {
+		block.Header.BlockHash = new(felt.Felt).SetUint64(number + 1)
+	}
+	if block.Status == "" {
+		block.Status = rpc.BlockStatus_AcceptedOnL2
+	}
+	s.completeTxns(&block, number)
+	s.blocks = append(s.blocks, &block)
+	return block.Header
+}
+
+// SetPendingBlock sets the pending block, served for the "pending" block
+// tag. Its parent hash is the hash of the latest block.
+//
+// Parameters:
+// - block: The pending block, nil to remove it
+// Returns:
+//
+//	none
+func (s *Server) SetPendingBlock(block *Block) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if block == nil {
+		s.pending = nil
+		return
+	}
+	pending := *block
+	pending.Status = rpc.BlockStatus_Pending
+	s.completeTxns(&pending, uint64(len(s.blocks)))
+	s.pending = &pending
+}
+
+// completeTxns fills the empty hashes and receipt fields of the transactions of a block.
+func (s *Server) completeTxns(block *Block, number uint64) {
+	txns := make([]Txn, len(block.Transactions))
+	for i, txn := range block.Transactions {
+		if txn.Hash == nil {
+			txn.Hash = new(felt.Felt).SetUint64((number+1)<<32 | uint64(i+1))
+		}
+		if txn.Receipt.TransactionHash == nil {
+			txn.Receipt.TransactionHash = txn.Hash
+		}
+		if txn.Receipt.Type == "" && txn.Transaction != nil {
+			txn.Receipt.Type = txn.Transaction.GetType()
+		}
+		if txn.Receipt.ExecutionStatus == "" {
+			txn.Receipt.ExecutionStatus = rpc.TxnExecutionStatusSUCCEEDED
+		}
+		if txn.Receipt.FinalityStatus == "" {
+			txn.Receipt.FinalityStatus = rpc.TxnFinalityStatusAcceptedOnL2
+			if block.Status == rpc.BlockStatus_AcceptedOnL1 {
+				txn.Receipt.FinalityStatus = rpc.TxnFinalityStatusAcceptedOnL1
+			}
+		}
+		txns[i] = txn
+	}
+	block.Transactions = txns
+}
+
+// AddClass declares a class.
+//
+// Parameters:
+// - classHash: The hash of the class
+// - class: The class, a *rpc.ContractClass or a *rpc.DeprecatedContractClass
+// - casm: The compiled class returned by starknet_getCompiledCasm, nil if none
+// Returns:
+//
+//	none
+func (s *Server) AddClass(classHash *felt.Felt, class rpc.ClassOutput, casm *contracts.CasmClass) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.classes[*classHash] = class
+	if casm != nil {
+		s.casms[*classHash] = casm
+	}
+}
+
+// DeployContract deploys a contract of a class, with a zero nonce and an empty storage.
+//
+// Parameters:
+// - address: The address of the contract
+// - classHash: The hash of its class
+// Returns:
+//
+//	none
+func (s *Server) DeployContract(address, classHash *felt.Felt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state(address).classHash = classHash
+}
+
+// SetStorage sets a storage slot of a contract, deploying it with a zero
+// class hash if needed.
+//
+// Parameters:
+// - address: The address of the contract
+// - key: The storage key
+// - value: The value
+// Returns:
+//
+//	none
+func (s *Server) SetStorage(address, key, value *felt.Felt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state(address).storage[*key] = value
+}
+
+// SetNonce sets the nonce of a contract, deploying it with a zero class
+// hash if needed.
+//
+// Parameters:
+// - address: The address of the contract
+// - nonce: The nonce
+// Returns:
+//
+//	none
+func (s *Server) SetNonce(address, nonce *felt.Felt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state(address).nonce = nonce
+}
+
+// state returns the state of a contract, created if needed.
+func (s *Server) state(address *felt.Felt) *contractState {
+	state, ok := s.states[*address]
+	if !ok {
+		state = &contractState{classHash: new(felt.Felt), nonce: new(felt.Felt), storage: map[felt.Felt]*felt.Felt{}}
+		s.states[*address] = state
+	}
+	return state
+}
+
+// Handle sets the handler of a method, replacing the seeded answer if any.
+//
+// Parameters:
+// - method: The method, such as "starknet_call"
+// - handler: The handler, nil to remove it
+// Returns:
+//
+//	none
+func (s *Server) Handle(method string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if handler == nil {
+		delete(s.handlers, method)
+		return
+	}
+	s.handlers[method] = handler
+}
+
+// SetResult answers every request of a method with the same result.
+//
+// Parameters:
+// - method: The method, such as "starknet_estimateFee"
+// - result: The result, marshaled to JSON
+// Returns:
+//
+//	none
+func (s *Server) SetResult(method string, result interface{}) {
+	s.Handle(method, func(params json.RawMessage) (interface{}, error) {
+		return result, nil
+	})
+}
+
+// FailNext answers the next requests of a method with errors, one per
+// request, before going back to the normal answers.
+//
+// Parameters:
+// - method: The method, such as "starknet_getNonce"
+// - errs: The errors, such as rpc.ErrContractNotFound
+// Returns:
+//
+//	none
+func (s *Server) FailNext(method string, errs ...*rpc.RPCError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[method] = append(s.failures[method], errs...)
+}
+
+// Requests returns the requests received so far, in order.
+//
+// Parameters:
+// - methods: The methods to return the requests of, all when empty
+// Returns:
+// - []Request: the received requests
+func (s *Server) Requests(methods ...string) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var requests []Request
+	for _, request := range s.requests {
+		if len(methods) == 0 || contains(methods, request.Method) {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+// ResetRequests forgets the requests received so far.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// jsonrpcRequest is a JSON-RPC 2.0 request.
+type jsonrpcRequest struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// jsonrpcResponse is a JSON-RPC 2.0 response.
+type jsonrpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpc.RPCError   `json:"error,omitempty"`
+}
+
+// serveHTTP answers a JSON-RPC request or batch.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	body = bytes.TrimSpace(body)
+
+	var answer interface{}
+	if len(body) > 0 && body[0] == '[' {
+		var requests []jsonrpcRequest
+		if err := json.Unmarshal(body, &requests); err != nil {
+			answer = jsonrpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpc.RPCError{Code: rpc.InvalidJSON, Message: "Parse error"}}
+		} else {
+			responses := make([]jsonrpcResponse, len(requests))
+			for i, request := range requests {
+				responses[i] = s.answer(request)
+			}
+			answer = responses
+		}
+	} else {
+		var request jsonrpcRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			answer = jsonrpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpc.RPCError{Code: rpc.InvalidJSON, Message: "Parse error"}}
+		} else {
+			answer = s.answer(request)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(answer); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// answer performs a request and builds its response.
+func (s *Server) answer(request jsonrpcRequest) jsonrpcResponse {
+	response := jsonrpcResponse{JSONRPC: "2.0", ID: request.ID}
+	if len(response.ID) == 0 {
+		response.ID = json.RawMessage("null")
+	}
+	params := request.Params
+	if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+		params = json.RawMessage("[]")
+	}
+
+	result, err := s.call(request.Method, params)
+	if err == nil {
+		response.Result, err = json.Marshal(result)
+	}
+	if err != nil {
+		var rpcErr *rpc.RPCError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &rpc.RPCError{Code: rpc.InternalError, Message: err.Error()}
+		}
+		response.Result, response.Error = nil, rpcErr
+	}
+	return response
+}
+
+// call records a request and performs it with the scripted errors, the
+// handlers or the seeded data, in this order.
+func (s *Server) call(method string, params json.RawMessage) (interface{}, error) {
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: method, Params: params})
+	if failures := s.failures[method]; len(failures) > 0 {
+		s.failures[method] = failures[1:]
+		s.mu.Unlock()
+		return nil, failures[0]
+	}
+	if handler, ok := s.handlers[method]; ok {
+		// unlocked, the handler may use the server
+		s.mu.Unlock()
+		return handler(params)
+	}
+	defer s.mu.Unlock()
+
+	fn, ok := methods[method]
+	if !ok {
+		return nil, &rpc.RPCError{Code: rpc.MethodNotFound, Message: fmt.Sprintf("Method not found: rpctest serves no result for %s, set one with Handle or SetResult", method)}
+	}
+	var args []json.RawMessage
+	if err := json.Unmarshal(params, &args); err != nil {
+		return nil, invalidParams(err)
+	}
+	return fn(s, args)
+}
+
+// encodeChainID returns the hex encoding of a chain id.
+func encodeChainID(chainID string) string {
+	return "0x" + hex.EncodeToString([]byte(chainID))
+}
+
+// contains reports whether s is in values.
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package rpctest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// seededServer returns a server with two blocks, the second one holding an
+// invoke transaction emitting two events, and a deployed contract.
+func seededServer(t *testing.T) *Server {
+	server := NewServer()
+	t.Cleanup(server.Close)
+
+	server.AddBlock(Block{Header: rpc.BlockHeader{NewRoot: utils.TestHexToFelt(t, "0xa"), StarknetVersion: "0.13.4"}})
+	server.AddBlock(Block{
+		Header: rpc.BlockHeader{NewRoot: utils.TestHexToFelt(t, "0xb"), StarknetVersion: "0.13.4"},
+		Transactions: []Txn{{
+			Hash: utils.TestHexToFelt(t, "0x123"),
+			Transaction: rpc.InvokeTxnV1{
+				Type:          rpc.TransactionType_Invoke,
+				Version:       rpc.TransactionV1,
+				MaxFee:        utils.TestHexToFelt(t, "0x10"),
+				Nonce:         utils.TestHexToFelt(t, "0x0"),
+				SenderAddress: utils.TestHexToFelt(t, "0x1000"),
+				Signature:     []*felt.Felt{},
+				Calldata:      []*felt.Felt{utils.TestHexToFelt(t, "0x1")},
+			},
+			Receipt: rpc.TransactionReceipt{
+				ActualFee: rpc.FeePayment{Amount: utils.TestHexToFelt(t, "0x5"), Unit: rpc.UnitWei},
+				Events: []rpc.Event{
+					{FromAddress: utils.TestHexToFelt(t, "0x2000"), Keys: []*felt.Felt{utils.TestHexToFelt(t, "0x1")}, Data: []*felt.Felt{}},
+					{FromAddress: utils.TestHexToFelt(t, "0x2000"), Keys: []*felt.Felt{utils.TestHexToFelt(t, "0x2")}, Data: []*felt.Felt{}},
+				},
+			},
+		}},
+	})
+
+	classHash := utils.TestHexToFelt(t, "0xc1a55")
+	server.AddClass(classHash, &rpc.ContractClass{SierraProgram: []*felt.Felt{}, ContractClassVersion: "0.1.0"}, nil)
+	server.DeployContract(utils.TestHexToFelt(t, "0x1000"), classHash)
+	server.SetNonce(utils.TestHexToFelt(t, "0x1000"), utils.TestHexToFelt(t, "0x1"))
+	server.SetStorage(utils.TestHexToFelt(t, "0x1000"), utils.GetSelectorFromNameFelt("balance"), utils.TestHexToFelt(t, "0x64"))
+	return server
+}
+
+// TestServerSeededData tests that a Provider reads the seeded blocks, transactions and states.
+func TestServerSeededData(t *testing.T) {
+	server := seededServer(t)
+	provider, err := rpc.NewProvider(server.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	chainID, err := provider.ChainID(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "SN_SEPOLIA", chainID)
+
+	number, err := provider.BlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), number)
+
+	block, err := provider.TypedBlockWithTxs(ctx, rpc.WithBlockTag("latest"))
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1"), block.Block.ParentHash)
+	require.Len(t, block.Block.Transactions, 1)
+	require.Equal(t, utils.TestHexToFelt(t, "0x123"), block.Block.Transactions[0].Hash())
+
+	txn, err := provider.TransactionByHash(ctx, utils.TestHexToFelt(t, "0x123"))
+	require.NoError(t, err)
+	require.Equal(t, rpc.TransactionType_Invoke, txn.GetType())
+
+	receipt, err := provider.TransactionReceipt(ctx, utils.TestHexToFelt(t, "0x123"))
+	require.NoError(t, err)
+	require.Equal(t, rpc.TxnExecutionStatusSUCCEEDED, receipt.ExecutionStatus)
+	require.Equal(t, uint(1), receipt.BlockNumber)
+
+	update, err := provider.StateUpdate(ctx, rpc.WithBlockNumber(1))
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0xa"), update.OldRoot)
+
+	nonce, err := provider.Nonce(ctx, rpc.WithBlockTag("latest"), utils.TestHexToFelt(t, "0x1000"))
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1"), nonce)
+
+	value, err := provider.StorageAt(ctx, utils.TestHexToFelt(t, "0x1000"), "balance", rpc.WithBlockTag("latest"))
+	require.NoError(t, err)
+	require.Equal(t, "0x64", value)
+
+	class, err := provider.ClassAt(ctx, rpc.WithBlockTag("latest"), utils.TestHexToFelt(t, "0x1000"))
+	require.NoError(t, err)
+	require.Equal(t, "0.1.0", class.(*rpc.ContractClass).ContractClassVersion)
+
+	_, err = provider.Nonce(ctx, rpc.WithBlockTag("latest"), utils.TestHexToFelt(t, "0x9999"))
+	require.ErrorIs(t, err, rpc.ErrContractNotFound)
+	_, err = provider.TypedBlockWithTxHashes(ctx, rpc.WithBlockNumber(5))
+	require.ErrorIs(t, err, rpc.ErrBlockNotFound)
+}
+
+// TestServerEvents tests the filtering and the pagination of the seeded events.
+func TestServerEvents(t *testing.T) {
+	server := seededServer(t)
+	provider, err := rpc.NewProvider(server.URL)
+	require.NoError(t, err)
+
+	input := rpc.EventsInput{
+		EventFilter:       rpc.EventFilter{FromBlock: rpc.WithBlockNumber(0), ToBlock: rpc.WithBlockTag("latest"), Address: utils.TestHexToFelt(t, "0x2000")},
+		ResultPageRequest: rpc.ResultPageRequest{ChunkSize: 1},
+	}
+	chunk, err := provider.Events(context.Background(), input)
+	require.NoError(t, err)
+	require.Len(t, chunk.Events, 1)
+	require.Equal(t, uint64(1), chunk.Events[0].BlockNumber)
+	require.NotEmpty(t, chunk.ContinuationToken)
+
+	input.ContinuationToken = chunk.ContinuationToken
+	chunk, err = provider.Events(context.Background(), input)
+	require.NoError(t, err)
+	require.Len(t, chunk.Events, 1)
+	require.Equal(t, utils.TestHexToFelt(t, "0x2"), chunk.Events[0].Keys[0])
+	require.Empty(t, chunk.ContinuationToken)
+
+	input = rpc.EventsInput{
+		EventFilter:       rpc.EventFilter{FromBlock: rpc.WithBlockNumber(0), ToBlock: rpc.WithBlockTag("latest"), Keys: [][]*felt.Felt{{utils.TestHexToFelt(t, "0x2")}}},
+		ResultPageRequest: rpc.ResultPageRequest{ChunkSize: 10},
+	}
+	chunk, err = provider.Events(context.Background(), input)
+	require.NoError(t, err)
+	require.Len(t, chunk.Events, 1)
+}
+
+// TestServerScripting tests the scripted errors, the handlers and the recorded requests.
+func TestServerScripting(t *testing.T) {
+	server := seededServer(t)
+	provider, err := rpc.NewProvider(server.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+	address := utils.TestHexToFelt(t, "0x1000")
+
+	server.FailNext("starknet_getNonce", rpc.ErrBlockNotFound)
+	_, err = provider.Nonce(ctx, rpc.WithBlockTag("latest"), address)
+	require.ErrorIs(t, err, rpc.ErrBlockNotFound)
+	_, err = provider.Nonce(ctx, rpc.WithBlockTag("latest"), address)
+	require.NoError(t, err)
+
+	_, err = provider.Call(ctx, rpc.FunctionCall{ContractAddress: address, EntryPointSelector: utils.GetSelectorFromNameFelt("get_balance"), Calldata: []*felt.Felt{}}, rpc.WithBlockTag("latest"))
+	var rpcErr *rpc.RPCError
+	require.True(t, errors.As(err, &rpcErr))
+	require.Equal(t, rpc.MethodNotFound, rpcErr.Code)
+
+	server.SetResult("starknet_call", []*felt.Felt{utils.TestHexToFelt(t, "0x64")})
+	result, err := provider.Call(ctx, rpc.FunctionCall{ContractAddress: address, EntryPointSelector: utils.GetSelectorFromNameFelt("get_balance"), Calldata: []*felt.Felt{}}, rpc.WithBlockTag("latest"))
+	require.NoError(t, err)
+	require.Equal(t, []*felt.Felt{utils.TestHexToFelt(t, "0x64")}, result)
+
+	requests := server.Requests("starknet_getNonce")
+	require.Len(t, requests, 2)
+	var params []json.RawMessage
+	require.NoError(t, json.Unmarshal(requests[1].Params, &params))
+	require.JSONEq(t, `"latest"`, string(params[0]))
+	require.JSONEq(t, `"0x1000"`, string(params[1]))
+	// the spec version is only queried for the calls depending on it
+	require.Empty(t, server.Requests("starknet_specVersion"))
+
+	server.ResetRequests()
+	require.Empty(t, server.Requests())
+}
+
+// TestServerBatch tests that the batches are answered.
+func TestServerBatch(t *testing.T) {
+	server := seededServer(t)
+	provider, err := rpc.NewProvider(server.URL)
+	require.NoError(t, err)
+
+	var number uint64
+	var nonce *felt.Felt
+	batch := provider.NewBatch()
+	numberCall := batch.BlockNumber(&number)
+	nonceCall := batch.Nonce(rpc.WithBlockTag("latest"), utils.TestHexToFelt(t, "0x1000"), &nonce)
+	require.NoError(t, batch.Send(context.Background()))
+	require.NoError(t, numberCall.Err())
+	require.NoError(t, nonceCall.Err())
+	require.Equal(t, uint64(1), number)
+	require.Equal(t, utils.TestHexToFelt(t, "0x1"), nonce)
+}
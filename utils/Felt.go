@@ -1,11 +1,8 @@
 package utils
 
 import (
-	"encoding/hex"
 	"fmt"
 	"math/big"
-	"regexp"
-	"strings"
 
 	"github.com/NethermindEth/juno/core/felt"
 )
@@ -112,37 +109,18 @@ func FeltArrToBigIntArr(f []*felt.Felt) []*big.Int {
 // [article]: https://docs.starknet.io/architecture-and-concepts/smart-contracts/serialization-of-cairo-types/#serialization_of_byte_arrays
 func StringToByteArrFelt(s string) ([]*felt.Felt, error) {
 	const SHORT_LENGTH = 31
-	exp := fmt.Sprintf(".{1,%d}", SHORT_LENGTH)
-	r := regexp.MustCompile(exp)
+	data := []byte(s)
 
-	arr := r.FindAllString(s, -1)
-	if len(arr) == 0 {
-		return []*felt.Felt{}, fmt.Errorf("invalid string no matches found, s: %s", s)
+	count := len(data) / SHORT_LENGTH
+	res := make([]*felt.Felt, 0, count+3)
+	res = append(res, new(felt.Felt).SetUint64(uint64(count)))
+	for i := 0; i < count; i++ {
+		res = append(res, new(felt.Felt).SetBytes(data[i*SHORT_LENGTH:(i+1)*SHORT_LENGTH]))
 	}
 
-	hexarr := []string{}
-	var count, size uint64
-
-	for _, val := range arr {
-		if len(val) == SHORT_LENGTH {
-			count += 1
-		} else {
-			size = uint64(len(val))
-		}
-		hexarr = append(hexarr, "0x"+hex.EncodeToString([]byte(val)))
-	}
-
-	harr, err := HexArrToFelt(hexarr)
-	if err != nil {
-		return nil, err
-	}
-
-	if size == 0 {
-		harr = append(harr, new(felt.Felt).SetUint64(0))
-	}
-
-	harr = append(harr, new(felt.Felt).SetUint64(size))
-	return append([]*felt.Felt{new(felt.Felt).SetUint64(count)}, harr...), nil
+	pendingWord := data[count*SHORT_LENGTH:]
+	res = append(res, new(felt.Felt).SetBytes(pendingWord), new(felt.Felt).SetUint64(uint64(len(pendingWord))))
+	return res, nil
 }
 
 // ByteArrFeltToString converts array of Felts to string.
@@ -150,6 +128,7 @@ func StringToByteArrFelt(s string) ([]*felt.Felt, error) {
 //
 // [number of felts with 31 characters in length, 31 byte felts..., pending word with max size of 30 bytes, pending words bytes size]
 //
+// The felts following the byte array are ignored, see ByteArrFeltToStringExact.
 // For further explanation, refer the [article]
 //
 // Parameters:
@@ -164,41 +143,75 @@ func StringToByteArrFelt(s string) ([]*felt.Felt, error) {
 //
 // [article]: https://docs.starknet.io/architecture-and-concepts/smart-contracts/serialization-of-cairo-types/#serialization_of_byte_arrays
 func ByteArrFeltToString(arr []*felt.Felt) (string, error) {
+	s, _, err := byteArrFeltPrefixToString(arr)
+	return s, err
+}
+
+// ByteArrFeltToStringExact converts array of Felts to string like
+// ByteArrFeltToString, but fails if felts follow the byte array. It suits
+// the decoders reading the felts of a single byte array.
+//
+// Parameters:
+//
+// - []*felt.Felt: the felts of the byte array
+//
+// Returns:
+//
+// - s: string/bytearray
+//
+// - error: an error, if any
+func ByteArrFeltToStringExact(arr []*felt.Felt) (string, error) {
+	s, n, err := byteArrFeltPrefixToString(arr)
+	if err != nil {
+		return "", err
+	}
+	if n != len(arr) {
+		return "", fmt.Errorf("invalid felt array, %d elements after the byte array", len(arr)-n)
+	}
+	return s, nil
+}
+
+// byteArrFeltPrefixToString converts the byte array at the start of arr to
+// a string and returns the number of felts it spans.
+func byteArrFeltPrefixToString(arr []*felt.Felt) (string, int, error) {
+	const SHORT_LENGTH = 31
 	if len(arr) < 3 {
-		return "", fmt.Errorf("invalid felt array, require atleast 3 elements in array")
+		return "", 0, fmt.Errorf("invalid felt array, require atleast 3 elements in array")
 	}
 
-	count := FeltToBigInt(arr[0]).Uint64()
-	var index uint64
-	var res []string
-	for index = 0; index < count; index++ {
-		f := arr[1+index]
-		s, err := feltToString(f)
+	count := FeltToBigInt(arr[0])
+	if !count.IsUint64() || count.Uint64() > uint64(len(arr)-3) {
+		return "", 0, fmt.Errorf("invalid felt array, %s full words for %d elements", count, len(arr))
+	}
+	n := int(count.Uint64()) + 3
+	var res []byte
+	for _, word := range arr[1 : n-2] {
+		b, err := feltToBytes(word, SHORT_LENGTH)
 		if err != nil {
-			return "", err
+			return "", 0, err
 		}
-		res = append(res, s)
+		res = append(res, b...)
 	}
 
-	pendingWordLength := arr[len(arr)-1]
-	if pendingWordLength.IsZero() {
-		return strings.Join(res, ""), nil
+	pendingWordLength := FeltToBigInt(arr[n-1])
+	if !pendingWordLength.IsUint64() || pendingWordLength.Uint64() >= SHORT_LENGTH {
+		return "", 0, fmt.Errorf("invalid pending word length %s", pendingWordLength)
 	}
-
-	pendingWordFelt := arr[1+index]
-	s, err := feltToString(pendingWordFelt)
+	b, err := feltToBytes(arr[n-2], int(pendingWordLength.Uint64()))
 	if err != nil {
-		return "", fmt.Errorf("invalid pending word")
+		return "", 0, fmt.Errorf("invalid pending word")
 	}
-
-	res = append(res, s)
-	return strings.Join(res, ""), nil
+	return string(append(res, b...)), n, nil
 }
 
-func feltToString(f *felt.Felt) (string, error) {
-	b, err := hex.DecodeString(f.String()[2:])
-	if err != nil {
-		return "", fmt.Errorf("unable to decode to string")
+// feltToBytes returns the size last bytes of the big-endian encoding of the
+// felt, failing if the felt doesn't fit in them.
+func feltToBytes(f *felt.Felt, size int) ([]byte, error) {
+	b := f.Bytes()
+	for _, c := range b[:len(b)-size] {
+		if c != 0 {
+			return nil, fmt.Errorf("the felt %s doesn't fit in %d bytes", f, size)
+		}
 	}
-	return string(b), nil
+	return b[len(b)-size:], nil
 }
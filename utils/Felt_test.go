@@ -39,6 +39,18 @@ func TestStringToByteArrFelt(t *testing.T) {
 			in:  "12345678901234567890123456789012",
 			out: []string{"0x1", "0x31323334353637383930313233343536373839303132333435363738393031", "0x32", "0x1"},
 		},
+		{
+			in:  "",
+			out: []string{"0x0", "0x0", "0x0"},
+		},
+		{
+			in:  "éééééééééééééééé",
+			out: []string{"0x1", "0xc3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3", "0xa9", "0x1"},
+		},
+		{
+			in:  "\x00a",
+			out: []string{"0x0", "0x61", "0x2"},
+		},
 	}
 
 	for _, tc := range tests {
@@ -85,6 +97,18 @@ func TestByteArrFeltToString(t *testing.T) {
 			in:  []string{"0x1", "0x31323334353637383930313233343536373839303132333435363738393031", "0x32", "0x1"},
 			out: "12345678901234567890123456789012",
 		},
+		{
+			in:  []string{"0x0", "0x0", "0x0"},
+			out: "",
+		},
+		{
+			in:  []string{"0x1", "0xc3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3a9c3", "0xa9", "0x1"},
+			out: "éééééééééééééééé",
+		},
+		{
+			in:  []string{"0x0", "0x61", "0x2"},
+			out: "\x00a",
+		},
 	}
 
 	for _, tc := range tests {
@@ -93,5 +117,16 @@ func TestByteArrFeltToString(t *testing.T) {
 		res, err := ByteArrFeltToString(in)
 		require.NoError(t, err, "error returned from ByteArrFeltToString")
 		require.Equal(t, tc.out, res, "invalid conversion: output does not match")
+		res, err = ByteArrFeltToStringExact(in)
+		require.NoError(t, err, "error returned from ByteArrFeltToStringExact")
+		require.Equal(t, tc.out, res, "invalid conversion: output does not match")
+
+		// the felts following the byte array are only rejected by ByteArrFeltToStringExact
+		longer := append(in, in[0])
+		res, err = ByteArrFeltToString(longer)
+		require.NoError(t, err, "error returned from ByteArrFeltToString")
+		require.Equal(t, tc.out, res, "invalid conversion: output does not match")
+		_, err = ByteArrFeltToStringExact(longer)
+		require.Error(t, err)
 	}
 }